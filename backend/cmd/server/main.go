@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"arguehub/config"
 	"arguehub/db"
@@ -25,9 +26,10 @@ func main() {
 		panic("Failed to load config: " + err.Error())
 	}
 
-	services.InitDebateVsBotService(cfg)
+	services.InitLLMProviders(cfg)
 	services.InitCoachService()
 	services.InitRatingService(cfg)
+	services.InitTranscriptionService(cfg)
 
 	// Connect to MongoDB using the URI from the configuration
 	if err := db.ConnectMongoDB(cfg.Database.URI); err != nil {
@@ -35,6 +37,12 @@ func main() {
 	}
 	log.Println("Connected to MongoDB")
 
+	// Bot personalities are validated here so a broken definition stops startup
+	if err := services.InitBotPersonalities(cfg.Bots.PersonalityDir); err != nil {
+		log.Fatalf("Failed to load bot personalities: %v", err)
+	}
+	go services.WatchBotPersonalities()
+
 	// Initialize Casbin RBAC
 	if err := middlewares.InitCasbin("./config/config.prod.yml"); err != nil {
 		log.Fatalf("Failed to initialize Casbin: %v", err)
@@ -61,6 +69,29 @@ func main() {
 
 	utils.SetJWTSecret(cfg.JWT.Secret)
 
+	// Spectator identities are HMACs keyed by a server secret so clients cannot forge them
+	if err := debate.SetSpectatorSecret(cfg.Spectator.IdentitySecret); err != nil {
+		log.Fatalf("Invalid spectator config: %v", err)
+	}
+	debate.SetSpectatorLimits(debate.SpectatorLimits{
+		MaxConnectionsPerIP:     cfg.Spectator.MaxConnectionsPerIP,
+		MaxConnectionsPerDevice: cfg.Spectator.MaxConnectionsPerDevice,
+		MaxVotesPerIP:           cfg.Spectator.MaxVotesPerIP,
+	})
+	debate.SetChatConfig(debate.ChatConfig{
+		HistoryLength: cfg.Spectator.ChatHistoryLength,
+		BlockedWords:  cfg.Spectator.ChatBlockedWords,
+	})
+
+	rateLimits := make(map[string]debate.RateLimitPolicy, len(cfg.RateLimits))
+	for action, policy := range cfg.RateLimits {
+		rateLimits[action] = debate.RateLimitPolicy{
+			Limit:  policy.Limit,
+			Window: time.Duration(policy.WindowSeconds) * time.Second,
+		}
+	}
+	debate.SetRateLimitPolicies(rateLimits)
+
 	// Seed initial debate-related data
 	utils.SeedDebateData()
 	utils.PopulateTestUsers()
@@ -107,6 +138,8 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 
 	// WebSocket routes (handle auth internally)
 	router.GET("/ws/matchmaking", websocket.MatchmakingHandler)
+	// Streams debate bot replies as they are generated (handles auth internally)
+	router.GET("/ws/vsbot", websocket.VsBotStreamHandler)
 	router.GET("/ws/gamification", websocket.GamificationWebSocketHandler)
 
 	// Protected routes (JWT auth)
@@ -134,6 +167,9 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 		auth.GET("/coach/strengthen-argument/weak-statement", routes.GetWeakStatement)
 		auth.POST("/coach/strengthen-argument/evaluate", routes.EvaluateStrengthenedArgument)
 
+		routes.SetupAIUsageRoutes(auth)
+		routes.SetupCustomBotRoutes(auth)
+
 		// Add Room routes.
 		auth.GET("/rooms", routes.GetRoomsHandler)
 		auth.POST("/rooms", routes.CreateRoomHandler)
@@ -164,5 +200,8 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 	// Debate spectator WebSocket handler (no auth required for anonymous spectators)
 	router.GET("/ws/debate/:debateID", DebateWebsocketHandler)
 
+	// Directory of public debates running now, open to anonymous spectators
+	routes.SetupLiveDebateRoutes(router)
+
 	return router
 }
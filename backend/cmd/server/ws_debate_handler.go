@@ -1,14 +1,22 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"arguehub/db"
 	"arguehub/internal/debate"
+	"arguehub/internal/wsconn"
+	"arguehub/models"
+	"arguehub/services"
+	"arguehub/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,6 +29,9 @@ var debateUpgrader = websocket.Upgrader{
 	},
 }
 
+// debateHubMetrics tracks connections to the /ws/debate/:debateID spectator hub
+var debateHubMetrics = wsconn.HubMetrics("/ws/debate")
+
 // DebateHub manages WebSocket connections for spectators
 type DebateHub struct {
 	debates map[string]*DebateRoom
@@ -32,16 +43,23 @@ type DebateRoom struct {
 	debateID string
 	clients  map[*websocket.Conn]*SpectatorClient
 	mu       sync.RWMutex
-	consumer *debate.StreamConsumer
 }
 
 // SpectatorClient represents a connected spectator
 type SpectatorClient struct {
 	conn          *websocket.Conn
-	writeMu       sync.Mutex
+	ws            *wsconn.Conn
 	spectatorHash string
-	lastEventID   string
 	debateID      string
+	userID        string // Set when the spectator connected with a valid JWT
+	clientIP      string
+	debater       bool // One of the debate's debaters watching the spectator feed
+
+	mu          sync.Mutex      // Guards the stream delivery state below
+	lastEventID string          // Newest stream ID delivered to this spectator
+	replaying   bool            // Live events are held in pending while missed events are replayed
+	replayed    bool            // A connection is replayed at most once
+	pending     []*debate.Event // Live events received during the replay
 }
 
 // NewDebateHub creates a new DebateHub
@@ -53,9 +71,10 @@ func NewDebateHub() *DebateHub {
 }
 
 // Register registers a new WebSocket connection for a debate
-func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHash string) *SpectatorClient {
+func (h *DebateHub) Register(debateID string, ws *wsconn.Conn, spectatorHash, userID, clientIP string, debater bool) *SpectatorClient {
+	conn := ws.Underlying()
+
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	// Get or create debate room
 	room, exists := h.debates[debateID]
@@ -63,29 +82,32 @@ func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHas
 		room = &DebateRoom{
 			debateID: debateID,
 			clients:  make(map[*websocket.Conn]*SpectatorClient),
-			consumer: debate.NewStreamConsumer(h),
 		}
 		h.debates[debateID] = room
 
-		// Start consumer group for this debate (only if Redis is available)
-		if room.consumer != nil {
-			go room.consumer.StartConsumerGroup(debateID)
-		}
+		// Receive the debate's events from the stream: a Redis consumer group, or the
+		// in-process stream in a single-instance deployment
+		debate.GetEventStream().Subscribe(debateID, h)
 	}
 
 	// Create client
 	client := &SpectatorClient{
 		conn:          conn,
+		ws:            ws,
 		spectatorHash: spectatorHash,
 		debateID:      debateID,
+		userID:        userID,
+		clientIP:      clientIP,
+		debater:       debater,
 	}
 
 	room.mu.Lock()
 	room.clients[conn] = client
 	clientCount := len(room.clients)
 	room.mu.Unlock()
+	h.mu.Unlock()
 
-	// Broadcast presence update
+	// Broadcast presence update once the hub is unlocked, as BroadcastPresence locks it
 	presenceEvent := map[string]interface{}{
 		"type": "presence",
 		"payload": map[string]interface{}{
@@ -98,13 +120,13 @@ func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHas
 	return client
 }
 
-// Unregister removes a WebSocket connection
+// Unregister removes a WebSocket connection. When the last spectator leaves, the room is
+// dropped and the hub stops receiving the debate's events.
 func (h *DebateHub) Unregister(debateID string, conn *websocket.Conn) {
-	h.mu.RLock()
+	h.mu.Lock()
 	room, exists := h.debates[debateID]
-	h.mu.RUnlock()
-
 	if !exists {
+		h.mu.Unlock()
 		return
 	}
 
@@ -113,6 +135,14 @@ func (h *DebateHub) Unregister(debateID string, conn *websocket.Conn) {
 	clientCount := len(room.clients)
 	room.mu.Unlock()
 
+	if clientCount == 0 {
+		delete(h.debates, debateID)
+		debate.GetEventStream().Unsubscribe(debateID, h)
+		h.mu.Unlock()
+		return
+	}
+	h.mu.Unlock()
+
 	// Broadcast presence update
 	presenceEvent := map[string]interface{}{
 		"type": "presence",
@@ -124,6 +154,14 @@ func (h *DebateHub) Unregister(debateID string, conn *websocket.Conn) {
 	h.BroadcastPresence(debateID, presenceEvent)
 }
 
+// addLiveSpectators updates the debate's spectator count in the live directory, which is
+// shared by every instance
+func addLiveSpectators(debateID string, delta int64) {
+	if err := debate.NewLiveRegistry().AddSpectators(debateID, delta); err != nil {
+		log.Printf("Failed to update spectator count: debate=%s err=%v", debateID, err)
+	}
+}
+
 // BroadcastToDebate broadcasts an event to all connected clients for a debate
 func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 	h.mu.RLock()
@@ -141,7 +179,15 @@ func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 	}
 	room.mu.RUnlock()
 
-	// Convert event to frontend format - parse payload
+	// Broadcast to all clients
+	for _, client := range clients {
+		client.deliver(event)
+	}
+}
+
+// eventMessage converts a stream event to the frontend format, carrying its stream ID
+func eventMessage(event *debate.Event) map[string]interface{} {
+	// Parse payload so it is sent as JSON rather than a string
 	var payload interface{}
 	if err := json.Unmarshal(event.Payload, &payload); err != nil {
 		// If unmarshal fails, use raw payload
@@ -153,12 +199,10 @@ func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 		"payload":   payload,
 		"timestamp": event.Timestamp,
 	}
-
-	// Broadcast to all clients
-	for _, client := range clients {
-		if err := client.WriteJSON(eventData); err != nil {
-		}
+	if event.ID != "" {
+		eventData["id"] = event.ID
 	}
+	return eventData
 }
 
 // BroadcastPresence broadcasts a presence update directly
@@ -185,11 +229,93 @@ func (h *DebateHub) BroadcastPresence(debateID string, presenceEvent map[string]
 	}
 }
 
-// WriteJSON safely writes JSON to the WebSocket connection
+// WriteJSON queues JSON for the WebSocket connection
 func (c *SpectatorClient) WriteJSON(v interface{}) error {
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
-	return c.conn.WriteJSON(v)
+	return c.ws.WriteJSON(v)
+}
+
+// deliver sends a live stream event to the spectator. Events that arrive while missed
+// events are being replayed are held back and sent once the replay finishes.
+func (c *SpectatorClient) deliver(event *debate.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replaying {
+		c.pending = append(c.pending, event)
+		return
+	}
+	c.sendLocked(event, false)
+}
+
+// sendLocked writes a stream event unless the spectator has already received it.
+// The caller must hold c.mu.
+func (c *SpectatorClient) sendLocked(event *debate.Event, replay bool) bool {
+	if event.ID != "" {
+		if debate.CompareEventIDs(event.ID, c.lastEventID) <= 0 {
+			return false
+		}
+		c.lastEventID = event.ID
+	}
+	if event.Type == "commentary" && !c.receivesCommentary() {
+		return false
+	}
+	eventData := eventMessage(event)
+	if replay {
+		eventData["replay"] = true
+	}
+	c.WriteJSON(eventData)
+	return true
+}
+
+// receivesCommentary reports whether the spectator is sent the AI commentary. It would
+// coach whoever is speaking, so it goes to logged-in spectators who are not debating; a
+// debater could otherwise read it by connecting without logging in.
+func (c *SpectatorClient) receivesCommentary() bool {
+	return c.userID != "" && !c.debater
+}
+
+// replayMissedEvents sends a reconnecting spectator the questions, reactions and poll
+// events stored after lastEventID, then switches it to live delivery. Live events that
+// arrive meanwhile are buffered, so nothing is lost or sent twice across the switch.
+func replayMissedEvents(client *SpectatorClient, lastEventID string) {
+	client.mu.Lock()
+	if client.replayed || lastEventID == "" {
+		client.mu.Unlock()
+		return
+	}
+	client.replayed = true
+	client.replaying = true
+	client.mu.Unlock()
+
+	events, _ := debate.ReadEventsSince(client.debateID, lastEventID)
+
+	client.mu.Lock()
+	if debate.CompareEventIDs(lastEventID, client.lastEventID) > 0 {
+		client.lastEventID = lastEventID
+	}
+	pollChanged := false
+	for _, event := range events {
+		if !debate.IsReplayableEvent(event.Type) {
+			continue
+		}
+		if client.sendLocked(event, true) && (event.Type == "vote" || event.Type == "poll_created") {
+			pollChanged = true
+		}
+	}
+	pending := client.pending
+	client.pending = nil
+	client.replaying = false
+	for _, event := range pending {
+		client.sendLocked(event, false)
+	}
+	client.mu.Unlock()
+
+	// Replayed votes are relative to an older tally, so follow them with current counts
+	if pollChanged {
+		if snapshot, err := loadPollSnapshot(client.debateID); err == nil && snapshot != nil {
+			client.WriteJSON(snapshot)
+		}
+	}
 }
 
 // DebateWebsocketHandler handles WebSocket connections for debate spectators
@@ -201,40 +327,99 @@ func DebateWebsocketHandler(c *gin.Context) {
 		return
 	}
 
+	// Spectators may authenticate with the same JWT as the rest of the API. An expired
+	// token downgrades the connection to an anonymous spectator; a token that does not
+	// verify, or names no user, is rejected.
+	userID := ""
+	if token := spectatorToken(c); token != "" {
+		claims, err := utils.ParseJWTToken(token)
+		switch {
+		case errors.Is(err, utils.ErrTokenExpired):
+		case err != nil:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		default:
+			if userID, err = spectatorUserID(claims); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+				return
+			}
+		}
+	}
+
+	// Logged-in spectators are identified by their account. Anonymous ones by their device
+	// ID, or a fresh ID for this connection. Either way the hash is an HMAC with a server
+	// secret, so it can neither be forged nor reused from another spectator's hash.
+	deviceID := c.Query("spectatorId")
+	if userID == "" && deviceID == "" {
+		deviceID = uuid.New().String()
+	}
+	spectatorHash := debate.SpectatorHash(userID, deviceID)
+	clientIP := c.ClientIP()
+
+	// Anonymous spectators are limited per IP and per device
+	if userID == "" {
+		admitted, err := debate.AcquireSpectatorConnection(debateID, clientIP, spectatorHash)
+		if err == nil && !admitted {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many spectator connections"})
+			return
+		}
+		if err == nil {
+			defer debate.ReleaseSpectatorConnection(debateID, clientIP, spectatorHash)
+		}
+	}
+
+	// Debaters may watch their own debate's spectator feed, minus the commentary
+	debater := false
+	if userID != "" {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		debater = isDebateParticipant(ctx, debateID, userID)
+		cancel()
+	}
+
 	// Upgrade connection
 	conn, err := debateUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		return
 	}
-	defer conn.Close()
-
-	// Get or generate spectator hash
-	spectatorID := c.Query("spectatorId")
-
-	var spectatorHash string
-	if spectatorID != "" {
-		// Hash the spectator ID
-		h := sha256.Sum256([]byte(spectatorID))
-		spectatorHash = hex.EncodeToString(h[:])
-	} else {
-		// Generate ephemeral ID
-		ephemeralID := uuid.New().String()
-		h := sha256.Sum256([]byte(ephemeralID))
-		spectatorHash = hex.EncodeToString(h[:])
-	}
+	wc := wsconn.New(conn, debateHubMetrics, wsconn.DefaultConfig())
+	defer wc.Close()
 
 	// Register client
 	hub := GetDebateHub()
-	client := hub.Register(debateID, conn, spectatorHash)
+	client := hub.Register(debateID, wc, spectatorHash, userID, clientIP, debater)
 	defer hub.Unregister(debateID, conn)
+	addLiveSpectators(debateID, 1)
+	defer addLiveSpectators(debateID, -1)
+
+	// Tell the spectator how it is identified so the client can offer to log in
+	client.WriteJSON(map[string]interface{}{
+		"type": "identity",
+		"payload": map[string]interface{}{
+			"authenticated": userID != "",
+			"commentary":    client.receivesCommentary(),
+		},
+		"timestamp": time.Now().Unix(),
+	})
 
 	// Send initial poll snapshot
 	snapshot, err := loadPollSnapshot(debateID)
 	if err == nil && snapshot != nil {
-		conn.WriteJSON(snapshot)
+		client.WriteJSON(snapshot)
 	} else if err != nil {
 	}
 
+	// Late joiners see the recent chat and its rules
+	if messages, settings, err := services.GetSpectatorChat(debateID); err == nil {
+		client.WriteJSON(map[string]interface{}{
+			"type": "chat_history",
+			"payload": map[string]interface{}{
+				"messages": messages,
+				"settings": settings,
+			},
+			"timestamp": time.Now().Unix(),
+		})
+	}
+
 	// Send initial presence count - get it from the hub after registration
 	hub.mu.RLock()
 	room, exists := hub.debates[debateID]
@@ -254,21 +439,57 @@ func DebateWebsocketHandler(c *gin.Context) {
 		},
 		"timestamp": time.Now().Unix(),
 	}
-	conn.WriteJSON(presenceEvent)
+	client.WriteJSON(presenceEvent)
+
+	// A reconnecting spectator can pass the last stream ID it saw to catch up on missed events
+	replayMissedEvents(client, c.Query("lastEventId"))
+
+	// Read until the spectator disconnects; the deferred Unregister and Close clean up
+	readPump(client, hub)
+}
+
+// lookupUserID returns the ID of the user with an email address
+var lookupUserID = func(email string) (string, error) {
+	if db.MongoDatabase == nil {
+		return "", errors.New("database not initialized")
+	}
+	id, err := utils.GetUserIDFromEmail(email)
+	if err != nil {
+		return "", err
+	}
+	return id.Hex(), nil
+}
 
-	// Read pump
-	go readPump(client, hub)
+// isDebateParticipant reports whether a user is debating in a debate
+var isDebateParticipant = services.IsDebateParticipant
 
-	// Keep connection alive
-	select {}
+// spectatorUserID resolves the user behind a verified token. Tokens issued at login carry
+// only the user's email in sub, so the user is looked up as the auth middleware does.
+func spectatorUserID(claims *utils.Claims) (string, error) {
+	email := claims.Sub
+	if email == "" {
+		email = claims.Email
+	}
+	if email == "" {
+		return "", utils.ErrInvalidToken
+	}
+	return lookupUserID(email)
+}
+
+// spectatorToken reads an optional JWT from the Authorization header or the token query
+func spectatorToken(c *gin.Context) string {
+	if token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); token != "" {
+		return token
+	}
+	return c.Query("token")
 }
 
 // readPump handles incoming messages from client
 func readPump(client *SpectatorClient, hub *DebateHub) {
-	defer client.conn.Close()
+	defer client.ws.Close()
 
 	for {
-		_, messageBytes, err := client.conn.ReadMessage()
+		_, messageBytes, err := client.ws.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 			} else {
@@ -285,14 +506,31 @@ func readPump(client *SpectatorClient, hub *DebateHub) {
 		// Handle different message types
 		switch clientMsg.Type {
 		case "join":
+			var join debate.JoinPayload
+			if err := json.Unmarshal(clientMsg.Payload, &join); err == nil {
+				replayMissedEvents(client, join.LastEventID)
+			}
 		case "vote":
 			handleVote(client, clientMsg.Payload)
 		case "question":
 			handleQuestion(client, clientMsg.Payload)
+		case "upvote_question", "upvoteQuestion":
+			handleUpvoteQuestion(client, clientMsg.Payload)
 		case "reaction":
 			handleReaction(client, clientMsg.Payload)
+		case "chat":
+			handleChat(client, clientMsg.Payload)
+		case "chat_moderate":
+			handleChatModerate(client, clientMsg.Payload)
+		case "chat_settings":
+			handleChatSettings(client, clientMsg.Payload)
 		case "createPoll", "create_poll":
-			handleCreatePoll(client, clientMsg.Payload)
+			// Polls are created by the host or a moderator through the REST API
+			client.WriteJSON(map[string]interface{}{
+				"type":      "poll_error",
+				"payload":   map[string]interface{}{"error": "Only the debate host or a moderator can create polls"},
+				"timestamp": time.Now().Unix(),
+			})
 		default:
 		}
 	}
@@ -305,13 +543,34 @@ func handleVote(client *SpectatorClient, payloadBytes []byte) {
 		return
 	}
 
-	// Set spectator hash and timestamp
-	payload.SpectatorHash = client.spectatorHash
 	if payload.ClientEventID == "" {
 		payload.ClientEventID = uuid.New().String()
 	}
 	payload.Timestamp = time.Now().Unix()
 
+	// The motion polls can decide the winner, so they take one vote per account from
+	// spectators who are not debating
+	if services.IsMotionPoll(payload.PollID) {
+		if client.userID == "" {
+			sendPollError(client, payload.PollID, "Log in to vote on the motion")
+			return
+		}
+		if client.debater {
+			sendPollError(client, payload.PollID, "Debaters cannot vote on their own motion")
+			return
+		}
+	}
+
+	if client.userID == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		requireLogin := services.RequiresLoggedInSpectators(ctx, client.debateID)
+		cancel()
+		if requireLogin {
+			sendPollError(client, payload.PollID, "Log in to vote in this debate")
+			return
+		}
+	}
+
 	// Check rate limit
 	rateLimiter := debate.NewRateLimiter()
 	canVote, err := rateLimiter.CheckVoteRateLimit(client.debateID, payload.PollID, client.spectatorHash)
@@ -319,9 +578,33 @@ func handleVote(client *SpectatorClient, payloadBytes []byte) {
 		return
 	}
 
+	// Anonymous votes also count against the spectator's IP, so inventing device IDs
+	// does not buy extra votes
+	if client.userID == "" {
+		reserved, err := debate.ReserveAnonymousVote(client.debateID, payload.PollID, client.clientIP)
+		if err != nil {
+			return
+		}
+		if !reserved {
+			sendPollError(client, payload.PollID, "Too many votes from this network; log in to vote")
+			return
+		}
+	}
+
 	// Process vote
 	store := debate.NewPollStore()
 	success, err := store.Vote(client.debateID, payload.PollID, payload.Option, client.spectatorHash)
+	if (err != nil || !success) && client.userID == "" {
+		debate.ReleaseAnonymousVote(client.debateID, payload.PollID, client.clientIP)
+	}
+	if errors.Is(err, debate.ErrPollClosed) {
+		// The poll may have run out of time on an instance that no longer has its timer
+		services.CloseExpiredPolls(client.debateID)
+	}
+	if errors.Is(err, debate.ErrPollClosed) || errors.Is(err, debate.ErrInvalidPollOption) {
+		sendPollError(client, payload.PollID, err.Error())
+		return
+	}
 	if err != nil {
 		return
 	}
@@ -330,33 +613,23 @@ func handleVote(client *SpectatorClient, payloadBytes []byte) {
 		return
 	}
 
-	// Get hub to broadcast
-	hub := GetDebateHub()
-
-	// Create event for broadcasting (in frontend format)
-	voteEvent := map[string]interface{}{
-		"type":      "vote",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.RecordSentimentVote(ctx, client.debateID, payload.PollID, payload.Option); err != nil {
+		log.Printf("Failed to record vote sentiment: debate=%s err=%v", client.debateID, err)
 	}
 
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(voteEvent)
-		}
-		room.mu.RUnlock()
-	}
-	hub.mu.RUnlock()
+	// Deliver through the debate stream so every spectator receives it exactly once
+	publishSpectatorEvent(client.debateID, "vote", payload)
+}
 
-	// Also publish to stream for persistence (if Redis is available)
-	event, err := debate.NewEvent("vote", payload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
-	}
+// sendPollError tells a spectator why their vote was not counted
+func sendPollError(client *SpectatorClient, pollID, reason string) {
+	client.WriteJSON(map[string]interface{}{
+		"type":      "poll_error",
+		"payload":   map[string]interface{}{"pollId": pollID, "error": reason},
+		"timestamp": time.Now().Unix(),
+	})
 }
 
 // handleQuestion handles a question request
@@ -366,195 +639,182 @@ func handleQuestion(client *SpectatorClient, payloadBytes []byte) {
 		return
 	}
 
-	// Set spectator hash and timestamp
-	payload.SpectatorHash = client.spectatorHash
-	if payload.QID == "" {
-		payload.QID = uuid.New().String()
-	}
-	payload.Timestamp = time.Now().Unix()
-
-	// Check rate limit
-	rateLimiter := debate.NewRateLimiter()
-	config := debate.DefaultRateLimitConfig()
-	canAsk, err := rateLimiter.CheckQuestionRateLimit(client.debateID, client.spectatorHash, config)
+	// Check and record the rate limit in one step
+	canAsk, err := debate.NewRateLimiter().AllowQuestion(client.debateID, client.spectatorHash)
 	if err != nil || !canAsk {
 		return
 	}
 
-	// Record rate limit
-	rateLimiter.RecordQuestion(client.debateID, client.spectatorHash, config)
+	// Store the question in the debate's moderated queue
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	question, err := services.SubmitAudienceQuestion(ctx, client.debateID, payload.Text, client.spectatorHash)
+	if err != nil {
+		return
+	}
+	if err := services.RecordSentimentQuestion(ctx, client.debateID); err != nil {
+		log.Printf("Failed to record question sentiment: debate=%s err=%v", client.debateID, err)
+	}
 
-	// Get hub to broadcast
-	hub := GetDebateHub()
+	// Only the asker sees the question while it waits for a moderator; the audience gets it
+	// through the debate stream once approved
+	client.WriteJSON(map[string]interface{}{
+		"type": "question_received",
+		"payload": debate.QuestionPayload{
+			QID:       question.ID,
+			Text:      question.Text,
+			Status:    question.Status,
+			Timestamp: question.CreatedAt.Unix(),
+		},
+		"timestamp": time.Now().Unix(),
+	})
+}
 
-	// Create event for broadcasting (in frontend format)
-	questionEvent := map[string]interface{}{
-		"type":      "question",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
+// handleUpvoteQuestion handles an upvote on a queued question. Each spectator can
+// upvote a question once.
+func handleUpvoteQuestion(client *SpectatorClient, payloadBytes []byte) {
+	var payload debate.QuestionUpvotePayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil || payload.QID == "" {
+		return
 	}
 
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			if err := c.WriteJSON(questionEvent); err != nil {
-			}
-		}
-		room.mu.RUnlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	question, counted, err := services.UpvoteAudienceQuestion(ctx, client.debateID, payload.QID, client.spectatorHash)
+	if err != nil || !counted || question.Status != models.AudienceQuestionApproved {
+		return
 	}
-	hub.mu.RUnlock()
 
-	// Also publish to stream for persistence (if Redis is available)
-	event, err := debate.NewEvent("question", payload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
-	}
+	publishSpectatorEvent(client.debateID, "question_updated", debate.QuestionUpdatedPayload{
+		QID:       question.ID,
+		Status:    question.Status,
+		Upvotes:   question.Upvotes,
+		Timestamp: time.Now().Unix(),
+	})
 }
 
+// maxReactionLength bounds a reaction, which is an emoji or a short name
+const maxReactionLength = 32
+
+// reactionAggregator turns individual reactions into one reaction_burst per bucket, so a
+// large audience does not fan out a write per reaction to every spectator
+var reactionAggregator = debate.NewReactionAggregator(func(debateID string, burst debate.ReactionBurstPayload) {
+	publishSpectatorEvent(debateID, "reaction_burst", burst)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.RecordReactionBurst(ctx, debateID, burst); err != nil {
+		log.Printf("Failed to record reaction sentiment: debate=%s err=%v", debateID, err)
+	}
+})
+
 // handleReaction handles a reaction request
 func handleReaction(client *SpectatorClient, payloadBytes []byte) {
 	var payload debate.ReactionPayload
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return
 	}
+	payload.Reaction = strings.TrimSpace(payload.Reaction)
+	if payload.Reaction == "" || len(payload.Reaction) > maxReactionLength {
+		return
+	}
 
-	// Set spectator hash and timestamp
-	payload.SpectatorHash = client.spectatorHash
-	payload.Timestamp = time.Now().Unix()
-
-	// Check rate limit
-	rateLimiter := debate.NewRateLimiter()
-	config := debate.DefaultRateLimitConfig()
-	canReact, err := rateLimiter.CheckReactionRateLimit(client.debateID, client.spectatorHash, config)
+	// Check and record the rate limit in one step
+	canReact, err := debate.NewRateLimiter().AllowReaction(client.debateID, client.spectatorHash)
 	if err != nil || !canReact {
 		return
 	}
 
-	// Record rate limit
-	rateLimiter.RecordReaction(client.debateID, client.spectatorHash, config)
-
-	// Get hub to broadcast
-	hub := GetDebateHub()
-
-	// Create event for broadcasting (in frontend format)
-	reactionEvent := map[string]interface{}{
-		"type":      "reaction",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
-	}
+	// Counted into the debate's next reaction_burst
+	reactionAggregator.Add(client.debateID, payload.Reaction, payload.Side)
+}
 
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(reactionEvent)
-		}
-		room.mu.RUnlock()
+// handleChat handles a spectator chat message. The message reaches spectators through the
+// debate stream, never the debaters' room.
+func handleChat(client *SpectatorClient, payloadBytes []byte) {
+	var payload debate.ChatSendPayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return
 	}
-	hub.mu.RUnlock()
 
-	// Also publish to stream for persistence (if Redis is available)
-	event, err := debate.NewEvent("reaction", payload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sender := services.ChatSender{SpectatorHash: client.spectatorHash, UserID: client.userID}
+	if _, err := services.SendSpectatorChat(ctx, client.debateID, sender, payload.Text); err != nil {
+		sendChatError(client, err)
 	}
 }
 
-// handleCreatePoll handles a poll creation request
-func handleCreatePoll(client *SpectatorClient, payloadBytes []byte) {
-	var payload debate.CreatePollPayload
+// handleChatModerate lets the host or a moderator, connected as a logged-in spectator,
+// delete messages and mute or ban spectators
+func handleChatModerate(client *SpectatorClient, payloadBytes []byte) {
+	var payload debate.ChatModeratePayload
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return
 	}
-
-	payload.SpectatorHash = client.spectatorHash
-	payload.Timestamp = time.Now().Unix()
-
-	store := debate.NewPollStore()
-	if store == nil {
+	if client.userID == "" {
+		sendChatError(client, services.ErrChatPermission)
 		return
 	}
 
-	pollID, err := store.CreatePoll(client.debateID, payload.PollID, payload.Question, payload.Options)
-	if err != nil {
-		return
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.ModerateSpectatorChat(ctx, client.debateID, client.userID, payload); err != nil {
+		sendChatError(client, err)
 	}
+}
 
-	// Load current snapshot for counts/metadata
-	snapshot, err := loadPollSnapshot(client.debateID)
-	if err != nil {
-		// Even if snapshot fails, continue with created event
-	}
-
-	// Determine created poll data for event
-	var createdPoll map[string]interface{}
-	if snapshot != nil {
-		if payloadMap, ok := snapshot["payload"].(map[string]interface{}); ok {
-			if pollsRaw, ok := payloadMap["polls"].([]interface{}); ok {
-				for _, p := range pollsRaw {
-					if pollObj, ok := p.(map[string]interface{}); ok {
-						if id, ok := pollObj["pollId"].(string); ok && id == pollID {
-							createdPoll = pollObj
-							break
-						}
-					}
-				}
-			}
-		}
+// handleChatSettings lets the host or a moderator change slow mode, the word filter and
+// link blocking
+func handleChatSettings(client *SpectatorClient, payloadBytes []byte) {
+	var payload debate.ChatSettings
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return
 	}
-
-	if createdPoll == nil {
-		// Fallback payload
-		createdPoll = map[string]interface{}{
-			"pollId":   pollID,
-			"question": payload.Question,
-			"options":  payload.Options,
-			"counts":   map[string]int64{},
-			"voters":   int64(0),
-		}
+	if client.userID == "" {
+		sendChatError(client, services.ErrChatPermission)
+		return
 	}
 
-	createdEvent := map[string]interface{}{
-		"type":      "poll_created",
-		"payload":   createdPoll,
-		"timestamp": payload.Timestamp,
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := services.UpdateChatSettings(ctx, client.debateID, client.userID, payload); err != nil {
+		sendChatError(client, err)
 	}
+}
 
-	hub := GetDebateHub()
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(createdEvent)
-			if snapshot != nil {
-				c.WriteJSON(snapshot)
-			}
-		}
-		room.mu.RUnlock()
+// sendChatError tells a spectator why a chat message or action was refused
+func sendChatError(client *SpectatorClient, err error) {
+	payload := map[string]interface{}{"error": err.Error()}
+	var rateErr *services.ChatRateError
+	if errors.As(err, &rateErr) {
+		payload["retryAfter"] = int(math.Ceil(rateErr.RetryAfter.Seconds()))
 	}
-	hub.mu.RUnlock()
+	client.WriteJSON(map[string]interface{}{
+		"type":      "chat_error",
+		"payload":   payload,
+		"timestamp": time.Now().Unix(),
+	})
+}
 
-	// Publish event for persistence
-	eventPayload := debate.PollCreatedPayload{
-		PollID:    pollID,
-		Question:  payload.Question,
-		Options:   payload.Options,
-		Timestamp: payload.Timestamp,
+// publishSpectatorEvent publishes an event to the debate stream once. Every instance's
+// subscription delivers the event to its own spectators, which drop any event ID they
+// have already received. If Redis cannot be reached the event is delivered to this
+// instance's spectators directly.
+func publishSpectatorEvent(debateID, eventType string, payload interface{}) {
+	event, err := debate.NewEvent(eventType, payload)
+	if err != nil {
+		return
 	}
-	event, err := debate.NewEvent("poll_created", eventPayload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event)
+	if err := debate.PublishEvent(debateID, event); err != nil {
+		GetDebateHub().BroadcastToDebate(debateID, event)
 	}
 }
 
-// loadPollSnapshot loads the current poll state from Redis
+// loadPollSnapshot loads the current poll state from the poll store, first closing any
+// poll whose close time has passed
 func loadPollSnapshot(debateID string) (map[string]interface{}, error) {
+	services.CloseExpiredPolls(debateID)
 	store := debate.NewPollStore()
 	pollState, votersCount, metadata, err := store.GetPollState(debateID)
 	if err != nil {
@@ -570,6 +830,10 @@ func loadPollSnapshot(debateID string) (map[string]interface{}, error) {
 			"options":  meta.Options,
 			"counts":   counts,
 			"voters":   votersCount[pollID],
+			"status":   meta.Status,
+		}
+		if meta.ClosesAt > 0 {
+			poll["closesAt"] = meta.ClosesAt
 		}
 		polls = append(polls, poll)
 	}
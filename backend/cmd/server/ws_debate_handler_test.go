@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"arguehub/controllers"
+	"arguehub/internal/debate"
+	"arguehub/services"
+	"arguehub/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+// startSpectatorServer serves the spectator hub with a users collection of one account
+func startSpectatorServer(t *testing.T, email, userID string) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	utils.SetJWTSecret(testJWTSecret)
+	if err := debate.SetSpectatorSecret("test-spectator-secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := lookupUserID
+	lookupUserID = func(e string) (string, error) {
+		if e != email {
+			return "", errors.New("user not found")
+		}
+		return userID, nil
+	}
+	t.Cleanup(func() { lookupUserID = previous })
+
+	router := gin.New()
+	router.GET("/ws/debate/:debateID", DebateWebsocketHandler)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// readIdentity reads messages until the identity message and returns its payload
+func readIdentity(t *testing.T, conn *websocket.Conn) map[string]interface{} {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("Expected an identity message, got %v", err)
+		}
+		if msg["type"] == "identity" {
+			payload, _ := msg["payload"].(map[string]interface{})
+			return payload
+		}
+	}
+}
+
+// hubUserIDs returns the user IDs of the spectators connected to a debate
+func hubUserIDs(debateID string) []string {
+	hub := GetDebateHub()
+	hub.mu.RLock()
+	defer hub.mu.RUnlock()
+	room, ok := hub.debates[debateID]
+	if !ok {
+		return nil
+	}
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	ids := make([]string, 0, len(room.clients))
+	for _, client := range room.clients {
+		ids = append(ids, client.userID)
+	}
+	return ids
+}
+
+func TestSpectatorAuthenticatesWithLoginToken(t *testing.T) {
+	url := startSpectatorServer(t, "ada@example.com", "64b7f0c2e4b0a1a2b3c4d5e6")
+	token, err := controllers.GenerateJWT("ada@example.com", testJWTSecret, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/debate-auth?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected the spectator to connect, got %v", err)
+	}
+	defer conn.Close()
+
+	if payload := readIdentity(t, conn); payload["authenticated"] != true {
+		t.Errorf("Expected the spectator to be authenticated, got %v", payload["authenticated"])
+	}
+	if ids := hubUserIDs("debate-auth"); len(ids) != 1 || ids[0] != "64b7f0c2e4b0a1a2b3c4d5e6" {
+		t.Errorf("Expected the spectator to be the user's ID, got %v", ids)
+	}
+}
+
+func TestSpectatorWithExpiredTokenIsAnonymous(t *testing.T) {
+	url := startSpectatorServer(t, "ada@example.com", "64b7f0c2e4b0a1a2b3c4d5e6")
+	token, err := controllers.GenerateJWT("ada@example.com", testJWTSecret, -5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/debate-expired?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected an expired token to connect anonymously, got %v", err)
+	}
+	defer conn.Close()
+
+	if payload := readIdentity(t, conn); payload["authenticated"] != false {
+		t.Errorf("Expected the spectator to be anonymous, got %v", payload["authenticated"])
+	}
+	if ids := hubUserIDs("debate-expired"); len(ids) != 1 || ids[0] != "" {
+		t.Errorf("Expected an anonymous spectator, got %v", ids)
+	}
+}
+
+func TestSpectatorWithInvalidTokenIsRejected(t *testing.T) {
+	url := startSpectatorServer(t, "ada@example.com", "64b7f0c2e4b0a1a2b3c4d5e6")
+	forged, err := controllers.GenerateJWT("ada@example.com", "another-secret", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := controllers.GenerateJWT("eve@example.com", testJWTSecret, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{"forged": forged, "unknown user": unknown} {
+		_, resp, err := websocket.DefaultDialer.Dial(url+"/ws/debate/debate-invalid?token="+token, nil)
+		if err == nil {
+			t.Errorf("Expected the %s token to be rejected", name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected the %s token to get %d, got %v", name, http.StatusUnauthorized, resp)
+		}
+	}
+}
+
+// readPollError reads messages until a poll error and returns its message
+func readPollError(t *testing.T, conn *websocket.Conn) string {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("Expected a poll error, got %v", err)
+		}
+		if msg["type"] == "poll_error" {
+			payload, _ := msg["payload"].(map[string]interface{})
+			reason, _ := payload["error"].(string)
+			return reason
+		}
+	}
+}
+
+func TestMotionVotesNeedALoggedInSpectator(t *testing.T) {
+	url := startSpectatorServer(t, "ada@example.com", "64b7f0c2e4b0a1a2b3c4d5e6")
+	previous := isDebateParticipant
+	isDebateParticipant = func(ctx context.Context, roomID, userID string) bool { return userID == "debater-id" }
+	t.Cleanup(func() { isDebateParticipant = previous })
+
+	debateID := "debate-motion-vote"
+	services.OpenPreDebateMotionPoll(debateID)
+	vote := `{"type":"vote","payload":{"pollId":"` + services.MotionPrePollID + `","option":"For"}}`
+
+	anonymous, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/"+debateID+"?spectatorId=device-1", nil)
+	if err != nil {
+		t.Fatalf("Expected the spectator to connect, got %v", err)
+	}
+	defer anonymous.Close()
+	readIdentity(t, anonymous)
+	anonymous.WriteMessage(websocket.TextMessage, []byte(vote))
+	if reason := readPollError(t, anonymous); reason != "Log in to vote on the motion" {
+		t.Errorf("Expected an anonymous motion vote to be refused, got %q", reason)
+	}
+
+	lookupUserID = func(e string) (string, error) { return "debater-id", nil }
+	token, err := controllers.GenerateJWT("bob@example.com", testJWTSecret, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	debater, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/"+debateID+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected the debater to connect, got %v", err)
+	}
+	defer debater.Close()
+	readIdentity(t, debater)
+	debater.WriteMessage(websocket.TextMessage, []byte(vote))
+	if reason := readPollError(t, debater); reason != "Debaters cannot vote on their own motion" {
+		t.Errorf("Expected a debater's motion vote to be refused, got %q", reason)
+	}
+
+	lookupUserID = func(e string) (string, error) { return "viewer-id", nil }
+	viewer, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/"+debateID+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected the spectator to connect, got %v", err)
+	}
+	defer viewer.Close()
+	readIdentity(t, viewer)
+	viewer.WriteMessage(websocket.TextMessage, []byte(vote))
+	viewer.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := viewer.ReadJSON(&msg); err != nil {
+			t.Fatalf("Expected the spectator's vote to be counted, got %v", err)
+		}
+		if msg["type"] == "vote" {
+			break
+		}
+	}
+
+	if counts, voters, _ := debate.NewPollStore().PollResults(debateID, services.MotionPrePollID); voters != 1 || counts["For"] != 1 {
+		t.Errorf("Expected only the spectator's motion vote, got %v from %d voters", counts, voters)
+	}
+}
+
+func TestCommentaryNeedsALoggedInSpectator(t *testing.T) {
+	url := startSpectatorServer(t, "ada@example.com", "64b7f0c2e4b0a1a2b3c4d5e6")
+	debateID := "debate-commentary"
+
+	anonymous, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/"+debateID+"?spectatorId=device-1", nil)
+	if err != nil {
+		t.Fatalf("Expected the spectator to connect, got %v", err)
+	}
+	defer anonymous.Close()
+	if payload := readIdentity(t, anonymous); payload["commentary"] != false {
+		t.Errorf("Expected an anonymous spectator not to get commentary, got %v", payload["commentary"])
+	}
+
+	token, err := controllers.GenerateJWT("ada@example.com", testJWTSecret, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viewer, _, err := websocket.DefaultDialer.Dial(url+"/ws/debate/"+debateID+"?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Expected the spectator to connect, got %v", err)
+	}
+	defer viewer.Close()
+	if payload := readIdentity(t, viewer); payload["commentary"] != true {
+		t.Errorf("Expected a logged-in spectator to get commentary, got %v", payload["commentary"])
+	}
+
+	commentary, _ := debate.NewEvent("commentary", debate.CommentaryPayload{Phase: "openingFor", Summary: "For opened strongly"})
+	debate.PublishEvent(debateID, commentary)
+	marker, _ := debate.NewEvent("chat_settings", debate.ChatSettings{})
+	debate.PublishEvent(debateID, marker)
+
+	// readUntilMarker reports whether the commentary arrived before the marker event
+	readUntilMarker := func(conn *websocket.Conn) bool {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		sawCommentary := false
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				t.Fatalf("Expected the marker event, got %v", err)
+			}
+			switch msg["type"] {
+			case "commentary":
+				sawCommentary = true
+			case "chat_settings":
+				return sawCommentary
+			}
+		}
+	}
+	if readUntilMarker(anonymous) {
+		t.Error("Expected the commentary to be held back from an anonymous spectator")
+	}
+	if !readUntilMarker(viewer) {
+		t.Error("Expected a logged-in spectator to receive the commentary")
+	}
+}
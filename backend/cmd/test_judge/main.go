@@ -17,7 +17,7 @@ func main() {
 		panic("failed to load config: " + err.Error())
 	}
 
-	services.InitDebateVsBotService(cfg)
+	services.InitLLMProviders(cfg)
 
 	sample := map[string]string{
 		"openingFor":           "Good evening. I firmly support the motion and will outline three reasons.",
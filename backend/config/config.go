@@ -21,12 +21,52 @@ type Config struct {
 
 	Openai struct {
 		GptApiKey string `yaml:"gptApiKey"`
+		Model     string `yaml:"model"` // Chat model; defaults to gpt-4o-mini
 	} `yaml:"openai"`
 
 	Gemini struct {
 		ApiKey string `yaml:"apiKey"`
+		Model  string `yaml:"model"` // Defaults to gemini-2.5-flash
 	} `yaml:"gemini"`
 
+	LLM struct {
+		Provider       string            `yaml:"provider"`       // "gemini", "openai", "local" or "fake"; defaults to gemini
+		Tasks          map[string]string `yaml:"tasks"`          // Provider by task ("bot", "judge", "coach", "weak_statement", "pros_cons", "commentary")
+		LocalURL       string            `yaml:"localUrl"`       // Base URL of an OpenAI-compatible server, e.g. Ollama's http://localhost:11434/v1
+		LocalModel     string            `yaml:"localModel"`     // Model name sent to the local server
+		LocalAPIKey    string            `yaml:"localApiKey"`    // Optional bearer token for the local server
+		FakeResponses  map[string]string `yaml:"fakeResponses"`  // Canned reply per task for the fake provider
+		TimeoutSeconds int               `yaml:"timeoutSeconds"` // Per-request timeout for the OpenAI and local providers
+		// Model per task, or per bot level of the "bot" task, for one provider; unrouted tasks
+		// and tasks served by another provider use the provider's default model
+		Routes []struct {
+			Provider string `yaml:"provider"`
+			Task     string `yaml:"task"`
+			Level    string `yaml:"level"`
+			Model    string `yaml:"model"`
+		} `yaml:"routes"`
+		Budgets struct {
+			UserDailyTokens   int64             `yaml:"userDailyTokens"`   // Tokens per user per UTC day; 0 is unlimited
+			GlobalDailyTokens int64             `yaml:"globalDailyTokens"` // Tokens for everyone per UTC day; 0 is unlimited
+			DegradedModels    map[string]string `yaml:"degradedModels"`    // Model by provider that answers once a budget is spent; none falls back to canned replies
+		} `yaml:"budgets"`
+	} `yaml:"llm"`
+
+	Bots struct {
+		PersonalityDir string `yaml:"personalityDir"` // Extra YAML/JSON personality files, added to or replacing the built-in ones
+	} `yaml:"bots"`
+
+	Transcription struct {
+		Provider       string `yaml:"provider"`       // "whisper" or "fake"; empty disables server-side transcription
+		WhisperURL     string `yaml:"whisperUrl"`     // Base URL of a Whisper-compatible HTTP service
+		Model          string `yaml:"model"`          // Model name sent to the service
+		Language       string `yaml:"language"`       // Spoken language hint, e.g. "en"
+		AudioDir       string `yaml:"audioDir"`       // Where uploaded audio chunks are stored
+		TimeoutSeconds int    `yaml:"timeoutSeconds"` // Per-request timeout for the transcription service
+		AudioRetention string `yaml:"audioRetention"` // "transcribed" deletes audio once transcribed, "keep" never deletes it
+		MaxAttempts    int    `yaml:"maxAttempts"`    // Transcription attempts per chunk before it waits for a manual retry
+	} `yaml:"transcription"`
+
 	Database struct {
 		URI string `yaml:"uri"`
 	} `yaml:"database"`
@@ -42,6 +82,22 @@ type Config struct {
 		Expiry int    `yaml:"expiry"`
 	}
 
+	Spectator struct {
+		IdentitySecret          string   `yaml:"identitySecret"`          // HMAC key for spectator identities; required
+		MaxConnectionsPerIP     int      `yaml:"maxConnectionsPerIP"`     // Anonymous connections per IP and debate
+		MaxConnectionsPerDevice int      `yaml:"maxConnectionsPerDevice"` // Connections per anonymous device and debate
+		MaxVotesPerIP           int      `yaml:"maxVotesPerIP"`           // Anonymous votes per IP and poll
+		ChatHistoryLength       int      `yaml:"chatHistoryLength"`       // Chat messages kept per debate for late joiners
+		ChatBlockedWords        []string `yaml:"chatBlockedWords"`        // Masked in every debate's spectator chat
+	} `yaml:"spectator"`
+
+	// Rate limit policies by action ("question", "reaction", "reaction_burst",
+	// "chat", "commentary", "vsbot_message", "post", "comment"); unlisted actions keep their defaults
+	RateLimits map[string]struct {
+		Limit         int `yaml:"limit"`
+		WindowSeconds int `yaml:"windowSeconds"`
+	} `yaml:"rateLimits"`
+
 	SMTP struct { // Add SMTP configuration
 		Host        string
 		Port        int
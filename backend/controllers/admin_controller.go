@@ -92,7 +92,7 @@ func AdminSignup(ctx *gin.Context) {
 	newAdmin.ID = result.InsertedID.(primitive.ObjectID)
 
 	// Generate JWT
-	token, err := generateJWT(newAdmin.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
+	token, err := GenerateJWT(newAdmin.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token", "message": err.Error()})
 		return
@@ -146,7 +146,7 @@ func AdminLogin(ctx *gin.Context) {
 	}
 
 	// Generate JWT
-	token, err := generateJWT(admin.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
+	token, err := GenerateJWT(admin.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token", "message": err.Error()})
 		return
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LLMContext attributes the model calls made for a request to the authenticated user, so
+// they count against the user's daily AI budget
+func LLMContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, exists := c.Get("userID"); exists {
+		if id, ok := userID.(primitive.ObjectID); ok {
+			ctx = services.WithLLMUser(ctx, id.Hex())
+		}
+	}
+	return ctx
+}
+
+// GetAIUsageHandler returns the model tokens the user has spent today and their daily budget
+func GetAIUsageHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	id, ok := userID.(primitive.ObjectID)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	usage, err := services.GetAIUsage(id.Hex())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load AI usage"})
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"arguehub/db"
+	"arguehub/internal/wsconn"
 	"arguehub/models"
 	"context"
 	"log"
@@ -339,3 +340,8 @@ func GetAdminActionLogs(ctx *gin.Context) {
 		"limit": limit,
 	})
 }
+
+// GetWebsocketMetrics returns connection metrics for every websocket hub
+func GetWebsocketMetrics(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"hubs": wsconn.Snapshots()})
+}
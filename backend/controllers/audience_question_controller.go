@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetAudienceQuestionsHandler returns a debate's spectator Q&A queue, most upvoted first.
+// Moderators and the host also see pending and hidden questions.
+func GetAudienceQuestionsHandler(c *gin.Context) {
+	roomID := c.Param("roomId")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	moderator := false
+	if userID, exists := c.Get("userID"); exists {
+		if id, ok := userID.(primitive.ObjectID); ok {
+			moderator = services.CanTakeSeat(ctx, roomID, id.Hex(), "moderator")
+		}
+	}
+
+	questions, err := services.GetAudienceQuestions(ctx, roomID, moderator)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load questions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roomId": roomID, "questions": questions})
+}
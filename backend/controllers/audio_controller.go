@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+	"arguehub/websocket"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadAudioChunkHandler stores an uploaded audio chunk for a debate phase and transcribes it.
+// Form fields: audio (file), phase, seq and offsetMs. The caller must be debating in the room
+// and hold the floor; the chunk is recorded under the side they took and the room's current
+// phase, which the phase field must match when it is sent.
+func UploadAudioChunkHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	roomID := c.Param("roomId")
+	if roomID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "roomId is required"})
+		return
+	}
+	callerID := userID.(primitive.ObjectID).Hex()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	defer cancel()
+	if !services.IsDebateParticipant(ctx, roomID, callerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the room's debaters can upload audio"})
+		return
+	}
+	role, phase := websocket.DebaterFloor(roomID, callerID)
+	if role == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Join the debate room and choose a side before uploading audio"})
+		return
+	}
+	if phase == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "You do not have the floor"})
+		return
+	}
+	if requested := c.PostForm("phase"); requested != "" && requested != phase {
+		c.JSON(http.StatusConflict, gin.H{"error": "The debate is no longer in this phase"})
+		return
+	}
+
+	seq, _ := strconv.Atoi(c.PostForm("seq"))
+	offsetMs, _ := strconv.ParseInt(c.PostForm("offsetMs"), 10, 64)
+
+	file, header, err := c.Request.FormFile("audio")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "audio file is required"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxAudioChunkBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read audio file"})
+		return
+	}
+
+	chunk := &models.AudioChunk{
+		RoomID:   roomID,
+		UserID:   callerID,
+		Role:     role,
+		Phase:    phase,
+		Seq:      seq,
+		OffsetMs: offsetMs,
+		MimeType: header.Header.Get("Content-Type"),
+	}
+
+	result := make(chan error, 1)
+	err = services.QueueAudioChunk(chunk, data, func(err error) { result <- err })
+	if errors.Is(err, services.ErrAudioQueueFull) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		// The chunk is still transcribed; the caller can read it from the transcript later
+		c.JSON(http.StatusAccepted, gin.H{"chunk": chunk})
+		return
+	}
+	if err != nil && chunk.Status != models.AudioStatusFailed {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"chunk": chunk})
+}
+
+// GetAudioTranscriptHandler returns the server-side transcript built from a room's audio to
+// the room's debaters, owner, judges and moderators
+func GetAudioTranscriptHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	if !services.CanViewAudioTranscript(ctx, c.Param("roomId"), userID.(primitive.ObjectID).Hex()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to view this transcript"})
+		return
+	}
+
+	transcripts, err := services.GetPhaseTranscripts(ctx, c.Param("roomId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load transcript"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roomId": c.Param("roomId"), "transcripts": transcripts})
+}
+
+// RetryAudioTranscriptHandler queues a room's untranscribed audio chunks for another
+// transcription attempt, for the same users who may read the transcript
+func RetryAudioTranscriptHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	if !services.CanViewAudioTranscript(ctx, c.Param("roomId"), userID.(primitive.ObjectID).Hex()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to retry this transcript"})
+		return
+	}
+
+	queued, err := services.RetryRoomAudio(ctx, c.Param("roomId"))
+	if errors.Is(err, services.ErrAudioQueueFull) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error(), "queued": queued})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"queued": queued})
+}
@@ -106,7 +106,7 @@ func GoogleLogin(ctx *gin.Context) {
 	}
 
 	// Generate JWT
-	token, err := generateJWT(existingUser.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
+	token, err := GenerateJWT(existingUser.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
 	if err != nil {
 		ctx.JSON(500, gin.H{"error": "Failed to generate token", "message": err.Error()})
 		return
@@ -290,7 +290,7 @@ func Login(ctx *gin.Context) {
 	}
 
 	// Generate JWT
-	token, err := generateJWT(user.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
+	token, err := GenerateJWT(user.Email, cfg.JWT.Secret, cfg.JWT.Expiry)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token", "message": err.Error()})
 		return
@@ -537,8 +537,9 @@ func VerifyToken(ctx *gin.Context) {
 	})
 }
 
-// Helper function to generate JWT
-func generateJWT(email, secret string, expiryMinutes int) (string, error) {
+// GenerateJWT issues the access token handed out at login. It identifies the user by
+// email in the sub claim.
+func GenerateJWT(email, secret string, expiryMinutes int) (string, error) {
 	now := time.Now()
 	expirationTime := now.Add(time.Minute * time.Duration(expiryMinutes))
 	
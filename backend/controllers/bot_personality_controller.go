@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"arguehub/middlewares"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindBotPersonality reads a personality from the request body, as YAML when the
+// Content-Type says so and JSON otherwise
+func bindBotPersonality(ctx *gin.Context) (services.BotPersonality, bool) {
+	raw, err := ctx.GetRawData()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "message": err.Error()})
+		return services.BotPersonality{}, false
+	}
+	format := "json"
+	if strings.Contains(ctx.ContentType(), "yaml") {
+		format = "yaml"
+	}
+	personality, err := services.ParseBotPersonality(raw, format)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "message": err.Error()})
+		return services.BotPersonality{}, false
+	}
+	return personality, true
+}
+
+// botPersonalityError maps the personality service's errors to responses
+func botPersonalityError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrBotPersonalityNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrBotPersonalityExists), errors.Is(err, services.ErrBotPersonalityConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDefaultPersonality):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save bot personality", "message": err.Error()})
+	}
+}
+
+// logBotPersonalityAction records an edit in the admin action log
+func logBotPersonalityAction(ctx *gin.Context, action string, version *services.BotPersonalityVersion) {
+	middlewares.LogAdminAction(ctx, action, "bot", version.ID, map[string]interface{}{
+		"bot":     version.Name,
+		"version": version.Version,
+	})
+}
+
+// adminEmail returns the email of the admin making the request
+func adminEmail(ctx *gin.Context) string {
+	email, _ := ctx.Get("adminEmail")
+	s, _ := email.(string)
+	return s
+}
+
+// BotSummary is what bot selection shows of a bot
+type BotSummary struct {
+	Name        string `json:"name"`
+	Level       string `json:"level"`
+	Rating      int    `json:"rating"`
+	Description string `json:"description,omitempty"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+	Quote       string `json:"quote,omitempty"`
+}
+
+// ListBots returns the bots users can debate, easiest first
+func ListBots(ctx *gin.Context) {
+	bots := []BotSummary{}
+	for _, b := range services.ListBotPersonalities() {
+		summary := BotSummary{
+			Name:        b.Name,
+			Level:       b.Level,
+			Rating:      b.Rating,
+			Description: b.Description,
+			AvatarURL:   b.AvatarURL,
+		}
+		if len(b.Catchphrases) > 0 {
+			summary.Quote = b.Catchphrases[0]
+		}
+		bots = append(bots, summary)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"bots": bots})
+}
+
+// ListBotPersonalities returns every bot personality in use
+func ListBotPersonalities(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"bots": services.ListBotPersonalities()})
+}
+
+// GetBotPersonality returns a bot's personality in use and its stored versions
+func GetBotPersonality(ctx *gin.Context) {
+	name := ctx.Param("name")
+	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	versions, err := services.BotPersonalityHistory(dbCtx, name)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bot versions", "message": err.Error()})
+		return
+	}
+	personality, ok := services.LookupBotPersonality(name)
+	if !ok && len(versions) == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": services.ErrBotPersonalityNotFound.Error()})
+		return
+	}
+	response := gin.H{"versions": versions}
+	if ok {
+		response["bot"] = personality
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// CreateBotPersonality adds a new bot from a JSON or YAML definition
+func CreateBotPersonality(ctx *gin.Context) {
+	personality, ok := bindBotPersonality(ctx)
+	if !ok {
+		return
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := services.SaveBotPersonality(dbCtx, personality, adminEmail(ctx), true)
+	if err != nil {
+		botPersonalityError(ctx, err)
+		return
+	}
+	logBotPersonalityAction(ctx, "create_bot", version)
+	ctx.JSON(http.StatusCreated, version)
+}
+
+// UpdateBotPersonality stores a new version of an existing bot. The name cannot change.
+func UpdateBotPersonality(ctx *gin.Context) {
+	personality, ok := bindBotPersonality(ctx)
+	if !ok {
+		return
+	}
+	if personality.Name != ctx.Param("name") {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "The bot's name cannot be changed"})
+		return
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := services.SaveBotPersonality(dbCtx, personality, adminEmail(ctx), false)
+	if err != nil {
+		botPersonalityError(ctx, err)
+		return
+	}
+	logBotPersonalityAction(ctx, "update_bot", version)
+	ctx.JSON(http.StatusOK, version)
+}
+
+// DeleteBotPersonality retires a bot; it can be restored from an earlier version
+func DeleteBotPersonality(ctx *gin.Context) {
+	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := services.DeleteBotPersonality(dbCtx, ctx.Param("name"), adminEmail(ctx))
+	if err != nil {
+		botPersonalityError(ctx, err)
+		return
+	}
+	logBotPersonalityAction(ctx, "delete_bot", version)
+	ctx.JSON(http.StatusOK, version)
+}
+
+// RestoreBotPersonalityVersion makes an earlier version of a bot the current one
+func RestoreBotPersonalityVersion(ctx *gin.Context) {
+	number, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil || number < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	version, err := services.RestoreBotPersonalityVersion(dbCtx, ctx.Param("name"), number, adminEmail(ctx))
+	if err != nil {
+		botPersonalityError(ctx, err)
+		return
+	}
+	logBotPersonalityAction(ctx, "restore_bot", version)
+	ctx.JSON(http.StatusOK, version)
+}
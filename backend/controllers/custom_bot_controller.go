@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arguehub/middlewares"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// customBotUser returns the authenticated user, responding 401 when there is none
+func customBotUser(c *gin.Context) (primitive.ObjectID, bool) {
+	userID, exists := c.Get("userID")
+	id, ok := userID.(primitive.ObjectID)
+	if !exists || !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// customBotParam parses the :id parameter, responding 400 when it is invalid
+func customBotParam(c *gin.Context) (primitive.ObjectID, bool) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bot ID"})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// customBotPage reads ?page and ?limit, defaulting to the first 20
+func customBotPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	return page, limit
+}
+
+// customBotError maps the custom bot service's errors to responses
+func customBotError(c *gin.Context, err error) {
+	var rejection *services.CustomBotRejection
+	switch {
+	case errors.As(err, &rejection):
+		c.JSON(http.StatusBadRequest, gin.H{"error": rejection.Error(), "field": rejection.Field})
+	case errors.Is(err, services.ErrCustomBotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrCustomBotLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process custom bot", "message": err.Error()})
+	}
+}
+
+// customBotResponse returns a bot with its debate bot name; only its creator sees the
+// reference material and reports
+func customBotResponse(bot *models.CustomBot, viewerID primitive.ObjectID) gin.H {
+	if bot.OwnerID != viewerID {
+		bot.ReferenceMaterial = ""
+		bot.ReportCount = 0
+		bot.ModerationNote = ""
+	}
+	return gin.H{"bot": bot, "botName": services.CustomBotPrefix + bot.ID.Hex()}
+}
+
+// ListMyCustomBots returns the bots the user created
+func ListMyCustomBots(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bots, err := services.ListCustomBots(ctx, userID)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bots": bots})
+}
+
+// ListCommunityCustomBots returns a page of the bots users have published
+func ListCommunityCustomBots(c *gin.Context) {
+	page, limit := customBotPage(c)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bots, err := services.ListPublishedCustomBots(ctx, page, limit)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	for i := range bots {
+		bots[i].ReportCount = 0
+	}
+	c.JSON(http.StatusOK, gin.H{"bots": bots, "page": page, "limit": limit})
+}
+
+// CreateCustomBot saves a new bot from the user's definition
+func CreateCustomBot(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	var input services.CustomBotInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "message": err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bot, err := services.CreateCustomBot(ctx, userID, input)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, customBotResponse(bot, userID))
+}
+
+// GetCustomBot returns one of the user's bots or a published one
+func GetCustomBot(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	botID, ok := customBotParam(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bot, err := services.GetCustomBot(ctx, botID, userID.Hex())
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, customBotResponse(bot, userID))
+}
+
+// UpdateCustomBot replaces the definition of one of the user's bots
+func UpdateCustomBot(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	botID, ok := customBotParam(c)
+	if !ok {
+		return
+	}
+	var input services.CustomBotInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "message": err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bot, err := services.UpdateCustomBot(ctx, userID, botID, input)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, customBotResponse(bot, userID))
+}
+
+// DeleteCustomBot deletes one of the user's bots
+func DeleteCustomBot(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	botID, ok := customBotParam(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := services.DeleteCustomBot(ctx, userID, botID); err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Bot deleted"})
+}
+
+// ReportCustomBot flags a published bot for moderators to review
+func ReportCustomBot(c *gin.Context) {
+	userID, ok := customBotUser(c)
+	if !ok {
+		return
+	}
+	botID, ok := customBotParam(c)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := services.ReportCustomBot(ctx, botID, userID); err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Thanks, a moderator will review this bot"})
+}
+
+// ListCustomBotsForReview returns custom bots for moderators: reported bots by default,
+// or hidden ones with ?status=hidden
+func ListCustomBotsForReview(c *gin.Context) {
+	status := c.DefaultQuery("status", models.CustomBotActive)
+	page, limit := customBotPage(c)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bots, err := services.ListCustomBotsForReview(ctx, status, page, limit)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"bots": bots, "page": page, "limit": limit})
+}
+
+// ModerateCustomBot hides a custom bot or restores a hidden one
+func ModerateCustomBot(c *gin.Context) {
+	botID, ok := customBotParam(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Status string `json:"status" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "message": err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	bot, err := services.SetCustomBotStatus(ctx, botID, req.Status, req.Note)
+	if err != nil {
+		customBotError(c, err)
+		return
+	}
+	middlewares.LogAdminAction(c, "moderate_custom_bot", "custom_bot", botID, map[string]interface{}{
+		"status": req.Status,
+		"note":   req.Note,
+	})
+	c.JSON(http.StatusOK, bot)
+}
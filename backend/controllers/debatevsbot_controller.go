@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"strings"
 	"time"
@@ -19,13 +20,21 @@ import (
 )
 
 type DebateRequest struct {
-	BotName      string           `json:"botName" binding:"required"`
-	BotLevel     string           `json:"botLevel" binding:"required"`
-	Topic        string           `json:"topic" binding:"required"`
-	Stance       string           `json:"stance" binding:"required"`
-	History      []models.Message `json:"history"`
-	PhaseTimings []PhaseTiming    `json:"phaseTimings"`
-	Context      string           `json:"context"`
+	BotName      string        `json:"botName" binding:"required"`
+	BotLevel     string        `json:"botLevel" binding:"required"`
+	Topic        string        `json:"topic" binding:"required"`
+	Stance       string        `json:"stance" binding:"required"`
+	PhaseTimings []PhaseTiming `json:"phaseTimings"`
+}
+
+// DebateTurnRequest is a turn of a bot debate. The history lives on the server, so only
+// the new turn is sent: Text is the user's turn, empty when the bot speaks first, and
+// Context tells the bot what kind of reply is due.
+type DebateTurnRequest struct {
+	DebateID string `json:"debateId" binding:"required"`
+	Phase    string `json:"phase" binding:"required"`
+	Text     string `json:"text"`
+	Context  string `json:"context"`
 }
 
 type PhaseTiming struct {
@@ -34,7 +43,7 @@ type PhaseTiming struct {
 }
 
 type JudgeRequest struct {
-	History []models.Message `json:"history" binding:"required"`
+	DebateID string `json:"debateId" binding:"required"`
 }
 
 type DebateResponse struct {
@@ -81,6 +90,19 @@ func CreateDebate(c *gin.Context) {
 		return
 	}
 
+	// Custom bots can only be debated by their creator or, once published, by anyone
+	if services.IsCustomBotName(req.BotName) {
+		userID, err := utils.GetUserIDFromEmail(email)
+		if err != nil {
+			c.JSON(401, gin.H{"error": "Failed to get user ID"})
+			return
+		}
+		if _, err := services.GetCustomBotByName(c.Request.Context(), req.BotName, userID.Hex()); err != nil {
+			c.JSON(404, gin.H{"error": "Bot not found"})
+			return
+		}
+	}
+
 	// Convert PhaseTimings to backend model format
 	backendPhaseTimings := make([]models.PhaseTiming, len(req.PhaseTimings))
 	for i, pt := range req.PhaseTimings {
@@ -97,7 +119,7 @@ func CreateDebate(c *gin.Context) {
 		BotLevel:     req.BotLevel,
 		Topic:        req.Topic,
 		Stance:       req.Stance,
-		History:      req.History,
+		History:      []models.Message{},
 		PhaseTimings: backendPhaseTimings,
 		CreatedAt:    time.Now().Unix(),
 	}
@@ -107,6 +129,7 @@ func CreateDebate(c *gin.Context) {
 		c.JSON(500, gin.H{"error": "Failed to create debate: " + err.Error()})
 		return
 	}
+	services.RecordCustomBotDebate(req.BotName)
 
 	response := DebateResponse{
 		DebateId:     debateID,
@@ -133,48 +156,73 @@ func SendDebateMessage(c *gin.Context) {
 		return
 	}
 
-	var req DebateRequest
+	var req DebateTurnRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": "Invalid request payload: " + err.Error()})
 		return
 	}
 
-	// Generate bot response with the additional context field.
-	botResponse := services.GenerateBotResponse(req.BotName, req.BotLevel, req.Topic, req.History, req.Stance, req.Context, 150)
+	turn := services.VsBotTurn{Phase: req.Phase, Text: req.Text, Context: req.Context}
+	d, botResponse, err := services.TakeVsBotTurn(LLMContext(c), req.DebateID, email, turn, nil)
+	if err != nil {
+		vsBotError(c, err)
+		return
+	}
 
-	// Update debate history with the bot's response.
-	updatedHistory := append(req.History, models.Message{
-		Sender: "Bot",
-		Text:   botResponse,
-		// You can also store the phase if needed.
-	})
+	response := DebateMessageResponse{
+		DebateId: d.ID.Hex(),
+		BotName:  d.BotName,
+		BotLevel: d.BotLevel,
+		Topic:    d.Topic,
+		Stance:   d.Stance,
+		Response: botResponse,
+	}
+	c.JSON(200, response)
+}
 
-	debate := models.DebateVsBot{
-		Email:     email,
-		BotName:   req.BotName,
-		BotLevel:  req.BotLevel,
-		Topic:     req.Topic,
-		Stance:    req.Stance,
-		History:   updatedHistory,
-		CreatedAt: time.Now().Unix(),
+// RecordDebateTurn saves a user's turn that the bot does not reply to, such as the answer
+// to the bot's cross-examination question
+func RecordDebateTurn(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		c.JSON(401, gin.H{"error": "Authorization token required"})
+		return
 	}
 
-	// Save to database (assuming ID is generated in service or here)
-	if debate.ID.IsZero() {
-		debate.ID = primitive.NewObjectID()
+	token = strings.TrimPrefix(token, "Bearer ")
+	valid, email, err := utils.ValidateTokenAndFetchEmail("./config/config.prod.yml", token, c)
+	if err != nil || !valid {
+		c.JSON(401, gin.H{"error": "Invalid or expired token"})
+		return
 	}
-	if err := db.SaveDebateVsBot(debate); err != nil {
+
+	var req DebateTurnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "Invalid request payload: " + err.Error()})
+		return
 	}
 
-	response := DebateMessageResponse{
-		DebateId: debate.ID.Hex(),
-		BotName:  req.BotName,
-		BotLevel: req.BotLevel,
-		Topic:    req.Topic,
-		Stance:   req.Stance,
-		Response: botResponse,
+	turn := services.VsBotTurn{Phase: req.Phase, Text: req.Text}
+	if err := services.AddVsBotUserTurn(c.Request.Context(), req.DebateID, email, turn); err != nil {
+		vsBotError(c, err)
+		return
+	}
+	c.JSON(200, gin.H{"message": "Turn saved"})
+}
+
+// vsBotError maps the bot debate session's errors to responses
+func vsBotError(c *gin.Context, err error) {
+	var turnErr *services.VsBotTurnError
+	switch {
+	case errors.As(err, &turnErr):
+		c.JSON(400, gin.H{"error": turnErr.Error()})
+	case errors.Is(err, services.ErrVsBotDebateNotFound):
+		c.JSON(404, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrVsBotDebateJudged), errors.Is(err, services.ErrVsBotHistoryChanged):
+		c.JSON(409, gin.H{"error": err.Error()})
+	default:
+		c.JSON(500, gin.H{"error": "Failed to process debate: " + err.Error()})
 	}
-	c.JSON(200, response)
 }
 
 func JudgeDebate(c *gin.Context) {
@@ -204,21 +252,18 @@ func JudgeDebate(c *gin.Context) {
 		return
 	}
 
-	// Judge the debate
-	result := services.JudgeDebate(req.History)
-
-	// Update debate outcome
-	if err := db.UpdateDebateVsBotOutcome(email, result); err != nil {
-	}
-
-	// Get the latest debate information to extract proper details
-	latestDebate, err := db.GetLatestDebateVsBot(email)
+	// Judge the stored debate, so the transcript cannot be edited before judging
+	latestDebate, result, judged, err := services.JudgeVsBotDebate(LLMContext(c), req.DebateID, email)
 	if err != nil {
-		// Use defaults if we can't get the debate info
-		latestDebate = &models.DebateVsBot{
-			Topic:   "Debate vs Bot",
-			BotName: "AI Bot",
-		}
+		vsBotError(c, err)
+		return
+	}
+	// Already judged, or the judge was unavailable: nothing to record
+	if !judged {
+		c.JSON(200, JudgeResponse{
+			Result: result,
+		})
+		return
 	}
 
 	// Determine result from judge's response
@@ -266,6 +311,8 @@ func JudgeDebate(c *gin.Context) {
 		}
 	}
 
+	services.RecordCustomBotResult(latestDebate.BotName, resultStatus)
+
 	// Save transcript with proper debate information
 	_ = services.SaveDebateTranscript(
 		userID,
@@ -274,7 +321,7 @@ func JudgeDebate(c *gin.Context) {
 		latestDebate.Topic,
 		latestDebate.BotName,
 		resultStatus,
-		req.History,
+		latestDebate.History,
 		nil,
 	)
 
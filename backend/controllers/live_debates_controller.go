@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetLiveDebatesHandler lists the public debates running now. ?sort=rating orders them by
+// the debaters' ratings instead of by spectator count; ?limit bounds the list.
+func GetLiveDebatesHandler(c *gin.Context) {
+	sortBy := c.DefaultQuery("sort", services.LiveSortPopular)
+	if sortBy != services.LiveSortPopular && sortBy != services.LiveSortRating {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be popular or rating"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	debates, err := services.ListLiveDebates(sortBy, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list live debates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"debates": debates})
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pollErrorStatus maps poll lifecycle errors to HTTP status codes
+func pollErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPollPermission), errors.Is(err, services.ErrPollReserved):
+		return http.StatusForbidden
+	case errors.Is(err, debate.ErrPollNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreatePollHandler lets the debate host or a moderator create a spectator poll
+func CreatePollHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var input services.CreatePollInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	poll, err := services.CreateDebatePoll(ctx, c.Param("roomId"), userID.(primitive.ObjectID).Hex(), input)
+	if err != nil {
+		c.JSON(pollErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"poll": poll})
+}
+
+// OpenPollHandler starts voting on a draft poll
+func OpenPollHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	poll, err := services.OpenDebatePoll(ctx, c.Param("roomId"), c.Param("pollId"), userID.(primitive.ObjectID).Hex())
+	if err != nil {
+		c.JSON(pollErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// ClosePollHandler stops voting on a poll and freezes its results
+func ClosePollHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	poll, err := services.CloseDebatePoll(ctx, c.Param("roomId"), c.Param("pollId"), userID.(primitive.ObjectID).Hex())
+	if err != nil {
+		c.JSON(pollErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"poll": poll})
+}
+
+// GetArchivedPollsHandler returns the frozen poll results of an ended debate
+func GetArchivedPollsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	polls, err := services.GetArchivedPolls(ctx, c.Param("roomId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load poll results"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roomId": c.Param("roomId"), "polls": polls})
+}
+
+// GetArchivedEventsHandler returns the spectator events of an ended debate
+func GetArchivedEventsHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	events, err := services.GetArchivedEvents(ctx, c.Param("roomId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate events"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"roomId": c.Param("roomId"), "events": events})
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSentimentTimelineHandler returns a debate's audience reactions, votes and questions
+// over time, aligned with its phases
+func GetSentimentTimelineHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	timeline, err := services.GetSentimentTimeline(ctx, c.Param("roomId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sentiment timeline"})
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
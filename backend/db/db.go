@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"github.com/redis/go-redis/v9"
@@ -68,17 +70,49 @@ func SaveDebateVsBot(debate models.DebateVsBot) error {
 	return nil
 }
 
-// UpdateDebateVsBotOutcome updates the outcome of the most recent bot debate for a user
-func UpdateDebateVsBotOutcome(userId, outcome string) error {
-	filter := bson.M{"userId": userId}
-	update := bson.M{"$set": bson.M{"outcome": outcome}}
-	_, err := DebateVsBotCollection.UpdateOne(context.Background(), filter, update, nil)
+// GetDebateVsBot returns a user's bot debate. It returns mongo.ErrNoDocuments when the
+// user has no debate with that ID.
+func GetDebateVsBot(ctx context.Context, debateID primitive.ObjectID, email string) (*models.DebateVsBot, error) {
+	var debate models.DebateVsBot
+	if err := DebateVsBotCollection.FindOne(ctx, bson.M{"_id": debateID, "email": email}).Decode(&debate); err != nil {
+		return nil, err
+	}
+	return &debate, nil
+}
+
+// notJudged matches bot debates without an outcome
+var notJudged = bson.M{"$in": []interface{}{"", nil}}
+
+// SetDebateVsBotHistory stores a user's bot debate history, provided the debate has not
+// been judged and its stored history still has expectedLen messages, so two turns taken
+// at once cannot both be saved. It returns mongo.ErrNoDocuments when no debate matches.
+func SetDebateVsBotHistory(ctx context.Context, debateID primitive.ObjectID, email string, expectedLen int, history []models.Message) error {
+	filter := bson.M{"_id": debateID, "email": email, "outcome": notJudged}
+	filter["history."+strconv.Itoa(expectedLen)] = bson.M{"$exists": false}
+	if expectedLen > 0 {
+		filter["history."+strconv.Itoa(expectedLen-1)] = bson.M{"$exists": true}
+	}
+	result, err := DebateVsBotCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"history": history}})
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
 	return nil
 }
 
+// SetDebateVsBotOutcome records the judge's result for a user's bot debate unless it has
+// already been judged, reporting whether it was recorded
+func SetDebateVsBotOutcome(ctx context.Context, debateID primitive.ObjectID, email, outcome string) (bool, error) {
+	filter := bson.M{"_id": debateID, "email": email, "outcome": notJudged}
+	result, err := DebateVsBotCollection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"outcome": outcome}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
 // GetLatestDebateVsBot retrieves the most recent bot debate for a user
 func GetLatestDebateVsBot(email string) (*models.DebateVsBot, error) {
 	filter := bson.M{"email": email}
@@ -0,0 +1,324 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ChatMessage is a message in a debate's spectator chat. Spectator chat is carried on the
+// debate's event stream only, so it never reaches the debaters' room. The sender's hash
+// stays on the server; moderators name a spectator by one of their message IDs.
+type ChatMessage struct {
+	ID            string `json:"id"`
+	SpectatorHash string `json:"-"`
+	Name          string `json:"name"`
+	Text          string `json:"text"`
+	Timestamp     int64  `json:"timestamp"` // Unix milliseconds
+}
+
+// storedChatMessage is a chat message as kept in Redis, sender included
+type storedChatMessage struct {
+	ChatMessage
+	SpectatorHash string `json:"spectatorHash"`
+}
+
+// ChatSettings are a debate's spectator chat rules, set by the host or a moderator
+type ChatSettings struct {
+	SlowModeSeconds int      `json:"slowModeSeconds"` // Minimum gap between one spectator's messages; zero disables slow mode
+	BlockedWords    []string `json:"blockedWords"`    // Masked in messages, on top of the configured words
+	BlockLinks      bool     `json:"blockLinks"`      // Reject messages that contain links
+}
+
+// ChatRestriction is a moderator's mute or ban of a spectator in a debate's chat
+type ChatRestriction struct {
+	Banned     bool   `json:"banned"`
+	MutedUntil int64  `json:"mutedUntil,omitempty"` // Unix milliseconds
+	MessageID  string `json:"messageId,omitempty"`  // Message the moderator acted on; names the spectator once a ban has removed their messages
+}
+
+// Active reports whether the restriction still stops the spectator from chatting
+func (r ChatRestriction) Active(now time.Time) bool {
+	return r.Banned || r.MutedUntil > now.UnixMilli()
+}
+
+// ChatConfig holds the server-wide spectator chat settings
+type ChatConfig struct {
+	HistoryLength int      // Messages kept per debate for spectators who join late
+	BlockedWords  []string // Masked in every debate's chat
+}
+
+// DefaultChatConfig returns the chat settings used when none are configured
+func DefaultChatConfig() ChatConfig {
+	return ChatConfig{HistoryLength: 50}
+}
+
+var (
+	chatConfig   = DefaultChatConfig()
+	chatConfigMu sync.RWMutex
+)
+
+// SetChatConfig overrides the server-wide chat settings. A zero history length keeps the default.
+func SetChatConfig(config ChatConfig) {
+	if config.HistoryLength <= 0 {
+		config.HistoryLength = DefaultChatConfig().HistoryLength
+	}
+	chatConfigMu.Lock()
+	defer chatConfigMu.Unlock()
+	chatConfig = config
+}
+
+// GetChatConfig returns the server-wide chat settings in effect
+func GetChatConfig() ChatConfig {
+	chatConfigMu.RLock()
+	defer chatConfigMu.RUnlock()
+	return chatConfig
+}
+
+// linkPattern matches URLs and bare domains with a common top-level domain
+var linkPattern = regexp.MustCompile(`(?i)(\b[a-z][a-z0-9+.-]*://|\bwww\.|\b[a-z0-9-]+\.(com|net|org|io|co|me|ly|gg|tv|app|dev|info|xyz|biz|link)\b)`)
+
+// ContainsLink reports whether a chat message contains a link
+func ContainsLink(text string) bool {
+	return linkPattern.MatchString(text)
+}
+
+// FilterChatText masks every whole-word, case-insensitive occurrence of a blocked word
+func FilterChatText(text string, blockedWords []string) string {
+	for _, word := range blockedWords {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)
+		if err != nil {
+			continue
+		}
+		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return strings.Repeat("*", len([]rune(match)))
+		})
+	}
+	return text
+}
+
+// ChatStore keeps a debate's recent spectator chat, its chat settings and restrictions
+type ChatStore interface {
+	// AppendMessage adds a message to the debate's history, dropping the oldest beyond the history length
+	AppendMessage(debateID string, message ChatMessage) error
+	// History returns the debate's recent messages, oldest first
+	History(debateID string) ([]ChatMessage, error)
+	// DeleteMessage removes a message from the history and reports whether it was there
+	DeleteMessage(debateID, messageID string) (bool, error)
+	// DeleteMessagesFrom removes a spectator's messages from the history and returns their IDs
+	DeleteMessagesFrom(debateID, spectatorHash string) ([]string, error)
+	// MessageSender returns the spectator hash behind a message in the history or a
+	// restriction placed over it, or "" if the message is unknown
+	MessageSender(debateID, messageID string) (string, error)
+	// GetSettings returns the debate's chat settings
+	GetSettings(debateID string) (ChatSettings, error)
+	// SetSettings replaces the debate's chat settings
+	SetSettings(debateID string, settings ChatSettings) error
+	// Restrict mutes or bans a spectator in the debate's chat
+	Restrict(debateID, spectatorHash string, restriction ChatRestriction) error
+	// Unrestrict lifts a spectator's mute or ban
+	Unrestrict(debateID, spectatorHash string) error
+	// Restriction returns a spectator's mute or ban, if any
+	Restriction(debateID, spectatorHash string) (ChatRestriction, error)
+}
+
+// chatKeyTTL keeps chat state from outliving a debate that never ended cleanly
+const chatKeyTTL = 24 * time.Hour
+
+// NewChatStore returns the Redis chat store when Redis is configured and the in-process
+// one otherwise
+func NewChatStore() ChatStore {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisChatStore{rdb: rdb, ctx: GetContext()}
+	}
+	return memoryChat
+}
+
+// RedisChatStore keeps chat state in Redis so every instance sees the same history and rules
+type RedisChatStore struct {
+	rdb *redis.Client
+	ctx context.Context
+}
+
+func chatMessagesKey(debateID string) string {
+	return fmt.Sprintf("debate:%s:chat:messages", debateID)
+}
+
+func chatSettingsKey(debateID string) string {
+	return fmt.Sprintf("debate:%s:chat:settings", debateID)
+}
+
+func chatRestrictionsKey(debateID string) string {
+	return fmt.Sprintf("debate:%s:chat:restrictions", debateID)
+}
+
+// AppendMessage adds a message to the debate's history
+func (cs *RedisChatStore) AppendMessage(debateID string, message ChatMessage) error {
+	data, err := json.Marshal(storedChatMessage{ChatMessage: message, SpectatorHash: message.SpectatorHash})
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat message: %w", err)
+	}
+	key := chatMessagesKey(debateID)
+	pipe := cs.rdb.TxPipeline()
+	pipe.RPush(cs.ctx, key, data)
+	pipe.LTrim(cs.ctx, key, int64(-GetChatConfig().HistoryLength), -1)
+	pipe.Expire(cs.ctx, key, chatKeyTTL)
+	if _, err := pipe.Exec(cs.ctx); err != nil {
+		return fmt.Errorf("failed to store chat message: %w", err)
+	}
+	return nil
+}
+
+// rawHistory returns the stored messages alongside their encoded form
+func (cs *RedisChatStore) rawHistory(debateID string) ([]string, []ChatMessage, error) {
+	raw, err := cs.rdb.LRange(cs.ctx, chatMessagesKey(debateID), 0, -1).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read chat history: %w", err)
+	}
+	messages := make([]ChatMessage, len(raw))
+	for i, data := range raw {
+		var stored storedChatMessage
+		if err := json.Unmarshal([]byte(data), &stored); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode chat message: %w", err)
+		}
+		messages[i] = stored.ChatMessage
+		messages[i].SpectatorHash = stored.SpectatorHash
+	}
+	return raw, messages, nil
+}
+
+// History returns the debate's recent messages, oldest first
+func (cs *RedisChatStore) History(debateID string) ([]ChatMessage, error) {
+	_, messages, err := cs.rawHistory(debateID)
+	return messages, err
+}
+
+// deleteWhere removes every stored message that matches and returns their IDs
+func (cs *RedisChatStore) deleteWhere(debateID string, match func(ChatMessage) bool) ([]string, error) {
+	raw, messages, err := cs.rawHistory(debateID)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for i, message := range messages {
+		if !match(message) {
+			continue
+		}
+		if err := cs.rdb.LRem(cs.ctx, chatMessagesKey(debateID), 1, raw[i]).Err(); err != nil {
+			return deleted, fmt.Errorf("failed to delete chat message: %w", err)
+		}
+		deleted = append(deleted, message.ID)
+	}
+	return deleted, nil
+}
+
+// DeleteMessage removes a message from the history and reports whether it was there
+func (cs *RedisChatStore) DeleteMessage(debateID, messageID string) (bool, error) {
+	deleted, err := cs.deleteWhere(debateID, func(m ChatMessage) bool { return m.ID == messageID })
+	return len(deleted) > 0, err
+}
+
+// DeleteMessagesFrom removes a spectator's messages from the history and returns their IDs
+func (cs *RedisChatStore) DeleteMessagesFrom(debateID, spectatorHash string) ([]string, error) {
+	return cs.deleteWhere(debateID, func(m ChatMessage) bool { return m.SpectatorHash == spectatorHash })
+}
+
+// MessageSender returns the spectator hash behind a message in the history or a
+// restriction placed over it
+func (cs *RedisChatStore) MessageSender(debateID, messageID string) (string, error) {
+	_, messages, err := cs.rawHistory(debateID)
+	if err != nil {
+		return "", err
+	}
+	for _, message := range messages {
+		if message.ID == messageID {
+			return message.SpectatorHash, nil
+		}
+	}
+	restrictions, err := cs.rdb.HGetAll(cs.ctx, chatRestrictionsKey(debateID)).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read chat restrictions: %w", err)
+	}
+	for spectatorHash, data := range restrictions {
+		var restriction ChatRestriction
+		if json.Unmarshal([]byte(data), &restriction) == nil && restriction.MessageID == messageID {
+			return spectatorHash, nil
+		}
+	}
+	return "", nil
+}
+
+// GetSettings returns the debate's chat settings
+func (cs *RedisChatStore) GetSettings(debateID string) (ChatSettings, error) {
+	var settings ChatSettings
+	data, err := cs.rdb.Get(cs.ctx, chatSettingsKey(debateID)).Bytes()
+	if err == redis.Nil {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, fmt.Errorf("failed to read chat settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, fmt.Errorf("failed to decode chat settings: %w", err)
+	}
+	return settings, nil
+}
+
+// SetSettings replaces the debate's chat settings
+func (cs *RedisChatStore) SetSettings(debateID string, settings ChatSettings) error {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat settings: %w", err)
+	}
+	if err := cs.rdb.Set(cs.ctx, chatSettingsKey(debateID), data, chatKeyTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store chat settings: %w", err)
+	}
+	return nil
+}
+
+// Restrict mutes or bans a spectator in the debate's chat
+func (cs *RedisChatStore) Restrict(debateID, spectatorHash string, restriction ChatRestriction) error {
+	data, err := json.Marshal(restriction)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat restriction: %w", err)
+	}
+	key := chatRestrictionsKey(debateID)
+	pipe := cs.rdb.TxPipeline()
+	pipe.HSet(cs.ctx, key, spectatorHash, data)
+	pipe.Expire(cs.ctx, key, chatKeyTTL)
+	if _, err := pipe.Exec(cs.ctx); err != nil {
+		return fmt.Errorf("failed to store chat restriction: %w", err)
+	}
+	return nil
+}
+
+// Unrestrict lifts a spectator's mute or ban
+func (cs *RedisChatStore) Unrestrict(debateID, spectatorHash string) error {
+	return cs.rdb.HDel(cs.ctx, chatRestrictionsKey(debateID), spectatorHash).Err()
+}
+
+// Restriction returns a spectator's mute or ban, if any
+func (cs *RedisChatStore) Restriction(debateID, spectatorHash string) (ChatRestriction, error) {
+	var restriction ChatRestriction
+	data, err := cs.rdb.HGet(cs.ctx, chatRestrictionsKey(debateID), spectatorHash).Bytes()
+	if err == redis.Nil {
+		return restriction, nil
+	}
+	if err != nil {
+		return restriction, fmt.Errorf("failed to read chat restriction: %w", err)
+	}
+	if err := json.Unmarshal(data, &restriction); err != nil {
+		return restriction, fmt.Errorf("failed to decode chat restriction: %w", err)
+	}
+	return restriction, nil
+}
@@ -0,0 +1,138 @@
+package debate
+
+import "sync"
+
+// memoryChat is the process-wide chat store used when Redis is not configured
+var memoryChat = NewMemoryChatStore()
+
+// MemoryChatStore keeps chat state in process memory for single-instance deployments and tests
+type MemoryChatStore struct {
+	mu           sync.Mutex
+	messages     map[string][]ChatMessage
+	settings     map[string]ChatSettings
+	restrictions map[string]map[string]ChatRestriction // debate ID -> spectator hash -> restriction
+}
+
+// NewMemoryChatStore creates an empty in-process chat store
+func NewMemoryChatStore() *MemoryChatStore {
+	return &MemoryChatStore{
+		messages:     make(map[string][]ChatMessage),
+		settings:     make(map[string]ChatSettings),
+		restrictions: make(map[string]map[string]ChatRestriction),
+	}
+}
+
+// AppendMessage adds a message to the debate's history
+func (cs *MemoryChatStore) AppendMessage(debateID string, message ChatMessage) error {
+	limit := GetChatConfig().HistoryLength
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	messages := append(cs.messages[debateID], message)
+	if len(messages) > limit {
+		messages = append([]ChatMessage(nil), messages[len(messages)-limit:]...)
+	}
+	cs.messages[debateID] = messages
+	return nil
+}
+
+// History returns the debate's recent messages, oldest first
+func (cs *MemoryChatStore) History(debateID string) ([]ChatMessage, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return append([]ChatMessage(nil), cs.messages[debateID]...), nil
+}
+
+// deleteWhere removes every stored message that matches and returns their IDs
+func (cs *MemoryChatStore) deleteWhere(debateID string, match func(ChatMessage) bool) []string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	kept := make([]ChatMessage, 0, len(cs.messages[debateID]))
+	var deleted []string
+	for _, message := range cs.messages[debateID] {
+		if match(message) {
+			deleted = append(deleted, message.ID)
+		} else {
+			kept = append(kept, message)
+		}
+	}
+	cs.messages[debateID] = kept
+	return deleted
+}
+
+// DeleteMessage removes a message from the history and reports whether it was there
+func (cs *MemoryChatStore) DeleteMessage(debateID, messageID string) (bool, error) {
+	return len(cs.deleteWhere(debateID, func(m ChatMessage) bool { return m.ID == messageID })) > 0, nil
+}
+
+// DeleteMessagesFrom removes a spectator's messages from the history and returns their IDs
+func (cs *MemoryChatStore) DeleteMessagesFrom(debateID, spectatorHash string) ([]string, error) {
+	return cs.deleteWhere(debateID, func(m ChatMessage) bool { return m.SpectatorHash == spectatorHash }), nil
+}
+
+// MessageSender returns the spectator hash behind a message in the history or a
+// restriction placed over it
+func (cs *MemoryChatStore) MessageSender(debateID, messageID string) (string, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	for _, message := range cs.messages[debateID] {
+		if message.ID == messageID {
+			return message.SpectatorHash, nil
+		}
+	}
+	for spectatorHash, restriction := range cs.restrictions[debateID] {
+		if restriction.MessageID == messageID {
+			return spectatorHash, nil
+		}
+	}
+	return "", nil
+}
+
+// GetSettings returns the debate's chat settings
+func (cs *MemoryChatStore) GetSettings(debateID string) (ChatSettings, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.settings[debateID], nil
+}
+
+// SetSettings replaces the debate's chat settings
+func (cs *MemoryChatStore) SetSettings(debateID string, settings ChatSettings) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.settings[debateID] = settings
+	return nil
+}
+
+// Restrict mutes or bans a spectator in the debate's chat
+func (cs *MemoryChatStore) Restrict(debateID, spectatorHash string, restriction ChatRestriction) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.restrictions[debateID] == nil {
+		cs.restrictions[debateID] = make(map[string]ChatRestriction)
+	}
+	cs.restrictions[debateID][spectatorHash] = restriction
+	return nil
+}
+
+// Unrestrict lifts a spectator's mute or ban
+func (cs *MemoryChatStore) Unrestrict(debateID, spectatorHash string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.restrictions[debateID], spectatorHash)
+	return nil
+}
+
+// Restriction returns a spectator's mute or ban, if any
+func (cs *MemoryChatStore) Restriction(debateID, spectatorHash string) (ChatRestriction, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.restrictions[debateID][spectatorHash], nil
+}
+
+// forget drops a debate's chat state
+func (cs *MemoryChatStore) forget(debateID string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	delete(cs.messages, debateID)
+	delete(cs.settings, debateID)
+	delete(cs.restrictions, debateID)
+}
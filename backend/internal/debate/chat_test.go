@@ -0,0 +1,95 @@
+package debate
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilterChatText(t *testing.T) {
+	got := FilterChatText("Darn it, DARN that darnation", []string{"darn", " "})
+	if want := "**** it, **** that darnation"; got != want {
+		t.Errorf("FilterChatText = %q, want %q", got, want)
+	}
+}
+
+func TestContainsLink(t *testing.T) {
+	for text, want := range map[string]bool{
+		"see https://example.org/x": true,
+		"go to www.example":         true,
+		"visit spam.com now":        true,
+		"that is e.g. wrong":        false,
+		"a fair point, well made":   false,
+	} {
+		if got := ContainsLink(text); got != want {
+			t.Errorf("ContainsLink(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestMemoryChatStoreHistory(t *testing.T) {
+	SetChatConfig(ChatConfig{HistoryLength: 3})
+	defer SetChatConfig(DefaultChatConfig())
+
+	store := NewMemoryChatStore()
+	for i := 0; i < 5; i++ {
+		hash := "s1"
+		if i%2 == 1 {
+			hash = "s2"
+		}
+		store.AppendMessage("d1", ChatMessage{ID: fmt.Sprint(i), SpectatorHash: hash})
+	}
+	history, _ := store.History("d1")
+	if len(history) != 3 || history[0].ID != "2" || history[2].ID != "4" {
+		t.Fatalf("history = %+v, want the last 3 messages oldest first", history)
+	}
+
+	if deleted, _ := store.DeleteMessage("d1", "3"); !deleted {
+		t.Error("DeleteMessage should report the message deleted")
+	}
+	store.DeleteMessagesFrom("d1", "s1")
+	if history, _ := store.History("d1"); len(history) != 0 {
+		t.Errorf("history after deletes = %+v, want empty", history)
+	}
+}
+
+func TestChatSenderStaysOnTheServer(t *testing.T) {
+	useMiniredis(t)
+	message := ChatMessage{ID: "m1", SpectatorHash: "s1", Name: "Guest", Text: "hi"}
+	data, _ := json.Marshal(message)
+	if strings.Contains(string(data), "s1\"") {
+		t.Errorf("encoded message %s carries the sender's hash", data)
+	}
+
+	for name, store := range map[string]ChatStore{"memory": NewMemoryChatStore(), "redis": NewChatStore()} {
+		store.AppendMessage("d1", message)
+		if sender, _ := store.MessageSender("d1", "m1"); sender != "s1" {
+			t.Errorf("%s: MessageSender = %q, want s1", name, sender)
+		}
+		store.Restrict("d1", "s1", ChatRestriction{Banned: true, MessageID: "m1"})
+		if deleted, _ := store.DeleteMessagesFrom("d1", "s1"); len(deleted) != 1 || deleted[0] != "m1" {
+			t.Errorf("%s: DeleteMessagesFrom = %v, want [m1]", name, deleted)
+		}
+		if sender, _ := store.MessageSender("d1", "m1"); sender != "s1" {
+			t.Errorf("%s: MessageSender after a ban = %q, want s1 from the restriction", name, sender)
+		}
+		if sender, _ := store.MessageSender("d1", "m2"); sender != "" {
+			t.Errorf("%s: MessageSender of an unknown message = %q, want empty", name, sender)
+		}
+	}
+}
+
+func TestChatRestrictionActive(t *testing.T) {
+	now := time.Now()
+	if (ChatRestriction{}).Active(now) {
+		t.Error("an empty restriction should not be active")
+	}
+	if !(ChatRestriction{Banned: true}).Active(now) {
+		t.Error("a ban should be active")
+	}
+	if (ChatRestriction{MutedUntil: now.Add(-time.Second).UnixMilli()}).Active(now) {
+		t.Error("an expired mute should not be active")
+	}
+}
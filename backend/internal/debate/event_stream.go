@@ -0,0 +1,164 @@
+package debate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventStream carries a debate's spectator events to every connected spectator and keeps
+// recent history for reconnect replay
+type EventStream interface {
+	// Publish appends an event to the debate's stream and sets its ID
+	Publish(debateID string, event *Event) error
+	// ReadSince returns up to maxReplayEvents events stored after lastEventID, oldest first
+	ReadSince(debateID, lastEventID string) ([]*Event, error)
+	// ReadAll returns every event kept for the debate, oldest first
+	ReadAll(debateID string) ([]*Event, error)
+	// Subscribe delivers every event published to the debate from now on to the hub
+	Subscribe(debateID string, hub DebateHub) error
+	// Unsubscribe stops delivering the debate's events to the hub
+	Unsubscribe(debateID string, hub DebateHub) error
+}
+
+// maxReplayEvents bounds how many missed events a reconnecting spectator is sent. The
+// stream itself is not trimmed: every event is kept until the debate is archived and its
+// state expires.
+const maxReplayEvents = 1000
+
+// memoryEvents is the process-wide event stream used when Redis is not configured
+var memoryEvents = NewMemoryEventStream()
+
+// GetEventStream returns the Redis event stream when Redis is configured and the
+// in-process one otherwise
+func GetEventStream() EventStream {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisEventStream{rdb: rdb, ctx: GetContext()}
+	}
+	return memoryEvents
+}
+
+// PublishEvent publishes an event to the debate's spectators
+func PublishEvent(debateID string, event *Event) error {
+	return GetEventStream().Publish(debateID, event)
+}
+
+// ReadEventsSince returns the events published after lastEventID, oldest first
+func ReadEventsSince(debateID, lastEventID string) ([]*Event, error) {
+	return GetEventStream().ReadSince(debateID, lastEventID)
+}
+
+// ReadAllEvents returns every event kept for a debate, oldest first
+func ReadAllEvents(debateID string) ([]*Event, error) {
+	return GetEventStream().ReadAll(debateID)
+}
+
+// MemoryEventStream keeps each debate's events in process memory and delivers them to
+// the hubs subscribed on this instance. IDs use the Redis Stream format, so spectators
+// deduplicate and replay them the same way.
+type MemoryEventStream struct {
+	mu          sync.Mutex
+	events      map[string][]*Event
+	subscribers map[string][]DebateHub
+	lastMillis  int64
+	sequence    int64
+}
+
+// NewMemoryEventStream creates an empty in-process event stream
+func NewMemoryEventStream() *MemoryEventStream {
+	return &MemoryEventStream{
+		events:      make(map[string][]*Event),
+		subscribers: make(map[string][]DebateHub),
+	}
+}
+
+// nextIDLocked returns a stream ID greater than every ID issued before.
+// The caller must hold s.mu.
+func (s *MemoryEventStream) nextIDLocked() string {
+	millis := time.Now().UnixMilli()
+	if millis > s.lastMillis {
+		s.lastMillis = millis
+		s.sequence = 0
+	} else {
+		s.sequence++
+	}
+	return fmt.Sprintf("%d-%d", s.lastMillis, s.sequence)
+}
+
+// Publish stores the event and delivers it to the debate's subscribers
+func (s *MemoryEventStream) Publish(debateID string, event *Event) error {
+	s.mu.Lock()
+	event.ID = s.nextIDLocked()
+	s.events[debateID] = append(s.events[debateID], event)
+	hubs := append([]DebateHub(nil), s.subscribers[debateID]...)
+	s.mu.Unlock()
+
+	for _, hub := range hubs {
+		hub.BroadcastToDebate(debateID, event)
+	}
+	return nil
+}
+
+// ReadSince returns the events stored after lastEventID, oldest first
+func (s *MemoryEventStream) ReadSince(debateID, lastEventID string) ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []*Event
+	for _, event := range s.events[debateID] {
+		if CompareEventIDs(event.ID, lastEventID) <= 0 {
+			continue
+		}
+		events = append(events, event)
+		if len(events) == maxReplayEvents {
+			break
+		}
+	}
+	return events, nil
+}
+
+// Subscribe delivers the debate's future events to the hub. Subscribing a hub twice
+// has no effect.
+func (s *MemoryEventStream) Subscribe(debateID string, hub DebateHub) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.subscribers[debateID] {
+		if existing == hub {
+			return nil
+		}
+	}
+	s.subscribers[debateID] = append(s.subscribers[debateID], hub)
+	return nil
+}
+
+// ReadAll returns every event kept for the debate, oldest first
+func (s *MemoryEventStream) ReadAll(debateID string) ([]*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Event(nil), s.events[debateID]...), nil
+}
+
+// Unsubscribe stops delivering the debate's events to the hub
+func (s *MemoryEventStream) Unsubscribe(debateID string, hub DebateHub) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hubs := s.subscribers[debateID]
+	for i, existing := range hubs {
+		if existing == hub {
+			hubs = append(hubs[:i], hubs[i+1:]...)
+			break
+		}
+	}
+	if len(hubs) == 0 {
+		delete(s.subscribers, debateID)
+	} else {
+		s.subscribers[debateID] = hubs
+	}
+	return nil
+}
+
+// forget drops a debate's stored events
+func (s *MemoryEventStream) forget(debateID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, debateID)
+}
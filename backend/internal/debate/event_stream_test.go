@@ -0,0 +1,76 @@
+package debate
+
+import "testing"
+
+type recordingHub struct {
+	events []*Event
+}
+
+func (h *recordingHub) BroadcastToDebate(debateID string, event *Event) {
+	h.events = append(h.events, event)
+}
+
+func TestMemoryEventStream(t *testing.T) {
+	stream := NewMemoryEventStream()
+	hub := &recordingHub{}
+	stream.Subscribe("d1", hub)
+	stream.Subscribe("d1", hub)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		event, _ := NewEvent("reaction", ReactionPayload{Reaction: "👍"})
+		if err := stream.Publish("d1", event); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if len(hub.events) != 3 {
+		t.Errorf("hub received %d events, want 3 delivered once each", len(hub.events))
+	}
+	if CompareEventIDs(ids[1], ids[0]) <= 0 || CompareEventIDs(ids[2], ids[1]) <= 0 {
+		t.Errorf("event IDs %v are not increasing", ids)
+	}
+
+	missed, _ := stream.ReadSince("d1", ids[0])
+	if len(missed) != 2 || missed[0].ID != ids[1] {
+		t.Errorf("ReadSince returned %d events, want the 2 after the first", len(missed))
+	}
+	if other, _ := stream.ReadSince("d2", ""); len(other) != 0 {
+		t.Error("another debate's stream should be empty")
+	}
+}
+
+func TestMemoryEventStreamUnsubscribe(t *testing.T) {
+	stream := NewMemoryEventStream()
+	hub := &recordingHub{}
+	stream.Subscribe("d1", hub)
+	stream.Unsubscribe("d1", hub)
+
+	event, _ := NewEvent("reaction", ReactionPayload{Reaction: "👏"})
+	stream.Publish("d1", event)
+	if len(hub.events) != 0 {
+		t.Errorf("an unsubscribed hub received %d events", len(hub.events))
+	}
+	if all, _ := stream.ReadAll("d1"); len(all) != 1 {
+		t.Errorf("ReadAll returned %d events, want the published one kept", len(all))
+	}
+	stream.forget("d1")
+	if all, _ := stream.ReadAll("d1"); len(all) != 0 {
+		t.Error("a forgotten debate should have no events")
+	}
+}
+
+func TestMemoryEventStreamKeepsTheWholeDebate(t *testing.T) {
+	stream := NewMemoryEventStream()
+	const published = 10001
+	for i := 0; i < published; i++ {
+		event, _ := NewEvent("reaction", ReactionPayload{Reaction: "👍"})
+		stream.Publish("d1", event)
+	}
+	if all, _ := stream.ReadAll("d1"); len(all) != published {
+		t.Errorf("ReadAll returned %d events, want all %d for the archive", len(all), published)
+	}
+	if replay, _ := stream.ReadSince("d1", ""); len(replay) != maxReplayEvents {
+		t.Errorf("ReadSince returned %d events, want the replay limit of %d", len(replay), maxReplayEvents)
+	}
+}
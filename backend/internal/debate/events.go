@@ -2,21 +2,23 @@ package debate
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // Event represents a debate event published to Redis Stream
 type Event struct {
+	ID        string          `json:"id,omitempty"` // Redis Stream entry ID, set once the event is in the stream
 	Type      string          `json:"type"`
 	Payload   json.RawMessage `json:"payload"`
 	Timestamp int64           `json:"timestamp"`
 }
 
-// VotePayload represents a vote event payload
+// VotePayload represents a vote event payload. It does not say who voted.
 type VotePayload struct {
 	PollID        string `json:"pollId"`
 	Option        string `json:"option"`
-	SpectatorHash string `json:"spectatorHash"`
 	ClientEventID string `json:"clientEventId"`
 	Timestamp     int64  `json:"timestamp"`
 }
@@ -35,24 +37,112 @@ type PollCreatedPayload struct {
 	PollID    string   `json:"pollId"`
 	Question  string   `json:"question"`
 	Options   []string `json:"options"`
+	Status    string   `json:"status,omitempty"`
+	ClosesAt  int64    `json:"closesAt,omitempty"`
 	Timestamp int64    `json:"timestamp"`
 }
 
-// QuestionPayload represents a question event payload
+// PollStatusPayload represents a poll being opened or closed. Closed polls carry their
+// final results.
+type PollStatusPayload struct {
+	PollID    string           `json:"pollId"`
+	Status    string           `json:"status"`
+	ClosesAt  int64            `json:"closesAt,omitempty"`
+	Counts    map[string]int64 `json:"counts,omitempty"`
+	Voters    int64            `json:"voters,omitempty"`
+	Timestamp int64            `json:"timestamp"`
+}
+
+// QuestionPayload represents a question event payload. Questions are only broadcast once
+// approved; the spectator who asked is told about their pending question directly.
 type QuestionPayload struct {
-	QID           string `json:"qId"`
-	Text          string `json:"text"`
-	SpectatorHash string `json:"spectatorHash"`
-	Timestamp     int64  `json:"timestamp"`
+	QID       string `json:"qId"`
+	Text      string `json:"text"`
+	Status    string `json:"status,omitempty"`
+	Upvotes   int    `json:"upvotes"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// QuestionUpvotePayload represents an upvote request from a spectator
+type QuestionUpvotePayload struct {
+	QID string `json:"qId"`
+}
+
+// QuestionUpdatedPayload represents a change to a question in the Q&A queue: an upvote,
+// a moderation decision, the question being put to the debaters or answered
+type QuestionUpdatedPayload struct {
+	QID       string `json:"qId"`
+	Status    string `json:"status"`
+	Upvotes   int    `json:"upvotes"`
+	Asked     bool   `json:"asked,omitempty"`
+	Timestamp int64  `json:"timestamp"`
 }
 
 // ReactionPayload represents a reaction event payload
 type ReactionPayload struct {
 	Reaction      string `json:"reaction"`
+	Side          string `json:"side,omitempty"` // Side the reaction is for: "for", "against" or empty
 	SpectatorHash string `json:"spectatorHash"`
 	Timestamp     int64  `json:"timestamp"`
 }
 
+// ReactionBurstPayload carries the reactions received in one aggregation bucket. It
+// replaces one event per reaction for large audiences.
+type ReactionBurstPayload struct {
+	Counts    map[string]int64 `json:"counts"` // reaction -> count
+	Sides     map[string]int64 `json:"sides"`  // "for", "against" or "none" -> count
+	Total     int64            `json:"total"`
+	From      int64            `json:"from"` // Unix milliseconds of the first reaction in the burst
+	To        int64            `json:"to"`   // Unix milliseconds of the last reaction in the burst
+	Timestamp int64            `json:"timestamp"`
+}
+
+// ChatSendPayload is a spectator's chat message as sent by the client
+type ChatSendPayload struct {
+	Text string `json:"text"`
+}
+
+// ChatModeratePayload is a host or moderator's action on the spectator chat. Every action
+// names a message; mutes and bans apply to the spectator who sent it.
+type ChatModeratePayload struct {
+	Action          string `json:"action"` // "delete", "mute", "unmute", "ban" or "unban"
+	MessageID       string `json:"messageId"`
+	DurationSeconds int    `json:"durationSeconds,omitempty"` // How long a mute lasts
+}
+
+// ChatDeletedPayload removes chat messages: one message, or every message from a banned spectator
+type ChatDeletedPayload struct {
+	MessageID  string   `json:"messageId,omitempty"`
+	MessageIDs []string `json:"messageIds,omitempty"`
+	Timestamp  int64    `json:"timestamp"`
+}
+
+// ChatRestrictedPayload announces that the sender of a message was muted, banned or let
+// back into the chat
+type ChatRestrictedPayload struct {
+	MessageID  string `json:"messageId"`
+	Banned     bool   `json:"banned"`
+	MutedUntil int64  `json:"mutedUntil,omitempty"` // Unix milliseconds
+	Timestamp  int64  `json:"timestamp"`
+}
+
+// CommentaryClaim is a key claim picked out by the AI commentator
+type CommentaryClaim struct {
+	Side  string `json:"side"` // "for" or "against"
+	Claim string `json:"claim"`
+}
+
+// CommentaryPayload is the AI commentator's neutral analysis of a finished phase. It is
+// delivered to spectators only.
+type CommentaryPayload struct {
+	Phase     string            `json:"phase"`
+	Summary   string            `json:"summary"`
+	KeyClaims []CommentaryClaim `json:"keyClaims"`
+	Clashes   []string          `json:"clashes"`
+	Leading   string            `json:"leading"` // "for", "against" or "even"
+	Timestamp int64             `json:"timestamp"`
+}
+
 // PollSnapshotPayload represents a poll snapshot event payload
 type PollSnapshotPayload struct {
 	PollState   map[string]map[string]int64 `json:"pollState"`   // pollId -> option -> count
@@ -69,6 +159,7 @@ type PresencePayload struct {
 type JoinPayload struct {
 	SpectatorID   string `json:"spectatorId,omitempty"`
 	SpectatorHash string `json:"spectatorHash,omitempty"`
+	LastEventID   string `json:"lastEventId,omitempty"` // Last stream ID seen before reconnecting
 }
 
 // ClientMessage represents a message from client
@@ -108,3 +199,39 @@ func UnmarshalEvent(data string) (*Event, error) {
 	}
 	return &event, nil
 }
+
+// CompareEventIDs orders two Redis Stream IDs ("<ms>-<seq>"). It returns -1, 0 or 1.
+// An empty ID sorts before every other ID.
+func CompareEventIDs(a, b string) int {
+	aMs, aSeq := splitEventID(a)
+	bMs, bSeq := splitEventID(b)
+	switch {
+	case aMs < bMs:
+		return -1
+	case aMs > bMs:
+		return 1
+	case aSeq < bSeq:
+		return -1
+	case aSeq > bSeq:
+		return 1
+	}
+	return 0
+}
+
+// splitEventID parses a stream ID into its millisecond and sequence parts
+func splitEventID(id string) (uint64, uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ := strconv.ParseUint(msPart, 10, 64)
+	seq, _ := strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// IsReplayableEvent reports whether a stream event is replayed to reconnecting spectators.
+// Single reactions are left out; their bursts already carry them.
+func IsReplayableEvent(eventType string) bool {
+	switch eventType {
+	case "question", "question_updated", "reaction_burst", "vote", "poll_created", "poll_opened", "poll_closed", "commentary":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package debate
+
+import "testing"
+
+func TestCompareEventIDs(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1700000000000-0", "1700000000000-0", 0},
+		{"1700000000000-1", "1700000000000-0", 1},
+		{"999-5", "1000-0", -1},
+		{"1700000000000-10", "1700000000000-9", 1},
+		{"", "1-0", -1},
+	}
+	for _, tc := range cases {
+		if got := CompareEventIDs(tc.a, tc.b); got != tc.want {
+			t.Errorf("CompareEventIDs(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
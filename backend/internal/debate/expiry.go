@@ -0,0 +1,62 @@
+package debate
+
+import (
+	"fmt"
+	"time"
+)
+
+// debateStateKeys returns the Redis keys kept for a debate: its event stream, live entry,
+// spectator count, chat and, for each poll listed in the debate's poll set, the poll's
+// state, counts, voters and metadata. Spectator connection and anonymous vote counters
+// expire on their own, and reactions are only kept in rate limit windows.
+func debateStateKeys(debateID string, pollIDs []string) []string {
+	keys := []string{
+		fmt.Sprintf("debate:%s:events", debateID),
+		liveDebateKey(debateID),
+		spectatorCountKey(debateID),
+		chatMessagesKey(debateID),
+		chatSettingsKey(debateID),
+		chatRestrictionsKey(debateID),
+		fmt.Sprintf("debate:%s:polls", debateID),
+	}
+	for _, pollID := range pollIDs {
+		keys = append(keys,
+			pollStateKey(debateID, pollID),
+			fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID),
+			fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID),
+			fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID),
+		)
+	}
+	return keys
+}
+
+// ExpireDebateState lets everything kept for an ended debate (its event stream, polls,
+// voters, spectator count and chat) expire after ttl. The grace period keeps results and
+// replay available to spectators who are still connected once the debate is archived.
+func ExpireDebateState(debateID string, ttl time.Duration) error {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		time.AfterFunc(ttl, func() {
+			memoryEvents.forget(debateID)
+			memoryPolls.forget(debateID)
+			memoryChat.forget(debateID)
+			memorySpectators.forget(debateID)
+		})
+		return nil
+	}
+
+	ctx := GetContext()
+	pollIDs, err := rdb.SMembers(ctx, fmt.Sprintf("debate:%s:polls", debateID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list debate polls: %w", err)
+	}
+
+	pipe := rdb.Pipeline()
+	for _, key := range debateStateKeys(debateID, pollIDs) {
+		pipe.Expire(ctx, key, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to expire debate keys: %w", err)
+	}
+	return nil
+}
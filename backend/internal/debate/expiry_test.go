@@ -0,0 +1,43 @@
+package debate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpireDebateState(t *testing.T) {
+	server := useMiniredis(t)
+	ps := NewPollStore()
+	pollID, err := ps.CreatePoll("d1", "", "Who won?", []string{"for", "against"}, PollSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ps.CreatePoll("d2", "", "Who won?", []string{"for", "against"}, PollSettings{}); err != nil {
+		t.Fatal(err)
+	}
+	server.Set(chatMessagesKey("d1"), "[]")
+	server.Set(liveDebateKey("d1"), "1")
+
+	if err := ExpireDebateState("d1", time.Hour); err != nil {
+		t.Fatalf("Expected the debate's state to expire, got %v", err)
+	}
+
+	for _, key := range []string{
+		"debate:d1:polls",
+		"debate:d1:poll:" + pollID + ":meta",
+		chatMessagesKey("d1"),
+		liveDebateKey("d1"),
+	} {
+		if ttl := server.TTL(key); ttl != time.Hour {
+			t.Errorf("Expected %s to expire in 1h, got %v", key, ttl)
+		}
+	}
+	if ttl := server.TTL("debate:d2:polls"); ttl != 0 {
+		t.Errorf("Expected other debates to be kept, got a TTL of %v", ttl)
+	}
+
+	server.FastForward(time.Hour + time.Second)
+	if server.Exists("debate:d1:poll:" + pollID + ":meta") {
+		t.Error("Expected the poll to be gone once the grace period ended")
+	}
+}
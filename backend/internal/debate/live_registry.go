@@ -0,0 +1,173 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// LiveParticipant is a debater in a live debate
+type LiveParticipant struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Side      string `json:"side,omitempty"` // "for" or "against" once chosen
+	Rating    int    `json:"rating"`
+	AvatarURL string `json:"avatarUrl,omitempty"`
+}
+
+// LiveDebate is a debate that is running now, as listed in the live directory
+type LiveDebate struct {
+	DebateID     string            `json:"debateId"`
+	Topic        string            `json:"topic,omitempty"`
+	Participants []LiveParticipant `json:"participants"`
+	Phase        string            `json:"phase,omitempty"`
+	Spectators   int64             `json:"spectators"`
+	StartedAt    int64             `json:"startedAt"` // Unix milliseconds
+}
+
+// LiveRegistry tracks the debates that are running now and how many spectators watch
+// each of them
+type LiveRegistry interface {
+	// MarkLive lists a debate as live, keeping its start time if it is already listed,
+	// and records its current phase and debaters
+	MarkLive(debateID, phase string, participants []LiveParticipant) error
+	// SetTopic records a debate's topic, whether or not it is live yet
+	SetTopic(debateID, topic string) error
+	// Remove takes an ended debate out of the directory
+	Remove(debateID string) error
+	// AddSpectators adjusts a debate's spectator count by delta
+	AddSpectators(debateID string, delta int64) error
+	// List returns every live debate
+	List() ([]LiveDebate, error)
+}
+
+// liveDebateTTL drops a debate from the directory if the instance running it stops
+// updating it, for example because it crashed
+const liveDebateTTL = 6 * time.Hour
+
+// liveDebatesKey indexes live debates by start time
+const liveDebatesKey = "debates:live"
+
+// NewLiveRegistry returns the Redis registry when Redis is configured, so every instance
+// lists the same debates, and the in-process one otherwise
+func NewLiveRegistry() LiveRegistry {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisLiveRegistry{rdb: rdb, ctx: GetContext()}
+	}
+	return memoryLive
+}
+
+// RedisLiveRegistry keeps the live directory in Redis
+type RedisLiveRegistry struct {
+	rdb *redis.Client
+	ctx context.Context
+}
+
+func liveDebateKey(debateID string) string {
+	return fmt.Sprintf("debate:%s:live", debateID)
+}
+
+func spectatorCountKey(debateID string) string {
+	return fmt.Sprintf("debate:%s:spectators:count", debateID)
+}
+
+// MarkLive lists a debate as live and records its phase and debaters
+func (lr *RedisLiveRegistry) MarkLive(debateID, phase string, participants []LiveParticipant) error {
+	data, err := json.Marshal(participants)
+	if err != nil {
+		return fmt.Errorf("failed to marshal participants: %w", err)
+	}
+	now := time.Now().UnixMilli()
+	key := liveDebateKey(debateID)
+	pipe := lr.rdb.TxPipeline()
+	pipe.ZAddNX(lr.ctx, liveDebatesKey, redis.Z{Score: float64(now), Member: debateID})
+	pipe.HSetNX(lr.ctx, key, "startedAt", now)
+	pipe.HSet(lr.ctx, key, "live", 1, "phase", phase, "participants", data)
+	pipe.Expire(lr.ctx, key, liveDebateTTL)
+	if _, err := pipe.Exec(lr.ctx); err != nil {
+		return fmt.Errorf("failed to mark debate live: %w", err)
+	}
+	return nil
+}
+
+// SetTopic records a debate's topic
+func (lr *RedisLiveRegistry) SetTopic(debateID, topic string) error {
+	key := liveDebateKey(debateID)
+	pipe := lr.rdb.TxPipeline()
+	pipe.HSet(lr.ctx, key, "topic", topic)
+	pipe.Expire(lr.ctx, key, liveDebateTTL)
+	if _, err := pipe.Exec(lr.ctx); err != nil {
+		return fmt.Errorf("failed to set debate topic: %w", err)
+	}
+	return nil
+}
+
+// Remove takes an ended debate out of the directory
+func (lr *RedisLiveRegistry) Remove(debateID string) error {
+	pipe := lr.rdb.TxPipeline()
+	pipe.ZRem(lr.ctx, liveDebatesKey, debateID)
+	pipe.Del(lr.ctx, liveDebateKey(debateID))
+	if _, err := pipe.Exec(lr.ctx); err != nil {
+		return fmt.Errorf("failed to remove live debate: %w", err)
+	}
+	return nil
+}
+
+// AddSpectators adjusts a debate's spectator count by delta
+func (lr *RedisLiveRegistry) AddSpectators(debateID string, delta int64) error {
+	key := spectatorCountKey(debateID)
+	pipe := lr.rdb.TxPipeline()
+	pipe.IncrBy(lr.ctx, key, delta)
+	pipe.Expire(lr.ctx, key, spectatorCounterTTL)
+	_, err := pipe.Exec(lr.ctx)
+	return err
+}
+
+// List returns every live debate. Debates whose entry expired are dropped from the index.
+func (lr *RedisLiveRegistry) List() ([]LiveDebate, error) {
+	ids, err := lr.rdb.ZRange(lr.ctx, liveDebatesKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list live debates: %w", err)
+	}
+	if len(ids) == 0 {
+		return []LiveDebate{}, nil
+	}
+
+	pipe := lr.rdb.Pipeline()
+	entries := make([]*redis.MapStringStringCmd, len(ids))
+	counts := make([]*redis.StringCmd, len(ids))
+	for i, id := range ids {
+		entries[i] = pipe.HGetAll(lr.ctx, liveDebateKey(id))
+		counts[i] = pipe.Get(lr.ctx, spectatorCountKey(id))
+	}
+	if _, err := pipe.Exec(lr.ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read live debates: %w", err)
+	}
+
+	debates := make([]LiveDebate, 0, len(ids))
+	var stale []interface{}
+	for i, id := range ids {
+		fields := entries[i].Val()
+		if fields["live"] == "" {
+			stale = append(stale, id)
+			continue
+		}
+		live := LiveDebate{DebateID: id, Topic: fields["topic"], Phase: fields["phase"]}
+		live.StartedAt, _ = strconv.ParseInt(fields["startedAt"], 10, 64)
+		if err := json.Unmarshal([]byte(fields["participants"]), &live.Participants); err != nil {
+			live.Participants = []LiveParticipant{}
+		}
+		if count, err := counts[i].Int64(); err == nil && count > 0 {
+			live.Spectators = count
+		}
+		debates = append(debates, live)
+	}
+	if len(stale) > 0 {
+		lr.rdb.ZRem(lr.ctx, liveDebatesKey, stale...)
+	}
+	return debates, nil
+}
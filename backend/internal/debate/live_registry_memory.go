@@ -0,0 +1,86 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryLive is the process-wide live registry used when Redis is not configured
+var memoryLive = NewMemoryLiveRegistry()
+
+// MemoryLiveRegistry keeps the live directory in process memory for single-instance
+// deployments and tests
+type MemoryLiveRegistry struct {
+	mu         sync.Mutex
+	debates    map[string]*LiveDebate
+	topics     map[string]string
+	spectators map[string]int64
+}
+
+// NewMemoryLiveRegistry creates an empty in-process live registry
+func NewMemoryLiveRegistry() *MemoryLiveRegistry {
+	return &MemoryLiveRegistry{
+		debates:    make(map[string]*LiveDebate),
+		topics:     make(map[string]string),
+		spectators: make(map[string]int64),
+	}
+}
+
+// MarkLive lists a debate as live and records its phase and debaters
+func (lr *MemoryLiveRegistry) MarkLive(debateID, phase string, participants []LiveParticipant) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	live, ok := lr.debates[debateID]
+	if !ok {
+		live = &LiveDebate{DebateID: debateID, StartedAt: time.Now().UnixMilli()}
+		lr.debates[debateID] = live
+	}
+	live.Phase = phase
+	live.Participants = append([]LiveParticipant(nil), participants...)
+	return nil
+}
+
+// SetTopic records a debate's topic
+func (lr *MemoryLiveRegistry) SetTopic(debateID, topic string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.topics[debateID] = topic
+	return nil
+}
+
+// Remove takes an ended debate out of the directory
+func (lr *MemoryLiveRegistry) Remove(debateID string) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	delete(lr.debates, debateID)
+	delete(lr.topics, debateID)
+	return nil
+}
+
+// AddSpectators adjusts a debate's spectator count by delta
+func (lr *MemoryLiveRegistry) AddSpectators(debateID string, delta int64) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	count := lr.spectators[debateID] + delta
+	if count <= 0 {
+		delete(lr.spectators, debateID)
+	} else {
+		lr.spectators[debateID] = count
+	}
+	return nil
+}
+
+// List returns every live debate
+func (lr *MemoryLiveRegistry) List() ([]LiveDebate, error) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	debates := make([]LiveDebate, 0, len(lr.debates))
+	for id, live := range lr.debates {
+		entry := *live
+		entry.Participants = append([]LiveParticipant{}, live.Participants...)
+		entry.Topic = lr.topics[id]
+		entry.Spectators = lr.spectators[id]
+		debates = append(debates, entry)
+	}
+	return debates, nil
+}
@@ -0,0 +1,35 @@
+package debate
+
+import "testing"
+
+func TestMemoryLiveRegistry(t *testing.T) {
+	registry := NewMemoryLiveRegistry()
+	registry.SetTopic("d1", "This house would ban homework")
+	registry.AddSpectators("d1", 1)
+	registry.AddSpectators("d1", 1)
+	registry.AddSpectators("d1", -1)
+
+	if debates, _ := registry.List(); len(debates) != 0 {
+		t.Fatalf("a debate should not be listed before it is live, got %+v", debates)
+	}
+
+	registry.MarkLive("d1", "openingFor", []LiveParticipant{{ID: "u1", Side: "for", Rating: 1600}})
+	debates, _ := registry.List()
+	if len(debates) != 1 {
+		t.Fatalf("List returned %d debates, want 1", len(debates))
+	}
+	started := debates[0].StartedAt
+	if debates[0].Topic == "" || debates[0].Spectators != 1 || debates[0].Phase != "openingFor" {
+		t.Errorf("live debate = %+v", debates[0])
+	}
+
+	registry.MarkLive("d1", "openingAgainst", nil)
+	if debates, _ := registry.List(); debates[0].StartedAt != started || debates[0].Phase != "openingAgainst" {
+		t.Errorf("a phase change should keep the start time, got %+v", debates[0])
+	}
+
+	registry.Remove("d1")
+	if debates, _ := registry.List(); len(debates) != 0 {
+		t.Errorf("an ended debate should not be listed, got %+v", debates)
+	}
+}
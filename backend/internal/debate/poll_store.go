@@ -3,43 +3,154 @@ package debate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-// PollStore handles poll state operations in Redis
-type PollStore struct {
+// PollStore keeps the state of a debate's spectator polls
+type PollStore interface {
+	// CreatePoll creates a poll and returns its ID, generating one when pollID is empty. It
+	// returns ErrPollExists rather than replace a poll that already has the ID.
+	CreatePoll(debateID, pollID, question string, options []string, settings PollSettings) (string, error)
+	// Vote records a spectator's vote. Duplicate votes return false without an error.
+	Vote(debateID, pollID, option, spectatorHash string) (bool, error)
+	// OpenPoll starts voting on a draft poll
+	OpenPoll(debateID, pollID string) (*PollMetadata, error)
+	// ClosePoll freezes a poll's results and reports whether this call closed it
+	ClosePoll(debateID, pollID string) (*PollMetadata, bool, error)
+	// GetPoll returns a poll's metadata and lifecycle state
+	GetPoll(debateID, pollID string) (*PollMetadata, error)
+	// PollResults returns a poll's vote counts and number of voters
+	PollResults(debateID, pollID string) (map[string]int64, int64, error)
+	// GetPollState returns the counts, voter numbers and metadata of every poll in a debate
+	GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error)
+	// HasVoted reports whether a spectator has voted in a poll
+	HasVoted(debateID, pollID, spectatorHash string) (bool, error)
+}
+
+// RedisPollStore keeps poll state in Redis so every instance sees the same polls
+type RedisPollStore struct {
 	rdb *redis.Client
 	ctx context.Context
 }
 
+// Poll lifecycle states
+const (
+	PollStatusDraft  = "draft"  // Created but not yet accepting votes
+	PollStatusOpen   = "open"   // Accepting votes
+	PollStatusClosed = "closed" // Results are final
+)
+
+var (
+	// ErrPollNotFound is returned for a poll that does not exist in the debate
+	ErrPollNotFound = errors.New("poll not found")
+	// ErrPollExists is returned when creating a poll with the ID of an existing one
+	ErrPollExists = errors.New("poll already exists")
+	// ErrPollClosed is returned when voting on a poll that is not open
+	ErrPollClosed = errors.New("poll is not open")
+	// ErrInvalidPollOption is returned when voting for an option the poll does not have
+	ErrInvalidPollOption = errors.New("invalid poll option")
+)
+
 // PollMetadata represents metadata about a poll
 type PollMetadata struct {
-	PollID   string   `json:"pollId"`
-	Question string   `json:"question"`
-	Options  []string `json:"options"`
+	PollID          string   `json:"pollId"`
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+	CreatedBy       string   `json:"createdBy,omitempty"`
+	DurationSeconds int      `json:"durationSeconds,omitempty"` // Auto-close delay once the poll opens
+	// Lifecycle fields, kept in the poll's state hash so votes can check them atomically
+	Status   string `json:"status"`
+	OpenedAt int64  `json:"openedAt,omitempty"` // Unix milliseconds
+	ClosesAt int64  `json:"closesAt,omitempty"` // Unix milliseconds; zero for polls without a timer
+	ClosedAt int64  `json:"closedAt,omitempty"` // Unix milliseconds
 }
 
-// NewPollStore creates a new PollStore instance
-func NewPollStore() *PollStore {
-	return &PollStore{
-		rdb: GetRedisClient(),
-		ctx: GetContext(),
-	}
+// PollSettings configures a new poll
+type PollSettings struct {
+	Status          string // PollStatusDraft or PollStatusOpen (default)
+	DurationSeconds int    // Close automatically this long after opening; zero keeps it open until closed
+	CreatedBy       string // User ID of the host or moderator creating the poll
 }
 
-// CreatePoll creates a new poll with the provided question and options
-func (ps *PollStore) CreatePoll(debateID, pollID, question string, options []string) (string, error) {
-	if ps == nil || ps.rdb == nil {
-		return "", fmt.Errorf("Redis client not available")
+// createScript creates a poll unless one with the same ID exists, so a poll's votes are
+// never reset. ARGV holds the metadata, poll ID, status, open and close times, then the
+// options.
+var createScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then return 0 end
+redis.call('DEL', KEYS[2], KEYS[3], KEYS[4])
+redis.call('SET', KEYS[1], ARGV[1])
+redis.call('SADD', KEYS[5], ARGV[2])
+for i = 6, #ARGV do
+	redis.call('HSET', KEYS[3], ARGV[i], 0)
+end
+if ARGV[3] == 'open' then
+	redis.call('HSET', KEYS[4], 'status', 'open', 'openedAt', ARGV[4], 'closesAt', ARGV[5])
+else
+	redis.call('HSET', KEYS[4], 'status', ARGV[3])
+end
+return 1
+`)
+
+// voteScript records a vote only while the poll is open, before its close time, for an
+// existing option, and once per spectator. Polls created before lifecycle states have no
+// state hash and are treated as open.
+var voteScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if status and status ~= 'open' then return -1 end
+local closesAt = tonumber(redis.call('HGET', KEYS[1], 'closesAt') or '0')
+if closesAt and closesAt > 0 and tonumber(ARGV[3]) >= closesAt then return -1 end
+if redis.call('HEXISTS', KEYS[3], ARGV[2]) == 0 then return -2 end
+if redis.call('SADD', KEYS[2], ARGV[1]) == 0 then return 0 end
+redis.call('HINCRBY', KEYS[3], ARGV[2], 1)
+return 1
+`)
+
+// openScript moves a draft poll to open and sets its close time
+var openScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if not status then return -1 end
+if status ~= 'draft' then return 0 end
+redis.call('HSET', KEYS[1], 'status', 'open', 'openedAt', ARGV[1], 'closesAt', ARGV[2])
+return 1
+`)
+
+// closeScript closes a poll once; later calls leave the frozen results untouched
+var closeScript = redis.NewScript(`
+local status = redis.call('HGET', KEYS[1], 'status')
+if status == 'closed' then return 0 end
+if not status and redis.call('EXISTS', KEYS[2]) == 0 then return -1 end
+redis.call('HSET', KEYS[1], 'status', 'closed', 'closedAt', ARGV[1])
+return 1
+`)
+
+func pollStateKey(debateID, pollID string) string {
+	return fmt.Sprintf("debate:%s:poll:%s:state", debateID, pollID)
+}
+
+// NewPollStore returns the Redis poll store when Redis is configured and the in-process
+// store otherwise
+func NewPollStore() PollStore {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisPollStore{
+			rdb: rdb,
+			ctx: GetContext(),
+		}
 	}
+	return memoryPolls
+}
 
+// newPollMetadata validates a new poll and returns its metadata with the cleaned options
+// and its initial status
+func newPollMetadata(pollID, question string, options []string, settings PollSettings) (PollMetadata, string, error) {
 	question = strings.TrimSpace(question)
 	if question == "" {
-		return "", fmt.Errorf("question is required")
+		return PollMetadata{}, "", fmt.Errorf("question is required")
 	}
 
 	cleanOptions := make([]string, 0, len(options))
@@ -57,49 +168,91 @@ func (ps *PollStore) CreatePoll(debateID, pollID, question string, options []str
 	}
 
 	if len(cleanOptions) < 2 {
-		return "", fmt.Errorf("at least two unique options are required")
+		return PollMetadata{}, "", fmt.Errorf("at least two unique options are required")
 	}
 
 	if pollID == "" {
 		pollID = uuid.NewString()
 	}
 
+	status := settings.Status
+	if status == "" {
+		status = PollStatusOpen
+	}
+	if status != PollStatusDraft && status != PollStatusOpen {
+		return PollMetadata{}, "", fmt.Errorf("poll status must be draft or open")
+	}
+	if settings.DurationSeconds < 0 {
+		return PollMetadata{}, "", fmt.Errorf("poll duration cannot be negative")
+	}
+
+	return PollMetadata{
+		PollID:          pollID,
+		Question:        question,
+		Options:         cleanOptions,
+		CreatedBy:       settings.CreatedBy,
+		DurationSeconds: settings.DurationSeconds,
+	}, status, nil
+}
+
+// CreatePoll creates a new poll with the provided question and options. Open polls with a
+// duration get their close time immediately; draft polls get it when opened.
+func (ps *RedisPollStore) CreatePoll(debateID, pollID, question string, options []string, settings PollSettings) (string, error) {
+	if ps == nil || ps.rdb == nil {
+		return "", fmt.Errorf("Redis client not available")
+	}
+
+	metadata, status, err := newPollMetadata(pollID, question, options, settings)
+	if err != nil {
+		return "", err
+	}
+	pollID = metadata.PollID
+
 	countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
 	votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
 	metaKey := fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID)
 	pollsKey := fmt.Sprintf("debate:%s:polls", debateID)
 
-	metadata := PollMetadata{
-		PollID:   pollID,
-		Question: question,
-		Options:  cleanOptions,
-	}
 	metaBytes, err := json.Marshal(metadata)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal poll metadata: %w", err)
 	}
 
-	pipe := ps.rdb.TxPipeline()
-
-	// Initialize counts to zero for each option
-	countFields := make(map[string]interface{}, len(cleanOptions))
-	for _, opt := range cleanOptions {
-		countFields[opt] = 0
+	var openedAt, closesAt int64
+	if status == PollStatusOpen {
+		now := time.Now()
+		openedAt = now.UnixMilli()
+		closesAt = closeTime(now, settings.DurationSeconds)
+	}
+	args := []interface{}{string(metaBytes), pollID, status, openedAt, closesAt}
+	for _, opt := range metadata.Options {
+		args = append(args, opt)
 	}
-	pipe.Del(ps.ctx, votersKey)
-	pipe.HSet(ps.ctx, countsKey, countFields)
-	pipe.Set(ps.ctx, metaKey, metaBytes, 0)
-	pipe.SAdd(ps.ctx, pollsKey, pollID)
 
-	if _, err := pipe.Exec(ps.ctx); err != nil {
+	created, err := createScript.Run(ps.ctx, ps.rdb,
+		[]string{metaKey, votersKey, countsKey, pollStateKey(debateID, pollID), pollsKey},
+		args...,
+	).Int()
+	if err != nil {
 		return "", fmt.Errorf("failed to create poll: %w", err)
 	}
-
+	if created == 0 {
+		return "", ErrPollExists
+	}
 	return pollID, nil
 }
 
-// Vote handles a vote request and returns whether it was successful
-func (ps *PollStore) Vote(debateID, pollID, option, spectatorHash string) (bool, error) {
+// closeTime returns the auto-close time in Unix milliseconds, or zero without a timer
+func closeTime(openedAt time.Time, durationSeconds int) int64 {
+	if durationSeconds <= 0 {
+		return 0
+	}
+	return openedAt.Add(time.Duration(durationSeconds) * time.Second).UnixMilli()
+}
+
+// Vote handles a vote request and returns whether it was successful. Duplicate votes
+// return false without an error; votes on polls that are not open return ErrPollClosed.
+func (ps *RedisPollStore) Vote(debateID, pollID, option, spectatorHash string) (bool, error) {
 	if ps == nil || ps.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
@@ -107,29 +260,150 @@ func (ps *PollStore) Vote(debateID, pollID, option, spectatorHash string) (bool,
 	votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
 	countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
 
-	// Check if spectator already voted (using SET)
-	added, err := ps.rdb.SAdd(ps.ctx, votersKey, spectatorHash).Result()
+	// Check the poll state, record the voter and increment the count atomically
+	result, err := voteScript.Run(ps.ctx, ps.rdb,
+		[]string{pollStateKey(debateID, pollID), votersKey, countsKey},
+		spectatorHash, option, time.Now().UnixMilli(),
+	).Int()
 	if err != nil {
-		return false, fmt.Errorf("failed to add voter: %w", err)
+		return false, fmt.Errorf("failed to record vote: %w", err)
 	}
 
-	if added == 0 {
+	switch result {
+	case -1:
+		return false, ErrPollClosed
+	case -2:
+		return false, ErrInvalidPollOption
+	case 0:
 		// Duplicate vote
 		return false, nil
 	}
+	return true, nil
+}
 
-	// Increment poll count atomically
-	if err := ps.rdb.HIncrBy(ps.ctx, countsKey, option, 1).Err(); err != nil {
-		// Rollback voter add
-		ps.rdb.SRem(ps.ctx, votersKey, spectatorHash)
-		return false, fmt.Errorf("failed to increment count: %w", err)
+// OpenPoll starts voting on a draft poll and returns its updated metadata. Opening a poll
+// that is already open or closed changes nothing.
+func (ps *RedisPollStore) OpenPoll(debateID, pollID string) (*PollMetadata, error) {
+	if ps == nil || ps.rdb == nil {
+		return nil, fmt.Errorf("Redis client not available")
+	}
+	meta, err := ps.GetPoll(debateID, pollID)
+	if err != nil {
+		return nil, err
 	}
 
-	return true, nil
+	now := time.Now()
+	result, err := openScript.Run(ps.ctx, ps.rdb, []string{pollStateKey(debateID, pollID)},
+		now.UnixMilli(), closeTime(now, meta.DurationSeconds),
+	).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open poll: %w", err)
+	}
+	if result < 0 {
+		return nil, ErrPollNotFound
+	}
+	return ps.GetPoll(debateID, pollID)
+}
+
+// ClosePoll stops voting on a poll, freezing its results. It reports whether this call
+// closed the poll, so callers announce the close only once.
+func (ps *RedisPollStore) ClosePoll(debateID, pollID string) (*PollMetadata, bool, error) {
+	if ps == nil || ps.rdb == nil {
+		return nil, false, fmt.Errorf("Redis client not available")
+	}
+	metaKey := fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID)
+	result, err := closeScript.Run(ps.ctx, ps.rdb, []string{pollStateKey(debateID, pollID), metaKey},
+		time.Now().UnixMilli(),
+	).Int()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to close poll: %w", err)
+	}
+	if result < 0 {
+		return nil, false, ErrPollNotFound
+	}
+	meta, err := ps.GetPoll(debateID, pollID)
+	if err != nil {
+		return nil, false, err
+	}
+	return meta, result == 1, nil
+}
+
+// GetPoll returns a poll's metadata and lifecycle state
+func (ps *RedisPollStore) GetPoll(debateID, pollID string) (*PollMetadata, error) {
+	if ps == nil || ps.rdb == nil {
+		return nil, fmt.Errorf("Redis client not available")
+	}
+	metaKey := fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID)
+	metaStr, err := ps.rdb.Get(ps.ctx, metaKey).Result()
+	if err == redis.Nil {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var meta PollMetadata
+	if err := json.Unmarshal([]byte(metaStr), &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode poll metadata: %w", err)
+	}
+	if meta.PollID == "" {
+		meta.PollID = pollID
+	}
+	ps.loadLifecycle(debateID, &meta)
+	return &meta, nil
+}
+
+// loadLifecycle fills the lifecycle fields of meta from the poll's state hash. Polls
+// without one predate lifecycle states and count as open.
+func (ps *RedisPollStore) loadLifecycle(debateID string, meta *PollMetadata) {
+	state, err := ps.rdb.HGetAll(ps.ctx, pollStateKey(debateID, meta.PollID)).Result()
+	if err != nil || len(state) == 0 {
+		meta.Status = PollStatusOpen
+		return
+	}
+	meta.Status = state["status"]
+	fmt.Sscanf(state["openedAt"], "%d", &meta.OpenedAt)
+	fmt.Sscanf(state["closesAt"], "%d", &meta.ClosesAt)
+	fmt.Sscanf(state["closedAt"], "%d", &meta.ClosedAt)
+	applyPollDeadline(meta)
+}
+
+// applyPollDeadline reports a poll past its close time as closed: it no longer takes
+// votes even before its timer closes it
+func applyPollDeadline(meta *PollMetadata) {
+	if meta.Status == PollStatusOpen && meta.ClosesAt > 0 && time.Now().UnixMilli() >= meta.ClosesAt {
+		meta.Status = PollStatusClosed
+		meta.ClosedAt = meta.ClosesAt
+	}
+}
+
+// PollResults returns a poll's vote counts and number of voters
+func (ps *RedisPollStore) PollResults(debateID, pollID string) (map[string]int64, int64, error) {
+	if ps == nil || ps.rdb == nil {
+		return nil, 0, fmt.Errorf("Redis client not available")
+	}
+	countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
+	votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
+
+	raw, err := ps.rdb.HGetAll(ps.ctx, countsKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	counts := make(map[string]int64, len(raw))
+	for option, countStr := range raw {
+		var count int64
+		if _, err := fmt.Sscanf(countStr, "%d", &count); err == nil {
+			counts[option] = count
+		}
+	}
+	voters, err := ps.rdb.SCard(ps.ctx, votersKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	return counts, voters, nil
 }
 
 // GetPollState returns the current poll state for all polls in a debate
-func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
+func (ps *RedisPollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
 	if ps == nil || ps.rdb == nil {
 		return nil, nil, nil, fmt.Errorf("Redis client not available")
 	}
@@ -216,6 +490,7 @@ func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64,
 				if meta.PollID == "" {
 					meta.PollID = pollID
 				}
+				ps.loadLifecycle(debateID, &meta)
 				metadataMap[pollID] = meta
 				continue
 			}
@@ -226,18 +501,20 @@ func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64,
 		for option := range pollState[pollID] {
 			options = append(options, option)
 		}
-		metadataMap[pollID] = PollMetadata{
+		meta := PollMetadata{
 			PollID:   pollID,
 			Question: "",
 			Options:  options,
 		}
+		ps.loadLifecycle(debateID, &meta)
+		metadataMap[pollID] = meta
 	}
 
 	return pollState, votersCount, metadataMap, nil
 }
 
 // HasVoted checks if a spectator has already voted
-func (ps *PollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
+func (ps *RedisPollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
 	if ps == nil || ps.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
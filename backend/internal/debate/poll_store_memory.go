@@ -0,0 +1,194 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryPolls is the process-wide poll store used when Redis is not configured
+var memoryPolls = NewMemoryPollStore()
+
+// MemoryPollStore keeps poll state in process memory. It gives a single-instance
+// deployment, or a test, the same poll behaviour as the Redis store.
+type MemoryPollStore struct {
+	mu    sync.Mutex
+	polls map[string]map[string]*memoryPoll // debate ID -> poll ID -> poll
+}
+
+type memoryPoll struct {
+	meta   PollMetadata
+	counts map[string]int64
+	voters map[string]struct{}
+}
+
+// NewMemoryPollStore creates an empty in-process poll store
+func NewMemoryPollStore() *MemoryPollStore {
+	return &MemoryPollStore{polls: make(map[string]map[string]*memoryPoll)}
+}
+
+// pollLocked returns a poll or nil. The caller must hold ps.mu.
+func (ps *MemoryPollStore) pollLocked(debateID, pollID string) *memoryPoll {
+	return ps.polls[debateID][pollID]
+}
+
+// snapshotLocked returns a copy of a poll's metadata with its deadline applied
+func (p *memoryPoll) snapshotLocked() *PollMetadata {
+	meta := p.meta
+	meta.Options = append([]string(nil), p.meta.Options...)
+	applyPollDeadline(&meta)
+	return &meta
+}
+
+func (p *memoryPoll) countsLocked() map[string]int64 {
+	counts := make(map[string]int64, len(p.counts))
+	for option, count := range p.counts {
+		counts[option] = count
+	}
+	return counts
+}
+
+// CreatePoll creates a new poll unless one with the same ID exists
+func (ps *MemoryPollStore) CreatePoll(debateID, pollID, question string, options []string, settings PollSettings) (string, error) {
+	meta, status, err := newPollMetadata(pollID, question, options, settings)
+	if err != nil {
+		return "", err
+	}
+	meta.Status = status
+	if status == PollStatusOpen {
+		now := time.Now()
+		meta.OpenedAt = now.UnixMilli()
+		meta.ClosesAt = closeTime(now, settings.DurationSeconds)
+	}
+
+	poll := &memoryPoll{
+		meta:   meta,
+		counts: make(map[string]int64, len(meta.Options)),
+		voters: make(map[string]struct{}),
+	}
+	for _, option := range meta.Options {
+		poll.counts[option] = 0
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.polls[debateID] == nil {
+		ps.polls[debateID] = make(map[string]*memoryPoll)
+	}
+	if _, exists := ps.polls[debateID][meta.PollID]; exists {
+		return "", ErrPollExists
+	}
+	ps.polls[debateID][meta.PollID] = poll
+	return meta.PollID, nil
+}
+
+// Vote records a vote while the poll is open, for an existing option, once per spectator
+func (ps *MemoryPollStore) Vote(debateID, pollID, option, spectatorHash string) (bool, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return false, ErrPollNotFound
+	}
+	if poll.snapshotLocked().Status != PollStatusOpen {
+		return false, ErrPollClosed
+	}
+	if _, ok := poll.counts[option]; !ok {
+		return false, ErrInvalidPollOption
+	}
+	if _, voted := poll.voters[spectatorHash]; voted {
+		return false, nil
+	}
+	poll.voters[spectatorHash] = struct{}{}
+	poll.counts[option]++
+	return true, nil
+}
+
+// OpenPoll starts voting on a draft poll. Opening a poll that is already open or closed
+// changes nothing.
+func (ps *MemoryPollStore) OpenPoll(debateID, pollID string) (*PollMetadata, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	if poll.meta.Status == PollStatusDraft {
+		now := time.Now()
+		poll.meta.Status = PollStatusOpen
+		poll.meta.OpenedAt = now.UnixMilli()
+		poll.meta.ClosesAt = closeTime(now, poll.meta.DurationSeconds)
+	}
+	return poll.snapshotLocked(), nil
+}
+
+// ClosePoll freezes a poll's results and reports whether this call closed it
+func (ps *MemoryPollStore) ClosePoll(debateID, pollID string) (*PollMetadata, bool, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return nil, false, ErrPollNotFound
+	}
+	if poll.meta.Status == PollStatusClosed {
+		return poll.snapshotLocked(), false, nil
+	}
+	poll.meta.Status = PollStatusClosed
+	poll.meta.ClosedAt = time.Now().UnixMilli()
+	return poll.snapshotLocked(), true, nil
+}
+
+// GetPoll returns a poll's metadata and lifecycle state
+func (ps *MemoryPollStore) GetPoll(debateID, pollID string) (*PollMetadata, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return nil, ErrPollNotFound
+	}
+	return poll.snapshotLocked(), nil
+}
+
+// PollResults returns a poll's vote counts and number of voters
+func (ps *MemoryPollStore) PollResults(debateID, pollID string) (map[string]int64, int64, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return nil, 0, ErrPollNotFound
+	}
+	return poll.countsLocked(), int64(len(poll.voters)), nil
+}
+
+// GetPollState returns the current poll state for all polls in a debate
+func (ps *MemoryPollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	pollState := make(map[string]map[string]int64)
+	votersCount := make(map[string]int64)
+	metadataMap := make(map[string]PollMetadata)
+	for pollID, poll := range ps.polls[debateID] {
+		pollState[pollID] = poll.countsLocked()
+		votersCount[pollID] = int64(len(poll.voters))
+		metadataMap[pollID] = *poll.snapshotLocked()
+	}
+	return pollState, votersCount, metadataMap, nil
+}
+
+// HasVoted checks if a spectator has already voted
+func (ps *MemoryPollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	poll := ps.pollLocked(debateID, pollID)
+	if poll == nil {
+		return false, nil
+	}
+	_, voted := poll.voters[spectatorHash]
+	return voted, nil
+}
+
+// forget drops a debate's polls
+func (ps *MemoryPollStore) forget(debateID string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	delete(ps.polls, debateID)
+}
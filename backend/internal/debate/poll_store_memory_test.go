@@ -0,0 +1,65 @@
+package debate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryPollStoreLifecycle(t *testing.T) {
+	store := NewMemoryPollStore()
+	pollID, err := store.CreatePoll("d1", "", "Who wins?", []string{"For", "Against", "for"}, PollSettings{Status: PollStatusDraft})
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	meta, _ := store.GetPoll("d1", pollID)
+	if len(meta.Options) != 2 {
+		t.Errorf("options = %v, want duplicates removed", meta.Options)
+	}
+
+	if _, err := store.Vote("d1", pollID, "For", "s1"); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("vote on a draft poll: err = %v, want ErrPollClosed", err)
+	}
+	if meta, _ := store.OpenPoll("d1", pollID); meta.Status != PollStatusOpen {
+		t.Fatalf("status after open = %q", meta.Status)
+	}
+	if ok, err := store.Vote("d1", pollID, "For", "s1"); !ok || err != nil {
+		t.Errorf("first vote: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.Vote("d1", pollID, "Against", "s1"); ok || err != nil {
+		t.Errorf("duplicate vote: ok=%v err=%v, want ignored", ok, err)
+	}
+	if _, err := store.Vote("d1", pollID, "Maybe", "s2"); !errors.Is(err, ErrInvalidPollOption) {
+		t.Errorf("unknown option: err = %v, want ErrInvalidPollOption", err)
+	}
+
+	if _, closed, _ := store.ClosePoll("d1", pollID); !closed {
+		t.Error("first close should report the poll closed")
+	}
+	if _, closed, _ := store.ClosePoll("d1", pollID); closed {
+		t.Error("second close should not report the poll closed again")
+	}
+	if _, err := store.Vote("d1", pollID, "Against", "s2"); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("vote on a closed poll: err = %v, want ErrPollClosed", err)
+	}
+
+	counts, voters, _ := store.PollResults("d1", pollID)
+	if counts["For"] != 1 || counts["Against"] != 0 || voters != 1 {
+		t.Errorf("results = %v with %d voters, want one vote for", counts, voters)
+	}
+	state, _, _, _ := store.GetPollState("d1")
+	if len(state) != 1 {
+		t.Errorf("poll state has %d polls, want 1", len(state))
+	}
+}
+
+func TestMemoryPollStoreKeepsExistingPolls(t *testing.T) {
+	store := NewMemoryPollStore()
+	store.CreatePoll("d1", "p1", "Who wins?", []string{"For", "Against"}, PollSettings{})
+	store.Vote("d1", "p1", "For", "s1")
+	if _, err := store.CreatePoll("d1", "p1", "Replaced?", []string{"Yes", "No"}, PollSettings{}); !errors.Is(err, ErrPollExists) {
+		t.Errorf("second create: err = %v, want ErrPollExists", err)
+	}
+	if counts, _, _ := store.PollResults("d1", "p1"); counts["For"] != 1 {
+		t.Errorf("counts = %v, want the original vote kept", counts)
+	}
+}
@@ -0,0 +1,109 @@
+package debate
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRedisPollStoreLifecycle(t *testing.T) {
+	useMiniredis(t)
+	store := NewPollStore()
+	if _, ok := store.(*RedisPollStore); !ok {
+		t.Fatalf("store = %T, want the Redis store", store)
+	}
+
+	pollID, err := store.CreatePoll("d1", "", "Who wins?", []string{"For", "Against", "for"}, PollSettings{Status: PollStatusDraft})
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	if meta, _ := store.GetPoll("d1", pollID); meta.Status != PollStatusDraft || len(meta.Options) != 2 {
+		t.Errorf("new poll = %+v, want a draft with duplicates removed", meta)
+	}
+
+	if _, err := store.Vote("d1", pollID, "For", "s1"); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("vote on a draft poll: err = %v, want ErrPollClosed", err)
+	}
+	if meta, err := store.OpenPoll("d1", pollID); err != nil || meta.Status != PollStatusOpen || meta.OpenedAt == 0 {
+		t.Fatalf("open = %+v, %v, want an open poll", meta, err)
+	}
+	if ok, err := store.Vote("d1", pollID, "For", "s1"); !ok || err != nil {
+		t.Errorf("first vote: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.Vote("d1", pollID, "Against", "s1"); ok || err != nil {
+		t.Errorf("duplicate vote: ok=%v err=%v, want ignored", ok, err)
+	}
+	if _, err := store.Vote("d1", pollID, "Maybe", "s2"); !errors.Is(err, ErrInvalidPollOption) {
+		t.Errorf("unknown option: err = %v, want ErrInvalidPollOption", err)
+	}
+
+	if _, closed, err := store.ClosePoll("d1", pollID); !closed || err != nil {
+		t.Errorf("first close: closed=%v err=%v, want closed", closed, err)
+	}
+	if _, closed, _ := store.ClosePoll("d1", pollID); closed {
+		t.Error("second close should not report the poll closed again")
+	}
+	if meta, _ := store.OpenPoll("d1", pollID); meta.Status != PollStatusClosed {
+		t.Errorf("status after reopening = %q, want a closed poll to stay closed", meta.Status)
+	}
+	if _, err := store.Vote("d1", pollID, "Against", "s2"); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("vote on a closed poll: err = %v, want ErrPollClosed", err)
+	}
+	if _, _, err := store.ClosePoll("d1", "missing"); !errors.Is(err, ErrPollNotFound) {
+		t.Errorf("close of a missing poll: err = %v, want ErrPollNotFound", err)
+	}
+
+	counts, voters, _ := store.PollResults("d1", pollID)
+	if counts["For"] != 1 || counts["Against"] != 0 || voters != 1 {
+		t.Errorf("results = %v with %d voters, want one vote for", counts, voters)
+	}
+}
+
+func TestRedisPollStoreKeepsExistingPolls(t *testing.T) {
+	useMiniredis(t)
+	store := NewPollStore()
+
+	if _, err := store.CreatePoll("d1", "motion-pre", "Where do you stand?", []string{"For", "Against"}, PollSettings{}); err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	store.Vote("d1", "motion-pre", "For", "s1")
+	if _, err := store.CreatePoll("d1", "motion-pre", "Replaced?", []string{"Yes", "No"}, PollSettings{}); !errors.Is(err, ErrPollExists) {
+		t.Errorf("second create: err = %v, want ErrPollExists", err)
+	}
+
+	meta, _ := store.GetPoll("d1", "motion-pre")
+	counts, voters, _ := store.PollResults("d1", "motion-pre")
+	if meta.Question != "Where do you stand?" || counts["For"] != 1 || voters != 1 {
+		t.Errorf("poll = %q with %v from %d voters, want the original poll and vote", meta.Question, counts, voters)
+	}
+}
+
+func TestRedisPollStoreDeadline(t *testing.T) {
+	server := useMiniredis(t)
+	store := NewPollStore()
+
+	pollID, err := store.CreatePoll("d1", "", "Who wins?", []string{"For", "Against"}, PollSettings{DurationSeconds: 60})
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+	meta, _ := store.GetPoll("d1", pollID)
+	if meta.Status != PollStatusOpen || meta.ClosesAt <= meta.OpenedAt {
+		t.Fatalf("timed poll = %+v, want open with a close time", meta)
+	}
+
+	// Past its close time the poll takes no votes and reads as closed, even before
+	// anything has closed it
+	past := time.Now().Add(-time.Second).UnixMilli()
+	server.HSet(pollStateKey("d1", pollID), "closesAt", strconv.FormatInt(past, 10))
+	if _, err := store.Vote("d1", pollID, "For", "s1"); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("vote after the deadline: err = %v, want ErrPollClosed", err)
+	}
+	_, _, metadata, _ := store.GetPollState("d1")
+	if got := metadata[pollID]; got.Status != PollStatusClosed || got.ClosedAt != past {
+		t.Errorf("expired poll = %+v, want closed at its close time", got)
+	}
+	if _, closed, _ := store.ClosePoll("d1", pollID); !closed {
+		t.Error("closing an expired poll should report it closed the first time")
+	}
+}
@@ -3,145 +3,206 @@ package debate
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-// RateLimiter handles rate limiting for spectator actions
-type RateLimiter struct {
-	rdb *redis.Client
-	ctx context.Context
-}
+// Rate-limited actions. Spectator actions are keyed by debate and spectator, REST actions
+// by user or IP.
+const (
+	ActionQuestion = "question"
+	ActionReaction = "reaction"
+	// ActionReactionBurst bounds how often a debate's aggregated reactions are broadcast
+	ActionReactionBurst = "reaction_burst"
+	ActionChat          = "chat"
+	// ActionCommentary bounds how often the AI commentator speaks in a debate
+	ActionCommentary   = "commentary"
+	ActionVsBotMessage = "vsbot_message"
+	ActionPost         = "post"
+	ActionComment      = "comment"
+)
 
-// NewRateLimiter creates a new RateLimiter instance
-func NewRateLimiter() *RateLimiter {
-	return &RateLimiter{
-		rdb: GetRedisClient(),
-		ctx: GetContext(),
-	}
+// RateLimitPolicy allows Limit requests in any sliding Window
+type RateLimitPolicy struct {
+	Limit  int
+	Window time.Duration
 }
 
-// RateLimitConfig defines rate limit rules
-type RateLimitConfig struct {
-	MaxVotes       int           // per poll
-	MaxQuestions   int           // per duration
-	MaxReactions   int           // per duration
-	QuestionWindow time.Duration // time window for questions
-	ReactionWindow time.Duration // time window for reactions
+// RateLimitResult is the outcome of a rate-limited request
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // How long until the next request would be allowed, when denied
 }
 
-// DefaultRateLimitConfig returns default rate limit configuration
-func DefaultRateLimitConfig() RateLimitConfig {
-	return RateLimitConfig{
-		MaxVotes:       1,
-		MaxQuestions:   1,
-		MaxReactions:   5,
-		QuestionWindow: 15 * time.Second,
-		ReactionWindow: 10 * time.Second,
+// DefaultRateLimitPolicies returns the policies used for actions that are not configured
+func DefaultRateLimitPolicies() map[string]RateLimitPolicy {
+	return map[string]RateLimitPolicy{
+		ActionQuestion:      {Limit: 1, Window: 15 * time.Second},
+		ActionReaction:      {Limit: 5, Window: 10 * time.Second},
+		ActionReactionBurst: {Limit: 1, Window: time.Second},
+		ActionChat:          {Limit: 5, Window: 10 * time.Second},
+		ActionCommentary:    {Limit: 1, Window: 20 * time.Second},
+		ActionVsBotMessage:  {Limit: 20, Window: time.Minute},
+		ActionPost:          {Limit: 5, Window: time.Minute},
+		ActionComment:       {Limit: 10, Window: time.Minute},
 	}
 }
 
-// CheckVoteRateLimit checks if spectator can vote (1 vote per poll)
-func (rl *RateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
-	// This is handled by the voter SET in poll_store.go
-	// Return true if not already voted
-	store := NewPollStore()
-	hasVoted, err := store.HasVoted(debateID, pollID, spectatorHash)
-	if err != nil {
-		return false, err
-	}
-	return !hasVoted, nil
-}
+var (
+	rateLimitPolicies   = DefaultRateLimitPolicies()
+	rateLimitPoliciesMu sync.RWMutex
+	// rateLimitNow is the clock the sliding windows are measured by
+	rateLimitNow = time.Now
+)
 
-// CheckQuestionRateLimit checks if spectator can ask a question
-func (rl *RateLimiter) CheckQuestionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
-	if rl == nil || rl.rdb == nil {
-		return false, fmt.Errorf("Redis client not available")
+// SetRateLimitPolicies overrides the policies of the given actions. Actions that are not
+// listed keep their defaults; policies without a positive limit and window are ignored.
+func SetRateLimitPolicies(policies map[string]RateLimitPolicy) {
+	merged := DefaultRateLimitPolicies()
+	for action, policy := range policies {
+		if policy.Limit > 0 && policy.Window > 0 {
+			merged[action] = policy
+		}
 	}
+	rateLimitPoliciesMu.Lock()
+	defer rateLimitPoliciesMu.Unlock()
+	rateLimitPolicies = merged
+}
 
-	key := fmt.Sprintf("rate:question:%s:%s", debateID, spectatorHash)
+// GetRateLimitPolicy returns the policy for an action
+func GetRateLimitPolicy(action string) (RateLimitPolicy, bool) {
+	rateLimitPoliciesMu.RLock()
+	defer rateLimitPoliciesMu.RUnlock()
+	policy, ok := rateLimitPolicies[action]
+	return policy, ok
+}
 
-	// Check current count
-	count, err := rl.rdb.Get(rl.ctx, key).Int()
-	if err == redis.Nil {
-		// First question, allow it
-		return true, nil
-	} else if err != nil {
-		return false, err
-	}
+// slidingWindowScript keeps one sorted-set entry per allowed request, scored by its time
+// in milliseconds. Checking and recording happen in one step, so concurrent requests
+// cannot both take the last slot. Returns {allowed, count, retryAfterMs}.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+if count >= limit then
+	local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+	local retry = window
+	if oldest[2] then
+		retry = tonumber(oldest[2]) + window - now
+	end
+	return {0, count, retry}
+end
+redis.call("ZADD", KEYS[1], now, ARGV[4])
+redis.call("PEXPIRE", KEYS[1], window)
+return {1, count + 1, 0}
+`)
+
+// RateLimiter limits spectator actions and REST endpoints by per-action policies
+type RateLimiter interface {
+	// Allow records a request by subject for an action if the action's policy permits it
+	Allow(action, subject string) (RateLimitResult, error)
+	// AllowPolicy records a request under key if policy permits it
+	AllowPolicy(key string, policy RateLimitPolicy) (RateLimitResult, error)
+	// CheckVoteRateLimit checks if a spectator can still vote in a poll
+	CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error)
+	// AllowQuestion records a spectator question if the question policy permits it
+	AllowQuestion(debateID, spectatorHash string) (bool, error)
+	// AllowReaction records a spectator reaction if the reaction policy permits it
+	AllowReaction(debateID, spectatorHash string) (bool, error)
+}
 
-	if count >= config.MaxQuestions {
-		return false, nil
+// NewRateLimiter returns the Redis rate limiter when Redis is configured and the
+// in-process one otherwise
+func NewRateLimiter() RateLimiter {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisRateLimiter{
+			rdb: rdb,
+			ctx: GetContext(),
+		}
 	}
-
-	return true, nil
+	return memoryRateLimiter
 }
 
-// RecordQuestion records a question for rate limiting
-func (rl *RateLimiter) RecordQuestion(debateID, spectatorHash string, config RateLimitConfig) error {
-	if rl == nil || rl.rdb == nil {
-		return fmt.Errorf("Redis client not available")
+// allowAction looks up an action's policy and applies it to the subject's key
+func allowAction(rl RateLimiter, action, subject string) (RateLimitResult, error) {
+	policy, ok := GetRateLimitPolicy(action)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("no rate limit policy for %q", action)
 	}
+	return rl.AllowPolicy(fmt.Sprintf("rate:%s:%s", action, subject), policy)
+}
 
-	key := fmt.Sprintf("rate:question:%s:%s", debateID, spectatorHash)
-
-	// Increment count
-	count, err := rl.rdb.Incr(rl.ctx, key).Result()
+// checkVoteRateLimit allows one vote per poll, tracked by the poll's voter set
+func checkVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	hasVoted, err := NewPollStore().HasVoted(debateID, pollID, spectatorHash)
 	if err != nil {
-		return err
-	}
-
-	// Set expiration if first time
-	if count == 1 {
-		rl.rdb.Expire(rl.ctx, key, config.QuestionWindow)
+		return false, err
 	}
-
-	return nil
+	return !hasVoted, nil
 }
 
-// CheckReactionRateLimit checks if spectator can send a reaction
-func (rl *RateLimiter) CheckReactionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
-	if rl == nil || rl.rdb == nil {
-		return false, fmt.Errorf("Redis client not available")
+// newRateLimitResult describes a decision given the requests counted in the window
+func newRateLimitResult(allowed bool, policy RateLimitPolicy, count int, retryAfter time.Duration) RateLimitResult {
+	remaining := policy.Limit - count
+	if remaining < 0 {
+		remaining = 0
 	}
-
-	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
-
-	// Check current count
-	count, err := rl.rdb.Get(rl.ctx, key).Int()
-	if err == redis.Nil {
-		// First reaction, allow it
-		return true, nil
-	} else if err != nil {
-		return false, err
+	return RateLimitResult{
+		Allowed:    allowed,
+		Limit:      policy.Limit,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
 	}
+}
 
-	if count >= config.MaxReactions {
-		return false, nil
-	}
+// RedisRateLimiter shares its sliding windows between instances through Redis
+type RedisRateLimiter struct {
+	rdb *redis.Client
+	ctx context.Context
+}
 
-	return true, nil
+// Allow records a request by subject for an action if the action's policy permits it
+func (rl *RedisRateLimiter) Allow(action, subject string) (RateLimitResult, error) {
+	return allowAction(rl, action, subject)
 }
 
-// RecordReaction records a reaction for rate limiting
-func (rl *RateLimiter) RecordReaction(debateID, spectatorHash string, config RateLimitConfig) error {
+// AllowPolicy records a request under key if policy permits it
+func (rl *RedisRateLimiter) AllowPolicy(key string, policy RateLimitPolicy) (RateLimitResult, error) {
 	if rl == nil || rl.rdb == nil {
-		return fmt.Errorf("Redis client not available")
+		return RateLimitResult{}, fmt.Errorf("Redis client not available")
 	}
 
-	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
-
-	// Increment count
-	count, err := rl.rdb.Incr(rl.ctx, key).Result()
+	values, err := slidingWindowScript.Run(rl.ctx, rl.rdb, []string{key},
+		rateLimitNow().UnixMilli(), policy.Window.Milliseconds(), policy.Limit, uuid.New().String()).Int64Slice()
 	if err != nil {
-		return err
+		return RateLimitResult{}, err
 	}
-
-	// Set expiration if first time
-	if count == 1 {
-		rl.rdb.Expire(rl.ctx, key, config.ReactionWindow)
+	if len(values) != 3 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit reply: %v", values)
 	}
+	return newRateLimitResult(values[0] == 1, policy, int(values[1]), time.Duration(values[2])*time.Millisecond), nil
+}
+
+// CheckVoteRateLimit checks if spectator can vote (1 vote per poll)
+func (rl *RedisRateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	return checkVoteRateLimit(debateID, pollID, spectatorHash)
+}
+
+// AllowQuestion records a spectator question if the question policy permits it
+func (rl *RedisRateLimiter) AllowQuestion(debateID, spectatorHash string) (bool, error) {
+	result, err := rl.Allow(ActionQuestion, debateID+":"+spectatorHash)
+	return result.Allowed, err
+}
 
-	return nil
+// AllowReaction records a spectator reaction if the reaction policy permits it
+func (rl *RedisRateLimiter) AllowReaction(debateID, spectatorHash string) (bool, error) {
+	result, err := rl.Allow(ActionReaction, debateID+":"+spectatorHash)
+	return result.Allowed, err
 }
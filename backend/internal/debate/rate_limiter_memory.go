@@ -0,0 +1,98 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryRateLimiter is the process-wide rate limiter used when Redis is not configured
+var memoryRateLimiter = NewMemoryRateLimiter()
+
+// memoryRateLimitSweepEvery is how many requests pass between sweeps of idle windows
+const memoryRateLimitSweepEvery = 1000
+
+// MemoryRateLimiter keeps sliding windows in process memory. Limits apply per instance.
+type MemoryRateLimiter struct {
+	mu       sync.Mutex
+	windows  map[string]*memoryWindow
+	requests int
+}
+
+type memoryWindow struct {
+	times  []time.Time // Allowed requests still inside the window, oldest first
+	window time.Duration
+}
+
+// NewMemoryRateLimiter creates an in-process rate limiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{windows: make(map[string]*memoryWindow)}
+}
+
+// prune drops requests that have left the window
+func (w *memoryWindow) prune(now time.Time) {
+	cutoff := now.Add(-w.window)
+	i := 0
+	for i < len(w.times) && !w.times[i].After(cutoff) {
+		i++
+	}
+	w.times = w.times[i:]
+}
+
+// Allow records a request by subject for an action if the action's policy permits it
+func (rl *MemoryRateLimiter) Allow(action, subject string) (RateLimitResult, error) {
+	return allowAction(rl, action, subject)
+}
+
+// AllowPolicy records a request under key if policy permits it
+func (rl *MemoryRateLimiter) AllowPolicy(key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	now := rateLimitNow()
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.requests++
+	if rl.requests%memoryRateLimitSweepEvery == 0 {
+		rl.sweepLocked(now)
+	}
+
+	w, ok := rl.windows[key]
+	if !ok {
+		w = &memoryWindow{}
+		rl.windows[key] = w
+	}
+	w.window = policy.Window
+	w.prune(now)
+
+	if len(w.times) >= policy.Limit {
+		retryAfter := w.times[0].Add(policy.Window).Sub(now)
+		return newRateLimitResult(false, policy, len(w.times), retryAfter), nil
+	}
+	w.times = append(w.times, now)
+	return newRateLimitResult(true, policy, len(w.times), 0), nil
+}
+
+// sweepLocked forgets windows with no requests left in them. The caller must hold rl.mu.
+func (rl *MemoryRateLimiter) sweepLocked(now time.Time) {
+	for key, w := range rl.windows {
+		w.prune(now)
+		if len(w.times) == 0 {
+			delete(rl.windows, key)
+		}
+	}
+}
+
+// CheckVoteRateLimit checks if spectator can vote (1 vote per poll)
+func (rl *MemoryRateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	return checkVoteRateLimit(debateID, pollID, spectatorHash)
+}
+
+// AllowQuestion records a spectator question if the question policy permits it
+func (rl *MemoryRateLimiter) AllowQuestion(debateID, spectatorHash string) (bool, error) {
+	result, err := rl.Allow(ActionQuestion, debateID+":"+spectatorHash)
+	return result.Allowed, err
+}
+
+// AllowReaction records a spectator reaction if the reaction policy permits it
+func (rl *MemoryRateLimiter) AllowReaction(debateID, spectatorHash string) (bool, error) {
+	result, err := rl.Allow(ActionReaction, debateID+":"+spectatorHash)
+	return result.Allowed, err
+}
@@ -0,0 +1,141 @@
+package debate
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetRateLimitPolicies(t *testing.T) {
+	defer SetRateLimitPolicies(nil)
+	SetRateLimitPolicies(map[string]RateLimitPolicy{
+		ActionPost:    {Limit: 2, Window: 30 * time.Second},
+		ActionComment: {Limit: 0, Window: time.Minute},
+		"custom":      {Limit: 3, Window: time.Second},
+	})
+
+	if policy, _ := GetRateLimitPolicy(ActionPost); policy.Limit != 2 || policy.Window != 30*time.Second {
+		t.Errorf("post policy = %+v, want the configured one", policy)
+	}
+	if policy, _ := GetRateLimitPolicy(ActionComment); policy != DefaultRateLimitPolicies()[ActionComment] {
+		t.Errorf("comment policy = %+v, want the default for an invalid override", policy)
+	}
+	if _, ok := GetRateLimitPolicy("custom"); !ok {
+		t.Error("a configured action without a default should have a policy")
+	}
+	if _, ok := GetRateLimitPolicy("unknown"); ok {
+		t.Error("an unconfigured action should have no policy")
+	}
+}
+
+func TestRedisRateLimiterWithoutClient(t *testing.T) {
+	rl := &RedisRateLimiter{}
+	if _, err := rl.Allow(ActionQuestion, "debate:spectator"); err == nil {
+		t.Error("Allow should fail without a Redis client")
+	}
+}
+
+func TestMemoryRateLimiter(t *testing.T) {
+	rl := NewMemoryRateLimiter()
+	policy := RateLimitPolicy{Limit: 2, Window: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		result, err := rl.AllowPolicy("key", policy)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d: allowed=%v err=%v, want allowed", i+1, result.Allowed, err)
+		}
+	}
+	result, _ := rl.AllowPolicy("key", policy)
+	if result.Allowed || result.Remaining != 0 || result.RetryAfter <= 0 {
+		t.Errorf("third request = %+v, want denied with a retry delay", result)
+	}
+	if result, _ := rl.AllowPolicy("other", policy); !result.Allowed {
+		t.Error("a different key should have its own window")
+	}
+
+	clock := useRateLimitClock(t)
+	short := RateLimitPolicy{Limit: 1, Window: 10 * time.Millisecond}
+	rl.AllowPolicy("short", short)
+	clock.advance(20 * time.Millisecond)
+	if result, _ := rl.AllowPolicy("short", short); !result.Allowed {
+		t.Error("a request should be allowed once the window has passed")
+	}
+}
+
+// testClock is a rate limit clock that only moves when the test advances it
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// useRateLimitClock points the rate limiters at a test clock, and the in-process one at
+// empty windows, for the rest of the test
+func useRateLimitClock(t *testing.T) *testClock {
+	t.Helper()
+	clock := &testClock{now: time.UnixMilli(1_700_000_000_000)}
+	previous, previousLimiter := rateLimitNow, memoryRateLimiter
+	rateLimitNow, memoryRateLimiter = clock.Now, NewMemoryRateLimiter()
+	t.Cleanup(func() { rateLimitNow, memoryRateLimiter = previous, previousLimiter })
+	return clock
+}
+
+func TestRedisRateLimiterSlidingWindow(t *testing.T) {
+	useMiniredis(t)
+	clock := useRateLimitClock(t)
+	rl := NewRateLimiter()
+	if _, ok := rl.(*RedisRateLimiter); !ok {
+		t.Fatalf("Expected the Redis rate limiter, got %T", rl)
+	}
+	policy := RateLimitPolicy{Limit: 3, Window: 10 * time.Second}
+
+	for i := 0; i < 3; i++ {
+		result, err := rl.AllowPolicy("key", policy)
+		if err != nil || !result.Allowed {
+			t.Fatalf("Expected request %d to be allowed, got %+v %v", i+1, result, err)
+		}
+		if result.Remaining != 2-i {
+			t.Errorf("Expected %d requests to remain, got %d", 2-i, result.Remaining)
+		}
+		clock.advance(2 * time.Second)
+	}
+
+	// The window is full until the first request, made 6s ago, leaves it
+	result, err := rl.AllowPolicy("key", policy)
+	if err != nil || result.Allowed {
+		t.Fatalf("Expected the fourth request to be denied, got %+v %v", result, err)
+	}
+	if result.Remaining != 0 || result.RetryAfter != 4*time.Second {
+		t.Errorf("Expected no requests left and a 4s retry, got %+v", result)
+	}
+	if result, _ := rl.AllowPolicy("other", policy); !result.Allowed {
+		t.Error("Expected a different key to have its own window")
+	}
+
+	clock.advance(4 * time.Second)
+	if result, _ := rl.AllowPolicy("key", policy); !result.Allowed {
+		t.Error("Expected a request once the oldest one slid out of the window")
+	}
+	// Denied requests are not counted, so only one slot opened
+	if result, _ := rl.AllowPolicy("key", policy); result.Allowed || result.RetryAfter != 2*time.Second {
+		t.Errorf("Expected the next request to wait 2s for the second oldest, got %+v", result)
+	}
+
+	clock.advance(policy.Window)
+	for i := 0; i < 3; i++ {
+		if result, _ := rl.AllowPolicy("key", policy); !result.Allowed {
+			t.Errorf("Expected a full window once every request expired, request %d was denied", i+1)
+		}
+	}
+}
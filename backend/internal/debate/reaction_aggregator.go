@@ -0,0 +1,129 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// reactionIdleTicks is how many empty buckets a debate's aggregator waits before it stops
+const reactionIdleTicks = 30
+
+// ReactionAggregator collects spectator reactions into short buckets and broadcasts one
+// reaction_burst event per bucket instead of one event per reaction. How often a debate's
+// bursts go out is bounded by the reaction_burst rate limit policy, which is shared by
+// all instances when Redis is configured.
+type ReactionAggregator struct {
+	mu      sync.Mutex
+	buckets map[string]*reactionBucket // debate ID -> reactions not yet broadcast
+	publish func(debateID string, burst ReactionBurstPayload)
+	// ticks returns the channel a debate's bucket is flushed on and a function that stops it
+	ticks func(interval time.Duration) (<-chan time.Time, func())
+}
+
+type reactionBucket struct {
+	counts    map[string]int64
+	sides     map[string]int64
+	from, to  int64 // Unix milliseconds of the first and last reaction
+	idleTicks int
+}
+
+// NewReactionAggregator creates an aggregator that hands each burst to publish
+func NewReactionAggregator(publish func(debateID string, burst ReactionBurstPayload)) *ReactionAggregator {
+	return &ReactionAggregator{
+		buckets: make(map[string]*reactionBucket),
+		publish: publish,
+		ticks: func(interval time.Duration) (<-chan time.Time, func()) {
+			ticker := time.NewTicker(interval)
+			return ticker.C, ticker.Stop
+		},
+	}
+}
+
+// reactionBucketInterval spreads a debate's allowed bursts evenly over the policy window
+func reactionBucketInterval() time.Duration {
+	policy, ok := GetRateLimitPolicy(ActionReactionBurst)
+	if !ok || policy.Limit <= 0 {
+		return time.Second
+	}
+	return policy.Window / time.Duration(policy.Limit)
+}
+
+// ReactionSideNone counts reactions that are not for either side
+const ReactionSideNone = "none"
+
+// Add counts a reaction for a side ("for", "against" or empty) towards the debate's
+// next burst
+func (a *ReactionAggregator) Add(debateID, reaction, side string) {
+	if side != "for" && side != "against" {
+		side = ReactionSideNone
+	}
+	now := time.Now().UnixMilli()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	bucket, ok := a.buckets[debateID]
+	if !ok {
+		bucket = &reactionBucket{counts: make(map[string]int64), sides: make(map[string]int64)}
+		a.buckets[debateID] = bucket
+		go a.run(debateID, bucket)
+	}
+	if len(bucket.counts) == 0 {
+		bucket.from = now
+	}
+	bucket.counts[reaction]++
+	bucket.sides[side]++
+	bucket.to = now
+	bucket.idleTicks = 0
+}
+
+// run flushes a debate's bucket every interval until it has been idle for a while
+func (a *ReactionAggregator) run(debateID string, bucket *reactionBucket) {
+	ticks, stop := a.ticks(reactionBucketInterval())
+	defer stop()
+
+	for range ticks {
+		if !a.flush(debateID, bucket) {
+			return
+		}
+	}
+}
+
+// flush broadcasts the bucket's reactions if the debate's burst rate allows it. Reactions
+// that cannot go out yet stay in the bucket for the next tick. It reports false once the
+// aggregator for the debate has stopped.
+func (a *ReactionAggregator) flush(debateID string, bucket *reactionBucket) bool {
+	a.mu.Lock()
+	if len(bucket.counts) == 0 {
+		bucket.idleTicks++
+		if bucket.idleTicks >= reactionIdleTicks {
+			delete(a.buckets, debateID)
+			a.mu.Unlock()
+			return false
+		}
+		a.mu.Unlock()
+		return true
+	}
+	a.mu.Unlock()
+
+	if result, err := NewRateLimiter().Allow(ActionReactionBurst, debateID); err == nil && !result.Allowed {
+		return true
+	}
+
+	a.mu.Lock()
+	burst := ReactionBurstPayload{
+		Counts:    bucket.counts,
+		Sides:     bucket.sides,
+		From:      bucket.from,
+		To:        bucket.to,
+		Timestamp: time.Now().Unix(),
+	}
+	for _, count := range bucket.counts {
+		burst.Total += count
+	}
+	bucket.counts = make(map[string]int64)
+	bucket.sides = make(map[string]int64)
+	a.mu.Unlock()
+
+	a.publish(debateID, burst)
+	return true
+}
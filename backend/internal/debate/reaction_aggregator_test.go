@@ -0,0 +1,67 @@
+package debate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReactionAggregatorBursts(t *testing.T) {
+	SetRateLimitPolicies(map[string]RateLimitPolicy{
+		ActionReactionBurst: {Limit: 1, Window: time.Second},
+	})
+	defer SetRateLimitPolicies(nil)
+	clock := useRateLimitClock(t)
+
+	published := make(chan ReactionBurstPayload, 4)
+	aggregator := NewReactionAggregator(func(debateID string, burst ReactionBurstPayload) {
+		published <- burst
+	})
+	tick := make(chan time.Time)
+	aggregator.ticks = func(time.Duration) (<-chan time.Time, func()) {
+		return tick, func() {}
+	}
+	nextBurst := func() ReactionBurstPayload {
+		t.Helper()
+		select {
+		case burst := <-published:
+			return burst
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected a reaction burst")
+			return ReactionBurstPayload{}
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		aggregator.Add("burst-debate", "👍", "for")
+	}
+	aggregator.Add("burst-debate", "👏", "")
+	tick <- clock.Now()
+
+	burst := nextBurst()
+	if burst.Counts["👍"] != 5 || burst.Counts["👏"] != 1 || burst.Total != 6 {
+		t.Errorf("Expected 5 thumbs up and 1 clap in one burst, got %+v", burst)
+	}
+	if burst.Sides["for"] != 5 || burst.Sides[ReactionSideNone] != 1 {
+		t.Errorf("Expected 5 for and 1 unattributed, got %v", burst.Sides)
+	}
+	if burst.From == 0 || burst.To < burst.From {
+		t.Errorf("Expected the burst window to be set, got %d..%d", burst.From, burst.To)
+	}
+
+	// The next burst waits for the burst rate limit; the second tick is only taken once
+	// the first flush has finished
+	aggregator.Add("burst-debate", "👍", "against")
+	tick <- clock.Now()
+	tick <- clock.Now()
+	select {
+	case burst := <-published:
+		t.Fatalf("Expected the burst to be held by the rate limit, got %+v", burst)
+	default:
+	}
+
+	clock.advance(time.Second)
+	tick <- clock.Now()
+	if burst := nextBurst(); burst.Total != 1 || burst.Sides["against"] != 1 {
+		t.Errorf("Expected the held reaction once the window passed, got %+v", burst)
+	}
+}
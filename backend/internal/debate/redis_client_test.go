@@ -0,0 +1,21 @@
+package debate
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// useMiniredis points the package's Redis client at an in-memory server for the test
+func useMiniredis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	previous := rdb
+	rdb = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		rdb.Close()
+		rdb = previous
+	})
+	return server
+}
@@ -0,0 +1,191 @@
+package debate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SpectatorLimits bounds how much a single anonymous spectator can take part. Logged-in
+// spectators are identified by their account and are not subject to these limits.
+type SpectatorLimits struct {
+	MaxConnectionsPerIP     int // Concurrent anonymous connections from one IP to one debate
+	MaxConnectionsPerDevice int // Concurrent connections for one anonymous device ID in one debate
+	MaxVotesPerIP           int // Anonymous votes from one IP in one poll
+}
+
+// DefaultSpectatorLimits returns the limits used when none are configured
+func DefaultSpectatorLimits() SpectatorLimits {
+	return SpectatorLimits{
+		MaxConnectionsPerIP:     10,
+		MaxConnectionsPerDevice: 3,
+		MaxVotesPerIP:           5,
+	}
+}
+
+// spectatorCounterTTL keeps connection counters from leaking if an instance dies
+// without releasing its connections
+const spectatorCounterTTL = 6 * time.Hour
+
+var (
+	spectatorSecret []byte
+	spectatorLimits = DefaultSpectatorLimits()
+	spectatorMu     sync.RWMutex
+)
+
+// SetSpectatorSecret sets the server secret spectator identities are derived from. An
+// empty secret is refused: anyone could compute the hashes.
+func SetSpectatorSecret(secret string) error {
+	if secret == "" {
+		return errors.New("spectator identity secret is required")
+	}
+	spectatorMu.Lock()
+	defer spectatorMu.Unlock()
+	spectatorSecret = []byte(secret)
+	return nil
+}
+
+// SetSpectatorLimits overrides the anonymous spectator limits. Zero values keep the defaults.
+func SetSpectatorLimits(limits SpectatorLimits) {
+	defaults := DefaultSpectatorLimits()
+	if limits.MaxConnectionsPerIP <= 0 {
+		limits.MaxConnectionsPerIP = defaults.MaxConnectionsPerIP
+	}
+	if limits.MaxConnectionsPerDevice <= 0 {
+		limits.MaxConnectionsPerDevice = defaults.MaxConnectionsPerDevice
+	}
+	if limits.MaxVotesPerIP <= 0 {
+		limits.MaxVotesPerIP = defaults.MaxVotesPerIP
+	}
+	spectatorMu.Lock()
+	defer spectatorMu.Unlock()
+	spectatorLimits = limits
+}
+
+// GetSpectatorLimits returns the anonymous spectator limits in effect
+func GetSpectatorLimits() SpectatorLimits {
+	spectatorMu.RLock()
+	defer spectatorMu.RUnlock()
+	return spectatorLimits
+}
+
+// SpectatorHash derives a spectator's identity with an HMAC keyed by the server secret,
+// so clients can neither forge nor predict another spectator's hash. Logged-in spectators
+// are identified by their user ID; anonymous ones by their device ID.
+func SpectatorHash(userID, deviceID string) string {
+	subject := "anon:" + deviceID
+	if userID != "" {
+		subject = "user:" + userID
+	}
+	spectatorMu.RLock()
+	mac := hmac.New(sha256.New, spectatorSecret)
+	spectatorMu.RUnlock()
+	mac.Write([]byte(subject))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// acquireScript increments the IP and device connection counters unless either is at
+// its limit. Returns 1 when the connection is admitted.
+var acquireScript = redis.NewScript(`
+local ip = tonumber(redis.call("GET", KEYS[1]) or "0")
+local device = tonumber(redis.call("GET", KEYS[2]) or "0")
+if ip >= tonumber(ARGV[1]) or device >= tonumber(ARGV[2]) then
+	return 0
+end
+redis.call("INCR", KEYS[1])
+redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+return 1
+`)
+
+func spectatorConnectionKeys(debateID, ip, spectatorHash string) []string {
+	return []string{
+		fmt.Sprintf("debate:%s:spectators:ip:%s", debateID, ip),
+		fmt.Sprintf("debate:%s:spectators:device:%s", debateID, spectatorHash),
+	}
+}
+
+// AcquireSpectatorConnection admits an anonymous spectator connection unless its IP or
+// device already holds the maximum number of connections to the debate. Without Redis
+// the connections are counted in process.
+func AcquireSpectatorConnection(debateID, ip, spectatorHash string) (bool, error) {
+	limits := GetSpectatorLimits()
+	rdb := GetRedisClient()
+	if rdb == nil {
+		return memorySpectators.Acquire(debateID, ip, spectatorHash, limits), nil
+	}
+	admitted, err := acquireScript.Run(GetContext(), rdb, spectatorConnectionKeys(debateID, ip, spectatorHash),
+		limits.MaxConnectionsPerIP, limits.MaxConnectionsPerDevice, spectatorCounterTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return admitted == 1, nil
+}
+
+// ReleaseSpectatorConnection gives back a connection taken by AcquireSpectatorConnection
+func ReleaseSpectatorConnection(debateID, ip, spectatorHash string) error {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		memorySpectators.Release(debateID, ip, spectatorHash)
+		return nil
+	}
+	ctx := GetContext()
+	pipe := rdb.TxPipeline()
+	for _, key := range spectatorConnectionKeys(debateID, ip, spectatorHash) {
+		pipe.Decr(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// anonymousVoteTTL is how long an IP's vote count for a poll is kept
+const anonymousVoteTTL = 24 * time.Hour
+
+// reserveVoteScript increments an IP's vote count for a poll unless it is at the limit,
+// setting the expiry with the first vote. Returns 1 when the vote is reserved.
+var reserveVoteScript = redis.NewScript(`
+local count = tonumber(redis.call("GET", KEYS[1]) or "0")
+if count >= tonumber(ARGV[1]) then
+	return 0
+end
+if redis.call("INCR", KEYS[1]) == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+func anonymousVoteKey(debateID, pollID, ip string) string {
+	return fmt.Sprintf("debate:%s:poll:%s:ipvotes:%s", debateID, pollID, ip)
+}
+
+// ReserveAnonymousVote counts an anonymous vote against its IP's allowance for a poll.
+// It reports false once the IP has used up its votes.
+func ReserveAnonymousVote(debateID, pollID, ip string) (bool, error) {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		return memorySpectators.ReserveVote(debateID, pollID, ip, GetSpectatorLimits().MaxVotesPerIP), nil
+	}
+	reserved, err := reserveVoteScript.Run(GetContext(), rdb, []string{anonymousVoteKey(debateID, pollID, ip)},
+		GetSpectatorLimits().MaxVotesPerIP, anonymousVoteTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return reserved == 1, nil
+}
+
+// ReleaseAnonymousVote returns a reserved vote that was not counted
+func ReleaseAnonymousVote(debateID, pollID, ip string) {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		memorySpectators.ReleaseVote(debateID, pollID, ip)
+		return
+	}
+	rdb.Decr(GetContext(), anonymousVoteKey(debateID, pollID, ip))
+}
@@ -0,0 +1,102 @@
+package debate
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySpectators is the process-wide spectator counter store used when Redis is not
+// configured
+var memorySpectators = NewMemorySpectatorCounters()
+
+// MemorySpectatorCounters keeps the anonymous spectator connection and vote counts in
+// process memory, so a single-instance deployment enforces the same per-IP and per-device
+// limits as Redis does
+type MemorySpectatorCounters struct {
+	mu          sync.Mutex
+	connections map[string]int                 // Counter key -> open connections
+	votes       map[string]memoryVoteAllowance // Counter key -> votes used
+}
+
+type memoryVoteAllowance struct {
+	count     int
+	expiresAt time.Time
+}
+
+// NewMemorySpectatorCounters creates empty in-process spectator counters
+func NewMemorySpectatorCounters() *MemorySpectatorCounters {
+	return &MemorySpectatorCounters{
+		connections: make(map[string]int),
+		votes:       make(map[string]memoryVoteAllowance),
+	}
+}
+
+// Acquire admits a connection unless its IP or device is at its limit
+func (sc *MemorySpectatorCounters) Acquire(debateID, ip, spectatorHash string, limits SpectatorLimits) bool {
+	keys := spectatorConnectionKeys(debateID, ip, spectatorHash)
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.connections[keys[0]] >= limits.MaxConnectionsPerIP || sc.connections[keys[1]] >= limits.MaxConnectionsPerDevice {
+		return false
+	}
+	for _, key := range keys {
+		sc.connections[key]++
+	}
+	return true
+}
+
+// Release gives back a connection taken by Acquire
+func (sc *MemorySpectatorCounters) Release(debateID, ip, spectatorHash string) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, key := range spectatorConnectionKeys(debateID, ip, spectatorHash) {
+		if sc.connections[key] <= 1 {
+			delete(sc.connections, key)
+		} else {
+			sc.connections[key]--
+		}
+	}
+}
+
+// ReserveVote counts a vote against its IP's allowance for a poll unless it is used up
+func (sc *MemorySpectatorCounters) ReserveVote(debateID, pollID, ip string, limit int) bool {
+	key := anonymousVoteKey(debateID, pollID, ip)
+	now := time.Now()
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	allowance, ok := sc.votes[key]
+	if !ok || !now.Before(allowance.expiresAt) {
+		allowance = memoryVoteAllowance{expiresAt: now.Add(anonymousVoteTTL)}
+	}
+	if allowance.count >= limit {
+		return false
+	}
+	allowance.count++
+	sc.votes[key] = allowance
+	return true
+}
+
+// ReleaseVote returns a reserved vote that was not counted
+func (sc *MemorySpectatorCounters) ReleaseVote(debateID, pollID, ip string) {
+	key := anonymousVoteKey(debateID, pollID, ip)
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if allowance, ok := sc.votes[key]; ok && allowance.count > 0 {
+		allowance.count--
+		sc.votes[key] = allowance
+	}
+}
+
+// forget drops a debate's vote allowances. Connection counts are left for the open
+// connections to release.
+func (sc *MemorySpectatorCounters) forget(debateID string) {
+	prefix := "debate:" + debateID + ":"
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for key := range sc.votes {
+		if strings.HasPrefix(key, prefix) {
+			delete(sc.votes, key)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package debate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpectatorHash(t *testing.T) {
+	t.Cleanup(func() { spectatorSecret = nil })
+	SetSpectatorSecret("first-secret")
+	user := SpectatorHash("user-1", "device-1")
+	if user != SpectatorHash("user-1", "other-device") {
+		t.Error("a logged-in spectator's hash should not depend on the device")
+	}
+	if user == SpectatorHash("", "user-1") {
+		t.Error("an anonymous device ID should not collide with a user ID")
+	}
+	anon := SpectatorHash("", "device-1")
+
+	SetSpectatorSecret("second-secret")
+	if SpectatorHash("", "device-1") == anon {
+		t.Error("the hash should change with the server secret")
+	}
+}
+
+func TestSetSpectatorSecretRequiresASecret(t *testing.T) {
+	if err := SetSpectatorSecret(""); err == nil {
+		t.Error("Expected an empty secret to be refused")
+	}
+}
+
+func TestSetSpectatorLimitsDefaults(t *testing.T) {
+	defer SetSpectatorLimits(DefaultSpectatorLimits())
+	SetSpectatorLimits(SpectatorLimits{MaxConnectionsPerIP: 2})
+	limits := GetSpectatorLimits()
+	if limits.MaxConnectionsPerIP != 2 {
+		t.Errorf("MaxConnectionsPerIP = %d, want 2", limits.MaxConnectionsPerIP)
+	}
+	if limits.MaxConnectionsPerDevice != DefaultSpectatorLimits().MaxConnectionsPerDevice {
+		t.Errorf("MaxConnectionsPerDevice = %d, want the default", limits.MaxConnectionsPerDevice)
+	}
+}
+
+func TestAcquireSpectatorConnection(t *testing.T) {
+	useMiniredis(t)
+	defer SetSpectatorLimits(DefaultSpectatorLimits())
+	SetSpectatorLimits(SpectatorLimits{MaxConnectionsPerIP: 3, MaxConnectionsPerDevice: 2})
+
+	for i := 0; i < 2; i++ {
+		if admitted, err := AcquireSpectatorConnection("d1", "1.2.3.4", "device-a"); err != nil || !admitted {
+			t.Fatalf("Expected connection %d to be admitted, got %v %v", i+1, admitted, err)
+		}
+	}
+	if admitted, _ := AcquireSpectatorConnection("d1", "1.2.3.4", "device-a"); admitted {
+		t.Error("Expected a third connection from one device to be refused")
+	}
+	if admitted, _ := AcquireSpectatorConnection("d1", "1.2.3.4", "device-b"); !admitted {
+		t.Error("Expected another device on the same IP to be admitted")
+	}
+	if admitted, _ := AcquireSpectatorConnection("d1", "1.2.3.4", "device-c"); admitted {
+		t.Error("Expected a fourth connection from one IP to be refused")
+	}
+	if admitted, _ := AcquireSpectatorConnection("d2", "1.2.3.4", "device-c"); !admitted {
+		t.Error("Expected the limits to apply per debate")
+	}
+
+	if err := ReleaseSpectatorConnection("d1", "1.2.3.4", "device-a"); err != nil {
+		t.Fatalf("Expected the connection to be released, got %v", err)
+	}
+	if admitted, _ := AcquireSpectatorConnection("d1", "1.2.3.4", "device-a"); !admitted {
+		t.Error("Expected a released connection to be available again")
+	}
+}
+
+func TestReserveAnonymousVote(t *testing.T) {
+	server := useMiniredis(t)
+	defer SetSpectatorLimits(DefaultSpectatorLimits())
+	SetSpectatorLimits(SpectatorLimits{MaxVotesPerIP: 2})
+
+	for i := 0; i < 2; i++ {
+		if reserved, err := ReserveAnonymousVote("d1", "p1", "1.2.3.4"); err != nil || !reserved {
+			t.Fatalf("Expected vote %d to be reserved, got %v %v", i+1, reserved, err)
+		}
+	}
+	if reserved, _ := ReserveAnonymousVote("d1", "p1", "1.2.3.4"); reserved {
+		t.Error("Expected a third vote from one IP to be refused")
+	}
+	key := anonymousVoteKey("d1", "p1", "1.2.3.4")
+	if got, _ := server.Get(key); got != "2" {
+		t.Errorf("Expected a refused vote to leave the count at 2, got %s", got)
+	}
+	if ttl := server.TTL(key); ttl <= 0 || ttl > anonymousVoteTTL {
+		t.Errorf("Expected the count to expire within %v, got %v", anonymousVoteTTL, ttl)
+	}
+	if reserved, _ := ReserveAnonymousVote("d1", "p2", "1.2.3.4"); !reserved {
+		t.Error("Expected the allowance to apply per poll")
+	}
+
+	ReleaseAnonymousVote("d1", "p1", "1.2.3.4")
+	if reserved, _ := ReserveAnonymousVote("d1", "p1", "1.2.3.4"); !reserved {
+		t.Error("Expected a released vote to be available again")
+	}
+
+	server.FastForward(anonymousVoteTTL + time.Second)
+	for i := 0; i < 2; i++ {
+		if reserved, _ := ReserveAnonymousVote("d1", "p1", "1.2.3.4"); !reserved {
+			t.Errorf("Expected the allowance to reset once the count expires, vote %d was refused", i+1)
+		}
+	}
+}
+
+func TestMemorySpectatorCounters(t *testing.T) {
+	counters := NewMemorySpectatorCounters()
+	limits := SpectatorLimits{MaxConnectionsPerIP: 3, MaxConnectionsPerDevice: 2}
+
+	for i := 0; i < 2; i++ {
+		if !counters.Acquire("d1", "1.2.3.4", "device-a", limits) {
+			t.Fatalf("Expected connection %d to be admitted", i+1)
+		}
+	}
+	if counters.Acquire("d1", "1.2.3.4", "device-a", limits) {
+		t.Error("Expected a third connection from one device to be refused")
+	}
+	if !counters.Acquire("d1", "1.2.3.4", "device-b", limits) {
+		t.Error("Expected another device on the same IP to be admitted")
+	}
+	if counters.Acquire("d1", "1.2.3.4", "device-c", limits) {
+		t.Error("Expected a fourth connection from one IP to be refused")
+	}
+	counters.Release("d1", "1.2.3.4", "device-a")
+	if !counters.Acquire("d1", "1.2.3.4", "device-a", limits) {
+		t.Error("Expected a released connection to be available again")
+	}
+
+	for i := 0; i < 2; i++ {
+		if !counters.ReserveVote("d1", "p1", "1.2.3.4", 2) {
+			t.Fatalf("Expected vote %d to be reserved", i+1)
+		}
+	}
+	if counters.ReserveVote("d1", "p1", "1.2.3.4", 2) {
+		t.Error("Expected a third vote from one IP to be refused")
+	}
+	counters.ReleaseVote("d1", "p1", "1.2.3.4")
+	if !counters.ReserveVote("d1", "p1", "1.2.3.4", 2) {
+		t.Error("Expected a released vote to be available again")
+	}
+	counters.forget("d1")
+	if !counters.ReserveVote("d1", "p1", "1.2.3.4", 2) {
+		t.Error("Expected the allowance to reset once the debate is forgotten")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -14,165 +15,197 @@ type DebateHub interface {
 	BroadcastToDebate(debateID string, event *Event)
 }
 
-// StreamConsumer handles Redis Stream consumer group operations
+// reclaimInterval is how often a consumer looks for messages left unacknowledged
+const reclaimInterval = 30 * time.Second
+
+// instanceID names this process's consumer groups. Each instance reads through its own
+// group: a shared group would hand every event to only one instance, leaving spectators
+// connected elsewhere without it.
+var instanceID = func() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}()
+
+// StreamConsumer reads one debate's Redis Stream through this instance's consumer group
+// and forwards the events to the hub. It runs while the debate has spectators here.
 type StreamConsumer struct {
 	rdb          *redis.Client
 	ctx          context.Context
+	cancel       context.CancelFunc
+	done         chan struct{}
+	debateID     string
+	streamKey    string
+	groupName    string
 	consumerName string
-	instanceID   string
 	hub          DebateHub
 }
 
-// NewStreamConsumer creates a new StreamConsumer instance
-func NewStreamConsumer(hub DebateHub) *StreamConsumer {
+// NewStreamConsumer creates a consumer for a debate, or nil without Redis
+func NewStreamConsumer(debateID string, hub DebateHub) *StreamConsumer {
 	rdb := GetRedisClient()
 	if rdb == nil {
 		return nil
 	}
 
-	hostname, _ := os.Hostname()
-	pid := os.Getpid()
-	instanceID := fmt.Sprintf("%s-%d", hostname, pid)
-	consumerName := fmt.Sprintf("consumer-%s", instanceID)
-
+	ctx, cancel := context.WithCancel(GetContext())
 	return &StreamConsumer{
 		rdb:          rdb,
-		ctx:          GetContext(),
-		consumerName: consumerName,
-		instanceID:   instanceID,
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+		debateID:     debateID,
+		streamKey:    fmt.Sprintf("debate:%s:events", debateID),
+		groupName:    fmt.Sprintf("debate:%s:group:%s", debateID, instanceID),
+		consumerName: fmt.Sprintf("consumer-%s", instanceID),
 		hub:          hub,
 	}
 }
 
-// StartConsumerGroup starts consuming from Redis Stream for a debate
-func (sc *StreamConsumer) StartConsumerGroup(debateID string) error {
+// Start creates the consumer group if needed and starts consuming
+func (sc *StreamConsumer) Start() error {
 	if sc == nil || sc.rdb == nil {
 		return fmt.Errorf("Redis client not available")
 	}
 
-	streamKey := fmt.Sprintf("debate:%s:events", debateID)
-	groupName := fmt.Sprintf("debate:%s:group", debateID)
-
-	// Create consumer group if it doesn't exist
-	err := sc.rdb.XGroupCreateMkStream(sc.ctx, streamKey, groupName, "0").Err()
+	// The group starts at the stream's tail; earlier events reach spectators through the
+	// poll snapshot and reconnect replay. An existing group is reused.
+	err := sc.rdb.XGroupCreateMkStream(sc.ctx, sc.streamKey, sc.groupName, "$").Err()
 	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
-		// Continue anyway, group might already exist
+		close(sc.done)
+		return fmt.Errorf("failed to create consumer group: %w", err)
 	}
 
-	// Start consuming in a goroutine
-	go sc.consumeLoop(debateID, streamKey, groupName)
-
+	go sc.consumeLoop()
 	return nil
 }
 
-// consumeLoop continuously reads from the stream and forwards to WebSocket clients
-func (sc *StreamConsumer) consumeLoop(debateID, streamKey, groupName string) {
+// destroyGroup removes this instance's consumer group once no consumer reads it
+func (sc *StreamConsumer) destroyGroup() error {
+	return sc.rdb.XGroupDestroy(GetContext(), sc.streamKey, sc.groupName).Err()
+}
+
+// consumeLoop reads from the stream and forwards events to WebSocket clients until stopped
+func (sc *StreamConsumer) consumeLoop() {
+	defer close(sc.done)
+	lastReclaim := time.Now()
 
-	for {
-		// Read from stream with consumer group
+	for sc.ctx.Err() == nil {
 		streams, err := sc.rdb.XReadGroup(sc.ctx, &redis.XReadGroupArgs{
-			Group:    groupName,
+			Group:    sc.groupName,
 			Consumer: sc.consumerName,
-			Streams:  []string{streamKey, ">"},
+			Streams:  []string{sc.streamKey, ">"},
 			Count:    100,
 			Block:    time.Second,
 		}).Result()
 
-		if err != nil {
-			if err == redis.Nil {
-				// No messages, continue
-				continue
+		if err != nil && err != redis.Nil {
+			if sc.ctx.Err() != nil {
+				return
 			}
 			time.Sleep(time.Second)
 			continue
 		}
 
-		// Process messages
 		for _, stream := range streams {
 			for _, message := range stream.Messages {
-				// Process message
-				if err := sc.processMessage(debateID, message); err != nil {
+				if err := sc.processMessage(message); err != nil {
 					continue
 				}
-
-				// ACK message after successful processing
-				if err := sc.rdb.XAck(sc.ctx, streamKey, groupName, message.ID).Err(); err != nil {
-				}
+				sc.rdb.XAck(sc.ctx, sc.streamKey, sc.groupName, message.ID)
 			}
 		}
 
-		// Handle pending messages (reclaim stalled messages)
-		go sc.reclaimPendingMessages(debateID, streamKey, groupName)
+		// Reclaim stalled messages now and then rather than on every read
+		if time.Since(lastReclaim) >= reclaimInterval {
+			sc.reclaimPendingMessages()
+			lastReclaim = time.Now()
+		}
 	}
 }
 
 // processMessage processes a stream message and forwards to WebSocket clients
-func (sc *StreamConsumer) processMessage(debateID string, message redis.XMessage) error {
-	// Extract event data from message
-	eventData, ok := message.Values["data"].(string)
-	if !ok {
-		return fmt.Errorf("invalid message format: missing data field")
-	}
-
-	// Unmarshal event
-	event, err := UnmarshalEvent(eventData)
+func (sc *StreamConsumer) processMessage(message redis.XMessage) error {
+	event, err := decodeStreamMessage(message)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal event: %w", err)
+		return err
 	}
 
 	// Forward event to all connected WebSocket clients for this debate
 	// The BroadcastToDebate method will format it correctly
-	sc.hub.BroadcastToDebate(debateID, event)
+	sc.hub.BroadcastToDebate(sc.debateID, event)
 
 	return nil
 }
 
-// reclaimPendingMessages reclaims pending messages that haven't been ACKed
-func (sc *StreamConsumer) reclaimPendingMessages(debateID, streamKey, groupName string) {
-	// Check for pending messages older than 30 seconds
+// reclaimPendingMessages reprocesses messages that have been pending for more than
+// reclaimInterval
+func (sc *StreamConsumer) reclaimPendingMessages() {
 	pending, err := sc.rdb.XPendingExt(sc.ctx, &redis.XPendingExtArgs{
-		Stream: streamKey,
-		Group:  groupName,
+		Stream: sc.streamKey,
+		Group:  sc.groupName,
 		Start:  "-",
 		End:    "+",
 		Count:  100,
 	}).Result()
-
 	if err != nil {
 		return
 	}
 
 	for _, p := range pending {
-		// If message is pending for more than 30 seconds, claim it
-		// p.Idle is already a time.Duration representing idle time
-		if p.Idle > 30*time.Second {
-			claimed, err := sc.rdb.XClaim(sc.ctx, &redis.XClaimArgs{
-				Stream:   streamKey,
-				Group:    groupName,
-				Consumer: sc.consumerName,
-				MinIdle:  30 * time.Second,
-				Messages: []string{p.ID},
-			}).Result()
-
-			if err == nil && len(claimed) > 0 {
-				// Process reclaimed message
-				for _, msg := range claimed {
-					sc.processMessage(debateID, msg)
-					sc.rdb.XAck(sc.ctx, streamKey, groupName, msg.ID)
-				}
-			}
+		if p.Idle <= reclaimInterval {
+			continue
+		}
+		claimed, err := sc.rdb.XClaim(sc.ctx, &redis.XClaimArgs{
+			Stream:   sc.streamKey,
+			Group:    sc.groupName,
+			Consumer: sc.consumerName,
+			MinIdle:  reclaimInterval,
+			Messages: []string{p.ID},
+		}).Result()
+		if err != nil {
+			continue
+		}
+		for _, msg := range claimed {
+			sc.processMessage(msg)
+			sc.rdb.XAck(sc.ctx, sc.streamKey, sc.groupName, msg.ID)
 		}
 	}
 }
 
-// PublishEvent publishes an event to the Redis Stream
-func PublishEvent(debateID string, event *Event) error {
-	rdb := GetRedisClient()
-	if rdb == nil {
-		return fmt.Errorf("Redis client not available")
+// decodeStreamMessage reads the event stored in a stream message
+func decodeStreamMessage(message redis.XMessage) (*Event, error) {
+	eventData, ok := message.Values["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid message format: missing data field")
 	}
-	ctx := GetContext()
+	event, err := UnmarshalEvent(eventData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	event.ID = message.ID
+	return event, nil
+}
 
+// streamConsumers holds the running consumer of each debate and hub on this instance
+var (
+	streamConsumers   = map[streamSubscription]*StreamConsumer{}
+	streamConsumersMu sync.Mutex
+)
+
+type streamSubscription struct {
+	debateID string
+	hub      DebateHub
+}
+
+// RedisEventStream carries spectator events through a Redis Stream per debate, so every
+// instance delivers them to its own spectators
+type RedisEventStream struct {
+	rdb *redis.Client
+	ctx context.Context
+}
+
+// Publish appends an event to the debate's Redis Stream
+func (s *RedisEventStream) Publish(debateID string, event *Event) error {
 	streamKey := fmt.Sprintf("debate:%s:events", debateID)
 
 	// Marshal event to JSON
@@ -181,19 +214,102 @@ func PublishEvent(debateID string, event *Event) error {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// Add to stream with MAXLEN to bound history
-	_, err = rdb.XAdd(ctx, &redis.XAddArgs{
+	// Not trimmed, so the archive gets the whole debate; the stream expires with the
+	// rest of the debate's state once it is archived
+	id, err := s.rdb.XAdd(s.ctx, &redis.XAddArgs{
 		Stream: streamKey,
 		Values: map[string]interface{}{
 			"data": eventData,
 		},
-		MaxLen: 10000,
-		Approx: true, // Use ~ for approximate trimming
 	}).Result()
 
 	if err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
+	event.ID = id
+
+	return nil
+}
+
+// ReadSince returns the events stored after lastEventID, oldest first. The range is
+// exclusive, so the event the spectator last saw is not sent again.
+func (s *RedisEventStream) ReadSince(debateID, lastEventID string) ([]*Event, error) {
+	streamKey := fmt.Sprintf("debate:%s:events", debateID)
+	messages, err := s.rdb.XRangeN(s.ctx, streamKey, "("+lastEventID, "+", maxReplayEvents).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	return decodeStreamMessages(messages), nil
+}
+
+// ReadAll returns every event kept in the debate's stream, oldest first
+func (s *RedisEventStream) ReadAll(debateID string) ([]*Event, error) {
+	streamKey := fmt.Sprintf("debate:%s:events", debateID)
+	messages, err := s.rdb.XRange(s.ctx, streamKey, "-", "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return decodeStreamMessages(messages), nil
+}
 
+// decodeStreamMessages decodes stream messages, skipping any that are malformed
+func decodeStreamMessages(messages []redis.XMessage) []*Event {
+	events := make([]*Event, 0, len(messages))
+	for _, message := range messages {
+		event, err := decodeStreamMessage(message)
+		if err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// Subscribe starts this instance's consumer for the debate, which delivers every new
+// event to the hub. A hub that is already subscribed keeps its consumer.
+func (s *RedisEventStream) Subscribe(debateID string, hub DebateHub) error {
+	key := streamSubscription{debateID: debateID, hub: hub}
+	streamConsumersMu.Lock()
+	defer streamConsumersMu.Unlock()
+	if _, ok := streamConsumers[key]; ok {
+		return nil
+	}
+	consumer := NewStreamConsumer(debateID, hub)
+	if consumer == nil {
+		return fmt.Errorf("Redis client not available")
+	}
+	if err := consumer.Start(); err != nil {
+		return err
+	}
+	streamConsumers[key] = consumer
+	return nil
+}
+
+// Unsubscribe stops the hub's consumer for the debate without waiting for its last read.
+// The instance's consumer group is removed once the consumer has exited, unless the
+// debate was subscribed again meanwhile.
+func (s *RedisEventStream) Unsubscribe(debateID string, hub DebateHub) error {
+	key := streamSubscription{debateID: debateID, hub: hub}
+	streamConsumersMu.Lock()
+	consumer, ok := streamConsumers[key]
+	delete(streamConsumers, key)
+	streamConsumersMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	consumer.cancel()
+	go func() {
+		<-consumer.done
+		streamConsumersMu.Lock()
+		defer streamConsumersMu.Unlock()
+		for active := range streamConsumers {
+			if active.debateID == debateID {
+				return
+			}
+		}
+		consumer.destroyGroup()
+	}()
 	return nil
 }
@@ -0,0 +1,100 @@
+package debate
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncHub records the events a stream consumer delivers from its goroutine
+type syncHub struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (h *syncHub) BroadcastToDebate(debateID string, event *Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *syncHub) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.events)
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestRedisEventStreamDeliversEachEventOnce(t *testing.T) {
+	server := useMiniredis(t)
+	stream := GetEventStream()
+	hub := &syncHub{}
+	if err := stream.Subscribe("d1", hub); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if err := stream.Subscribe("d1", hub); err != nil {
+		t.Fatalf("Second subscribe failed: %v", err)
+	}
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		event, _ := NewEvent("pollVote", VotePayload{})
+		if err := PublishEvent("d1", event); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if !waitFor(func() bool { return hub.count() >= 3 }) {
+		t.Fatalf("Expected 3 events delivered, got %d", hub.count())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if hub.count() != 3 {
+		t.Errorf("Expected each event delivered once, got %d deliveries", hub.count())
+	}
+	hub.mu.Lock()
+	for i, event := range hub.events {
+		if event.ID != ids[i] {
+			t.Errorf("Expected event %d to be %s, got %s", i, ids[i], event.ID)
+		}
+	}
+	hub.mu.Unlock()
+
+	missed, err := ReadEventsSince("d1", ids[0])
+	if err != nil || len(missed) != 2 || missed[0].ID != ids[1] {
+		t.Errorf("Expected the 2 events after the first, got %d (err %v)", len(missed), err)
+	}
+
+	groupName := "debate:d1:group:" + instanceID
+	if err := stream.Unsubscribe("d1", hub); err != nil {
+		t.Fatalf("Unsubscribe failed: %v", err)
+	}
+	groupGone := waitFor(func() bool {
+		groups, err := rdb.XInfoGroups(GetContext(), "debate:d1:events").Result()
+		if err != nil {
+			return false
+		}
+		for _, g := range groups {
+			if g.Name == groupName {
+				return false
+			}
+		}
+		return true
+	})
+	if !groupGone {
+		t.Error("Expected the consumer group to be removed after the last unsubscribe")
+	}
+	if !server.Exists("debate:d1:events") {
+		t.Error("Expected the stream to be kept for replay")
+	}
+}
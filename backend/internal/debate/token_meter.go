@@ -0,0 +1,89 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TokenMeter counts the model tokens spent each UTC day, in total and per user, so AI
+// features can be held to daily budgets
+type TokenMeter interface {
+	// Add records tokens spent today on a user's behalf, or on no one's for an empty userID
+	Add(userID string, tokens int64) error
+	// Usage returns the tokens a user and everyone together have spent today
+	Usage(userID string) (user, global int64, err error)
+}
+
+// tokenMeterTTL keeps a day's counters a little past the end of the day
+const tokenMeterTTL = 48 * time.Hour
+
+// NewTokenMeter returns the Redis meter when Redis is configured, so budgets hold across
+// instances, and the in-process one otherwise
+func NewTokenMeter() TokenMeter {
+	if rdb := GetRedisClient(); rdb != nil {
+		return &RedisTokenMeter{rdb: rdb, ctx: GetContext()}
+	}
+	return memoryTokenMeter
+}
+
+// tokenDay is the UTC day budgets are counted in
+func tokenDay(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+func globalTokensKey(day string) string {
+	return fmt.Sprintf("ai:tokens:%s:global", day)
+}
+
+func userTokensKey(day, userID string) string {
+	return fmt.Sprintf("ai:tokens:%s:user:%s", day, userID)
+}
+
+// RedisTokenMeter keeps daily token counters in Redis
+type RedisTokenMeter struct {
+	rdb *redis.Client
+	ctx context.Context
+}
+
+// Add records tokens spent today
+func (tm *RedisTokenMeter) Add(userID string, tokens int64) error {
+	if tokens <= 0 {
+		return nil
+	}
+	day := tokenDay(time.Now())
+	pipe := tm.rdb.TxPipeline()
+	pipe.IncrBy(tm.ctx, globalTokensKey(day), tokens)
+	pipe.Expire(tm.ctx, globalTokensKey(day), tokenMeterTTL)
+	if userID != "" {
+		pipe.IncrBy(tm.ctx, userTokensKey(day, userID), tokens)
+		pipe.Expire(tm.ctx, userTokensKey(day, userID), tokenMeterTTL)
+	}
+	if _, err := pipe.Exec(tm.ctx); err != nil {
+		return fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return nil
+}
+
+// Usage returns today's totals for a user and for everyone
+func (tm *RedisTokenMeter) Usage(userID string) (int64, int64, error) {
+	day := tokenDay(time.Now())
+	keys := []string{globalTokensKey(day)}
+	if userID != "" {
+		keys = append(keys, userTokensKey(day, userID))
+	}
+	values, err := tm.rdb.MGet(tm.ctx, keys...).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read token usage: %w", err)
+	}
+	counts := make([]int64, 2)
+	for i, value := range values {
+		if s, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+	return counts[1], counts[0], nil
+}
@@ -0,0 +1,54 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryTokenMeter is the process-wide token meter used when Redis is not configured
+var memoryTokenMeter = NewMemoryTokenMeter()
+
+// MemoryTokenMeter keeps today's token counters in process memory. Budgets apply per instance.
+type MemoryTokenMeter struct {
+	mu     sync.Mutex
+	day    string
+	global int64
+	users  map[string]int64
+}
+
+// NewMemoryTokenMeter creates an empty in-process token meter
+func NewMemoryTokenMeter() *MemoryTokenMeter {
+	return &MemoryTokenMeter{users: make(map[string]int64)}
+}
+
+// rollover starts new counters when the day changes; callers hold tm.mu
+func (tm *MemoryTokenMeter) rollover() {
+	if day := tokenDay(time.Now()); day != tm.day {
+		tm.day = day
+		tm.global = 0
+		tm.users = make(map[string]int64)
+	}
+}
+
+// Add records tokens spent today
+func (tm *MemoryTokenMeter) Add(userID string, tokens int64) error {
+	if tokens <= 0 {
+		return nil
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.rollover()
+	tm.global += tokens
+	if userID != "" {
+		tm.users[userID] += tokens
+	}
+	return nil
+}
+
+// Usage returns today's totals for a user and for everyone
+func (tm *MemoryTokenMeter) Usage(userID string) (int64, int64, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.rollover()
+	return tm.users[userID], tm.global, nil
+}
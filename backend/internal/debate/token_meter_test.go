@@ -0,0 +1,27 @@
+package debate
+
+import "testing"
+
+func TestMemoryTokenMeter(t *testing.T) {
+	meter := NewMemoryTokenMeter()
+	meter.Add("u1", 120)
+	meter.Add("u1", 30)
+	meter.Add("", 50)
+	meter.Add("u2", -10)
+
+	user, global, err := meter.Usage("u1")
+	if err != nil {
+		t.Fatalf("Usage: %v", err)
+	}
+	if user != 150 || global != 200 {
+		t.Errorf("usage = %d of %d, want 150 of 200", user, global)
+	}
+	if user, _, _ := meter.Usage("u2"); user != 0 {
+		t.Errorf("negative token counts should be ignored, got %d", user)
+	}
+
+	meter.day = "2000-01-01"
+	if user, global, _ := meter.Usage("u1"); user != 0 || global != 0 {
+		t.Errorf("a new day should start from zero, got %d of %d", user, global)
+	}
+}
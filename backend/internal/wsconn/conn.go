@@ -0,0 +1,232 @@
+package wsconn
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	// ErrClosed is returned when writing to a connection that has been closed
+	ErrClosed = errors.New("websocket connection closed")
+	// ErrSlowConsumer is returned when a client's send queue is full and it is evicted
+	ErrSlowConsumer = errors.New("websocket client evicted: send queue full")
+)
+
+// Config controls heartbeats, deadlines and buffering for a connection
+type Config struct {
+	WriteWait      time.Duration // Deadline for a single write
+	PongWait       time.Duration // Idle timeout; reset by every message and pong from the peer
+	PingPeriod     time.Duration // How often to ping the peer; must be less than PongWait
+	SendQueueSize  int           // Outgoing messages buffered before the client counts as slow
+	MaxMessageSize int64         // Largest message accepted from the peer
+}
+
+// DefaultConfig returns the settings used by the hubs unless they need larger messages
+func DefaultConfig() Config {
+	return Config{
+		WriteWait:      10 * time.Second,
+		PongWait:       60 * time.Second,
+		PingPeriod:     54 * time.Second,
+		SendQueueSize:  256,
+		MaxMessageSize: 512 << 10,
+	}
+}
+
+type frame struct {
+	messageType int
+	data        []byte
+}
+
+// Conn wraps a websocket connection with a single writer goroutine. Writes are queued and
+// never block the caller; a client whose queue fills up is evicted. Reads extend the idle
+// deadline, and the writer pings the peer so dead connections are detected.
+type Conn struct {
+	ws      *websocket.Conn
+	cfg     Config
+	metrics *Metrics
+
+	send      chan frame
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+// New takes over ws and starts its writer. The caller must keep reading with ReadMessage
+// (or ReadJSON) until it returns an error, then call Close.
+func New(ws *websocket.Conn, metrics *Metrics, cfg Config) *Conn {
+	defaults := DefaultConfig()
+	if cfg.WriteWait <= 0 {
+		cfg.WriteWait = defaults.WriteWait
+	}
+	if cfg.PongWait <= 0 {
+		cfg.PongWait = defaults.PongWait
+	}
+	if cfg.PingPeriod <= 0 || cfg.PingPeriod >= cfg.PongWait {
+		cfg.PingPeriod = cfg.PongWait * 9 / 10
+	}
+	if cfg.SendQueueSize <= 0 {
+		cfg.SendQueueSize = defaults.SendQueueSize
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = defaults.MaxMessageSize
+	}
+
+	c := &Conn{
+		ws:      ws,
+		cfg:     cfg,
+		metrics: metrics,
+		send:    make(chan frame, cfg.SendQueueSize),
+		done:    make(chan struct{}),
+	}
+
+	ws.SetReadLimit(cfg.MaxMessageSize)
+	ws.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(cfg.PongWait))
+	})
+
+	metrics.opened()
+	go c.writePump()
+	return c
+}
+
+// Underlying returns the wrapped connection, e.g. for use as a map key
+func (c *Conn) Underlying() *websocket.Conn {
+	return c.ws
+}
+
+// Done is closed once the connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// ReadMessage reads the next message and extends the idle deadline
+func (c *Conn) ReadMessage() (int, []byte, error) {
+	messageType, data, err := c.ws.ReadMessage()
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			c.metrics.idleTimeout()
+		}
+		c.Close()
+		return messageType, data, err
+	}
+	c.ws.SetReadDeadline(time.Now().Add(c.cfg.PongWait))
+	c.metrics.received()
+	return messageType, data, nil
+}
+
+// ReadJSON reads the next message and decodes it into v
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON queues v as a text message
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(websocket.TextMessage, data)
+}
+
+// WriteMessage queues a message without blocking. If the send queue is full the client
+// is too slow to keep up, so it is disconnected instead of stalling the broadcaster.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case c.send <- frame{messageType: messageType, data: data}:
+		return nil
+	default:
+		c.metrics.evicted()
+		c.CloseWith(websocket.ClosePolicyViolation, "slow consumer")
+		return ErrSlowConsumer
+	}
+}
+
+// Close closes the connection normally after flushing queued messages
+func (c *Conn) Close() {
+	c.CloseWith(websocket.CloseNormalClosure, "")
+}
+
+// CloseWith closes the connection with the given close code and reason
+func (c *Conn) CloseWith(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+		c.metrics.closed()
+	})
+}
+
+// writePump is the only goroutine that writes to the connection
+func (c *Conn) writePump() {
+	ticker := time.NewTicker(c.cfg.PingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.ws.Close()
+	}()
+
+	for {
+		select {
+		case f := <-c.send:
+			c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+			if err := c.ws.WriteMessage(f.messageType, f.data); err != nil {
+				c.metrics.writeError()
+				c.Close()
+				return
+			}
+			c.metrics.sent()
+
+		case <-ticker.C:
+			c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.metrics.writeError()
+				c.Close()
+				return
+			}
+
+		case <-c.done:
+			c.flush()
+			c.ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(c.closeCode, c.closeText),
+				time.Now().Add(c.cfg.WriteWait))
+			return
+		}
+	}
+}
+
+// flush writes whatever is still queued, bounded by a single write deadline.
+// An evicted client gets nothing more.
+func (c *Conn) flush() {
+	if c.closeCode == websocket.ClosePolicyViolation {
+		return
+	}
+	c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteWait))
+	for {
+		select {
+		case f := <-c.send:
+			if err := c.ws.WriteMessage(f.messageType, f.data); err != nil {
+				return
+			}
+			c.metrics.sent()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package wsconn
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialPair returns a server-side Conn and the client end of the same websocket
+func dialPair(t *testing.T, metrics *Metrics, cfg Config) (*Conn, *websocket.Conn) {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	serverConns := make(chan *Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		serverConns <- New(ws, metrics, cfg)
+	}))
+	t.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return <-serverConns, client
+}
+
+func TestConnDeliversQueuedMessages(t *testing.T) {
+	metrics := &Metrics{hub: "test"}
+	conn, client := dialPair(t, metrics, DefaultConfig())
+
+	if err := conn.WriteJSON(map[string]string{"type": "hello"}); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got map[string]string
+	if err := client.ReadJSON(&got); err != nil {
+		t.Fatalf("ReadJSON failed: %v", err)
+	}
+	if got["type"] != "hello" {
+		t.Errorf("Expected hello message, got %v", got)
+	}
+
+	conn.Close()
+	if err := conn.WriteJSON("late"); err != ErrClosed {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+	if snap := metrics.Snapshot(); snap.Active != 0 || snap.Accepted != 1 {
+		t.Errorf("Unexpected metrics after close: %+v", snap)
+	}
+}
+
+func TestConnEvictsSlowConsumer(t *testing.T) {
+	metrics := &Metrics{hub: "test"}
+	cfg := DefaultConfig()
+	cfg.SendQueueSize = 1
+	conn, _ := dialPair(t, metrics, cfg)
+
+	// The client never reads, so the queue fills once the writer blocks on the socket
+	payload := make([]byte, 1<<20)
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = conn.WriteMessage(websocket.BinaryMessage, payload)
+	}
+	if err != ErrSlowConsumer {
+		t.Fatalf("Expected ErrSlowConsumer, got %v", err)
+	}
+	select {
+	case <-conn.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected evicted connection to be closed")
+	}
+	if metrics.Snapshot().SlowConsumers != 1 {
+		t.Errorf("Expected one eviction, got %+v", metrics.Snapshot())
+	}
+}
@@ -0,0 +1,129 @@
+package wsconn
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Metrics counts connection activity for one hub
+type Metrics struct {
+	hub string
+
+	active       atomic.Int64
+	accepted     atomic.Int64
+	closedTotal  atomic.Int64
+	evictions    atomic.Int64
+	idleTimeouts atomic.Int64
+	writeErrors  atomic.Int64
+	sentTotal    atomic.Int64
+	receivedMsgs atomic.Int64
+}
+
+// Snapshot is a point-in-time copy of a hub's metrics
+type Snapshot struct {
+	Hub              string `json:"hub"`
+	Active           int64  `json:"active"`
+	Accepted         int64  `json:"accepted"`
+	Closed           int64  `json:"closed"`
+	SlowConsumers    int64  `json:"slowConsumersEvicted"`
+	IdleTimeouts     int64  `json:"idleTimeouts"`
+	WriteErrors      int64  `json:"writeErrors"`
+	MessagesSent     int64  `json:"messagesSent"`
+	MessagesReceived int64  `json:"messagesReceived"`
+}
+
+var (
+	registry   = make(map[string]*Metrics)
+	registryMu sync.Mutex
+)
+
+// HubMetrics returns the metrics for a hub, creating them on first use
+func HubMetrics(hub string) *Metrics {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	m, ok := registry[hub]
+	if !ok {
+		m = &Metrics{hub: hub}
+		registry[hub] = m
+	}
+	return m
+}
+
+// Snapshots returns the metrics of every hub, sorted by hub name
+func Snapshots() []Snapshot {
+	registryMu.Lock()
+	hubs := make([]*Metrics, 0, len(registry))
+	for _, m := range registry {
+		hubs = append(hubs, m)
+	}
+	registryMu.Unlock()
+
+	out := make([]Snapshot, 0, len(hubs))
+	for _, m := range hubs {
+		out = append(out, m.Snapshot())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Hub < out[j].Hub })
+	return out
+}
+
+// Snapshot copies the hub's current counters
+func (m *Metrics) Snapshot() Snapshot {
+	return Snapshot{
+		Hub:              m.hub,
+		Active:           m.active.Load(),
+		Accepted:         m.accepted.Load(),
+		Closed:           m.closedTotal.Load(),
+		SlowConsumers:    m.evictions.Load(),
+		IdleTimeouts:     m.idleTimeouts.Load(),
+		WriteErrors:      m.writeErrors.Load(),
+		MessagesSent:     m.sentTotal.Load(),
+		MessagesReceived: m.receivedMsgs.Load(),
+	}
+}
+
+// The recorders below accept a nil receiver so connections can be created without metrics
+
+func (m *Metrics) opened() {
+	if m != nil {
+		m.active.Add(1)
+		m.accepted.Add(1)
+	}
+}
+
+func (m *Metrics) closed() {
+	if m != nil {
+		m.active.Add(-1)
+		m.closedTotal.Add(1)
+	}
+}
+
+func (m *Metrics) evicted() {
+	if m != nil {
+		m.evictions.Add(1)
+	}
+}
+
+func (m *Metrics) idleTimeout() {
+	if m != nil {
+		m.idleTimeouts.Add(1)
+	}
+}
+
+func (m *Metrics) writeError() {
+	if m != nil {
+		m.writeErrors.Add(1)
+	}
+}
+
+func (m *Metrics) sent() {
+	if m != nil {
+		m.sentTotal.Add(1)
+	}
+}
+
+func (m *Metrics) received() {
+	if m != nil {
+		m.receivedMsgs.Add(1)
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"arguehub/internal/debate"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RateLimitMiddleware limits requests to an endpoint by the action's configured policy.
+// Authenticated requests are limited per user, anonymous ones per IP. Requests pass
+// through when the limiter fails.
+func RateLimitMiddleware(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := debate.NewRateLimiter().Allow(action, rateLimitSubject(c))
+		if err != nil {
+			log.Printf("Rate limit check failed for %s: %v", action, err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":      "Too many requests",
+				"retryAfter": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitSubject identifies who a request counts against
+func rateLimitSubject(c *gin.Context) string {
+	if userID, exists := c.Get("userID"); exists {
+		if id, ok := userID.(primitive.ObjectID); ok {
+			return "user:" + id.Hex()
+		}
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"arguehub/internal/debate"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// newRateLimitedRouter serves one endpoint limited by the given policy, with the limiter's
+// windows in a miniredis server
+func newRateLimitedRouter(t *testing.T, policy debate.RateLimitPolicy) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	server := miniredis.RunT(t)
+	if err := debate.InitRedis(server.Addr(), "", 0); err != nil {
+		t.Fatal(err)
+	}
+	debate.SetRateLimitPolicies(map[string]debate.RateLimitPolicy{"test": policy})
+	t.Cleanup(func() { debate.SetRateLimitPolicies(nil) })
+
+	router := gin.New()
+	router.GET("/limited", RateLimitMiddleware("test"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router, server
+}
+
+func request(router *gin.Engine, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	router, server := newRateLimitedRouter(t, debate.RateLimitPolicy{Limit: 2, Window: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		w := request(router, "10.0.0.1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected request %d to pass, got %d", i+1, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != []string{"1", "0"}[i] {
+			t.Errorf("Expected X-RateLimit-Remaining %s, got %s", []string{"1", "0"}[i], got)
+		}
+	}
+
+	w := request(router, "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected the third request to be limited, got %d", w.Code)
+	}
+	if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+		t.Errorf("Expected X-RateLimit-Limit 2, got %s", got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "60" {
+		t.Errorf("Expected Retry-After 60, got %s", got)
+	}
+
+	if w := request(router, "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("Expected another IP to have its own window, got %d", w.Code)
+	}
+
+	// The window's key expires with the window
+	server.FastForward(time.Minute + time.Second)
+	if w := request(router, "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("Expected a request once the window expired, got %d", w.Code)
+	}
+}
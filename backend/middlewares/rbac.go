@@ -84,8 +84,11 @@ m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
 		enforcer.AddPolicy("admin", "comment", "delete")
 		enforcer.AddPolicy("admin", "user", "read")
 		enforcer.AddPolicy("admin", "analytics", "read")
+		enforcer.AddPolicy("admin", "bot", "write")
+		enforcer.AddPolicy("admin", "custom_bot", "moderate")
 		enforcer.AddPolicy("moderator", "comment", "delete")
 		enforcer.AddPolicy("moderator", "user", "read")
+		enforcer.AddPolicy("moderator", "custom_bot", "moderate")
 	}
 
 	// Load policies
@@ -112,8 +115,11 @@ func ensureDefaultPolicies() {
 		{"admin", "comment", "delete"},
 		{"admin", "user", "read"},
 		{"admin", "analytics", "read"},
+		{"admin", "bot", "write"},
+		{"admin", "custom_bot", "moderate"},
 		{"moderator", "comment", "delete"},
 		{"moderator", "user", "read"},
+		{"moderator", "custom_bot", "moderate"},
 	}
 
 	// Add policies if they don't exist
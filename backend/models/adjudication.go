@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Decision modes for a live debate room
+const (
+	DecisionModeAI    = "ai"    // AI judge decides (default)
+	DecisionModeHuman = "human" // Human judge ballots decide
+	DecisionModeBlend = "blend" // Weighted mix of AI and human totals
+)
+
+// Winner sources decide how the Oxford-style audience swing vote affects the result
+const (
+	WinnerSourceJudge = "judge" // The judges' decision stands; the swing is reported alongside (default)
+	WinnerSourceSwing = "swing" // The side that moved the audience most wins
+	WinnerSourceMix   = "mix"   // Weighted mix of the judges' margin and the swing
+)
+
+// MotionTally counts one motion vote (before or after the debate)
+type MotionTally struct {
+	For       int64 `bson:"for" json:"for"`
+	Against   int64 `bson:"against" json:"against"`
+	Undecided int64 `bson:"undecided" json:"undecided"`
+	Total     int64 `bson:"total" json:"total"`
+}
+
+// AudienceSwing is how far the audience moved between the pre-debate and post-debate
+// motion votes. Swing values are changes in each side's share of the vote, in percentage points.
+type AudienceSwing struct {
+	Pre    MotionTally        `bson:"pre" json:"pre"`
+	Post   MotionTally        `bson:"post" json:"post"`
+	Swing  map[string]float64 `bson:"swing" json:"swing"` // "for" and "against"
+	Winner string             `bson:"winner" json:"winner"`
+}
+
+// JudgeBallot is a human judge's score for a single debate phase.
+// Phases map onto the same criteria the AI judge scores (opening, cross
+// examination questions, cross examination answers, closing).
+type JudgeBallot struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	RoomID      string             `bson:"roomId" json:"roomId"`
+	JudgeID     string             `bson:"judgeId" json:"judgeId"`
+	JudgeName   string             `bson:"judgeName" json:"judgeName"`
+	Phase       string             `bson:"phase" json:"phase"`         // e.g. "openingFor"
+	Criterion   string             `bson:"criterion" json:"criterion"` // e.g. "opening_statement"
+	Side        string             `bson:"side" json:"side"`           // "for" or "against"
+	Score       int                `bson:"score" json:"score"`         // 0-10
+	Reason      string             `bson:"reason" json:"reason"`
+	SubmittedAt time.Time          `bson:"submittedAt" json:"submittedAt"`
+}
+
+// ModeratorWarning records a warning issued by a room moderator
+type ModeratorWarning struct {
+	ModeratorID   string    `bson:"moderatorId" json:"moderatorId"`
+	ModeratorName string    `bson:"moderatorName" json:"moderatorName"`
+	TargetUserID  string    `bson:"targetUserId" json:"targetUserId"`
+	Reason        string    `bson:"reason" json:"reason"`
+	Phase         string    `bson:"phase,omitempty" json:"phase,omitempty"`
+	IssuedAt      time.Time `bson:"issuedAt" json:"issuedAt"`
+}
+
+// RoomAdjudication holds the adjudication settings and moderator record of a room
+type RoomAdjudication struct {
+	DecisionMode string             `bson:"decisionMode,omitempty" json:"decisionMode,omitempty"`
+	HumanWeight  *float64           `bson:"humanWeight,omitempty" json:"humanWeight,omitempty"` // Share of human totals in blend mode (0-1); nil uses the default
+	Judges       []string           `bson:"judges,omitempty" json:"judges,omitempty"`           // User IDs allowed to take a judge seat
+	Moderators   []string           `bson:"moderators,omitempty" json:"moderators,omitempty"`   // User IDs allowed to moderate
+	OwnerID      string             `bson:"ownerId,omitempty" json:"ownerId,omitempty"`
+	WinnerSource string             `bson:"winnerSource,omitempty" json:"winnerSource,omitempty"`
+	SwingWeight  *float64           `bson:"swingWeight,omitempty" json:"swingWeight,omitempty"` // Share of the swing in mix mode (0-1); nil uses the default
+	Warnings     []ModeratorWarning `bson:"moderatorWarnings,omitempty" json:"moderatorWarnings,omitempty"`
+	EndedBy      string             `bson:"endedBy,omitempty" json:"endedBy,omitempty"`
+	EndedAt      *time.Time         `bson:"endedAt,omitempty" json:"endedAt,omitempty"`
+	// Audience swing recorded when the debate finished
+	Swing *AudienceSwing `bson:"audienceSwing,omitempty" json:"audienceSwing,omitempty"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AIUsage records the tokens one model call used
+type AIUsage struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"userId,omitempty" json:"userId,omitempty"` // Empty for calls not made on a user's behalf
+	Task         string             `bson:"task" json:"task"`
+	Level        string             `bson:"level,omitempty" json:"level,omitempty"` // Bot level, for bot replies
+	Provider     string             `bson:"provider" json:"provider"`
+	Model        string             `bson:"model,omitempty" json:"model,omitempty"` // Empty when the provider's default was used
+	InputTokens  int                `bson:"inputTokens" json:"inputTokens"`
+	OutputTokens int                `bson:"outputTokens" json:"outputTokens"`
+	Degraded     bool               `bson:"degraded,omitempty" json:"degraded,omitempty"` // A budget was spent and the degraded model answered
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// AIUsageSummary is a user's model token spending for the current day
+type AIUsageSummary struct {
+	Date            string `json:"date"` // UTC day, YYYY-MM-DD
+	TokensUsed      int64  `json:"tokensUsed"`
+	DailyLimit      int64  `json:"dailyLimit,omitempty"` // Zero when unlimited
+	BudgetExhausted bool   `json:"budgetExhausted"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Audience question states
+const (
+	AudienceQuestionPending  = "pending"  // Waiting for a moderator or the host
+	AudienceQuestionApproved = "approved" // Eligible to be put to the debaters
+	AudienceQuestionHidden   = "hidden"   // Removed from the queue by a moderator or the host
+	AudienceQuestionAnswered = "answered" // Answered by the debaters
+)
+
+// AudienceQuestion is a spectator question in a debate's moderated Q&A queue
+type AudienceQuestion struct {
+	ID            string     `bson:"_id" json:"qId"`
+	DebateID      string     `bson:"debateId" json:"debateId"`
+	Text          string     `bson:"text" json:"text"`
+	SpectatorHash string     `bson:"spectatorHash" json:"-"`
+	Status        string     `bson:"status" json:"status"`
+	Upvotes       int        `bson:"upvotes" json:"upvotes"`
+	Upvoters      []string   `bson:"upvoters,omitempty" json:"-"` // Spectator hashes that upvoted, one vote each
+	ModeratedBy   string     `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModeratedAt   *time.Time `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	AskedAt       *time.Time `bson:"askedAt,omitempty" json:"askedAt,omitempty"` // When the question was pushed to the debaters
+	AnsweredAt    *time.Time `bson:"answeredAt,omitempty" json:"answeredAt,omitempty"`
+	CreatedAt     time.Time  `bson:"createdAt" json:"createdAt"`
+}
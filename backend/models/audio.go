@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Transcription states of an audio chunk
+const (
+	AudioStatusPending     = "pending"
+	AudioStatusTranscribed = "transcribed"
+	AudioStatusFailed      = "failed"
+)
+
+// TranscriptSegment is a timed piece of transcribed speech. Start and End are
+// seconds from the beginning of the phase.
+type TranscriptSegment struct {
+	Start float64 `bson:"start" json:"start"`
+	End   float64 `bson:"end" json:"end"`
+	Text  string  `bson:"text" json:"text"`
+}
+
+// AudioChunk is a piece of debate audio recorded by one debater during a phase
+type AudioChunk struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	RoomID     string              `bson:"roomId" json:"roomId"`
+	UserID     string              `bson:"userId" json:"userId"`
+	Role       string              `bson:"role,omitempty" json:"role,omitempty"`
+	Phase      string              `bson:"phase" json:"phase"`
+	Seq        int                 `bson:"seq" json:"seq"`           // Order of the chunk within the phase
+	UploadID   string              `bson:"uploadId" json:"-"`        // Identifies one upload of the chunk; a re-sent chunk gets a new one
+	OffsetMs   int64               `bson:"offsetMs" json:"offsetMs"` // Start of the chunk relative to the phase start
+	MimeType   string              `bson:"mimeType" json:"mimeType"`
+	Path       string              `bson:"path" json:"-"`
+	Size       int64               `bson:"size" json:"size"`
+	Status     string              `bson:"status" json:"status"`
+	Attempts   int                 `bson:"attempts" json:"attempts"` // Transcription attempts so far
+	Error      string              `bson:"error,omitempty" json:"error,omitempty"`
+	Text       string              `bson:"text,omitempty" json:"text,omitempty"`
+	Segments   []TranscriptSegment `bson:"segments,omitempty" json:"segments,omitempty"`
+	ReceivedAt time.Time           `bson:"receivedAt" json:"receivedAt"`
+}
+
+// PhaseTranscript is the server-side transcript of one speaker in one phase
+type PhaseTranscript struct {
+	Phase    string              `json:"phase"`
+	UserID   string              `json:"userId"`
+	Role     string              `json:"role,omitempty"`
+	Text     string              `json:"text"`
+	Segments []TranscriptSegment `json:"segments"`
+}
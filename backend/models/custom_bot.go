@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Who can debate a custom bot
+const (
+	CustomBotPrivate = "private" // Only its creator
+	CustomBotPublic  = "public"  // Anyone, listed in the community directory
+)
+
+// Moderation states of a custom bot
+const (
+	CustomBotActive = "active"
+	CustomBotHidden = "hidden" // Hidden by a moderator; only its creator can see it and nobody can debate it
+)
+
+// CustomBot is a debate bot persona created by a user. It covers a subset of a built-in
+// bot's personality, plus optional reference material the bot argues from.
+type CustomBot struct {
+	ID                primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	OwnerID           primitive.ObjectID   `json:"ownerId" bson:"ownerId"`
+	Name              string               `json:"name" bson:"name"`
+	Level             string               `json:"level" bson:"level"`
+	Description       string               `json:"description,omitempty" bson:"description,omitempty"`
+	Tone              string               `json:"tone" bson:"tone"`
+	RhetoricalStyle   string               `json:"rhetoricalStyle,omitempty" bson:"rhetoricalStyle,omitempty"`
+	DebateStrategy    string               `json:"debateStrategy" bson:"debateStrategy"`
+	InteractionStyle  string               `json:"interactionStyle,omitempty" bson:"interactionStyle,omitempty"`
+	Catchphrases      []string             `json:"catchphrases" bson:"catchphrases"`
+	ReferenceMaterial string               `json:"referenceMaterial,omitempty" bson:"referenceMaterial,omitempty"`
+	Visibility        string               `json:"visibility" bson:"visibility"`
+	Status            string               `json:"status" bson:"status"`
+	ModerationNote    string               `json:"moderationNote,omitempty" bson:"moderationNote,omitempty"` // Why a moderator hid the bot
+	Reporters         []primitive.ObjectID `json:"-" bson:"reporters,omitempty"`
+	ReportCount       int                  `json:"reportCount,omitempty" bson:"reportCount"`
+	Stats             CustomBotStats       `json:"stats" bson:"stats"`
+	CreatedAt         time.Time            `json:"createdAt" bson:"createdAt"`
+	UpdatedAt         time.Time            `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CustomBotStats counts how a custom bot has been used
+type CustomBotStats struct {
+	Debates    int64      `json:"debates" bson:"debates"`   // Debates started against the bot
+	Replies    int64      `json:"replies" bson:"replies"`   // Replies the bot has generated
+	UserWins   int64      `json:"userWins" bson:"userWins"` // Judged debates the opponent won
+	BotWins    int64      `json:"botWins" bson:"botWins"`
+	Draws      int64      `json:"draws" bson:"draws"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty" bson:"lastUsedAt,omitempty"`
+}
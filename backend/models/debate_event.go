@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// ArchivedEvent is one spectator event from an ended debate's stream
+type ArchivedEvent struct {
+	ID         string      `bson:"_id" json:"id"` // "<debateId>:<eventId>"
+	DebateID   string      `bson:"debateId" json:"debateId"`
+	EventID    string      `bson:"eventId" json:"eventId"` // Stream ID, which orders the events
+	Type       string      `bson:"type" json:"type"`
+	Payload    interface{} `bson:"payload" json:"payload"`
+	Timestamp  int64       `bson:"timestamp" json:"timestamp"`
+	ArchivedAt time.Time   `bson:"archivedAt" json:"archivedAt"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Point of information states
+const (
+	POIStatusPending  = "pending"  // Waiting for the speaker to respond
+	POIStatusAccepted = "accepted" // Offerer currently holds the floor
+	POIStatusDeclined = "declined" // Speaker declined the point
+	POIStatusExpired  = "expired"  // Speaker did not respond in time
+	POIStatusEnded    = "ended"    // Accepted point finished and the floor returned to the speaker
+	POIStatusCanceled = "canceled" // Phase changed before the point was resolved
+)
+
+// PointOfInformation is an interruption offered by the non-speaking side of a live debate
+type PointOfInformation struct {
+	ID            string     `bson:"_id" json:"id"`
+	RoomID        string     `bson:"roomId" json:"roomId"`
+	Phase         string     `bson:"phase" json:"phase"`
+	OfferedBy     string     `bson:"offeredBy" json:"offeredBy"` // User ID of the offering debater
+	OfferedByName string     `bson:"offeredByName" json:"offeredByName"`
+	OffererRole   string     `bson:"offererRole" json:"offererRole"` // "for" or "against"
+	SpeakerID     string     `bson:"speakerId,omitempty" json:"speakerId,omitempty"`
+	SpeakerName   string     `bson:"speakerName,omitempty" json:"speakerName,omitempty"`
+	Content       string     `bson:"content,omitempty" json:"content,omitempty"` // Optional summary of the point
+	Status        string     `bson:"status" json:"status"`
+	RequestedAt   time.Time  `bson:"requestedAt" json:"requestedAt"`
+	RespondedAt   *time.Time `bson:"respondedAt,omitempty" json:"respondedAt,omitempty"`
+	EndedAt       *time.Time `bson:"endedAt,omitempty" json:"endedAt,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ArchivedPoll holds the frozen results of a spectator poll once its debate has ended
+type ArchivedPoll struct {
+	ID         string           `bson:"_id" json:"id"` // "<debateId>:<pollId>"
+	DebateID   string           `bson:"debateId" json:"debateId"`
+	PollID     string           `bson:"pollId" json:"pollId"`
+	Question   string           `bson:"question" json:"question"`
+	Options    []string         `bson:"options" json:"options"`
+	Counts     map[string]int64 `bson:"counts" json:"counts"`
+	Voters     int64            `bson:"voters" json:"voters"`
+	CreatedBy  string           `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	OpenedAt   *time.Time       `bson:"openedAt,omitempty" json:"openedAt,omitempty"`
+	ClosedAt   time.Time        `bson:"closedAt" json:"closedAt"`
+	ArchivedAt time.Time        `bson:"archivedAt" json:"archivedAt"`
+}
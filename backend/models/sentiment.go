@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// SentimentBucket holds the audience activity recorded in one time bucket of a debate
+type SentimentBucket struct {
+	ID            string                      `bson:"_id" json:"-"` // "<debateId>:<bucket start in Unix seconds>"
+	DebateID      string                      `bson:"debateId" json:"debateId"`
+	Start         time.Time                   `bson:"start" json:"start"`
+	Reactions     map[string]int64            `bson:"reactions,omitempty" json:"reactions,omitempty"`         // side ("for", "against" or "none") -> count
+	ReactionTypes map[string]int64            `bson:"reactionTypes,omitempty" json:"reactionTypes,omitempty"` // reaction -> count
+	Votes         map[string]map[string]int64 `bson:"votes,omitempty" json:"votes,omitempty"`                 // poll ID -> option -> votes cast in the bucket
+	Questions     int64                       `bson:"questions,omitempty" json:"questions,omitempty"`
+}
+
+// DebatePhaseMark records when a debate moved to a phase
+type DebatePhaseMark struct {
+	DebateID string    `bson:"debateId" json:"debateId"`
+	Phase    string    `bson:"phase" json:"phase"`
+	Speaker  string    `bson:"speaker,omitempty" json:"speaker,omitempty"` // "for", "against" or empty
+	At       time.Time `bson:"at" json:"at"`
+}
+
+// TimelinePhase is one phase of a debate on its sentiment timeline
+type TimelinePhase struct {
+	Phase     string     `json:"phase"`
+	Speaker   string     `json:"speaker,omitempty"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+// TimelinePoint is the audience activity of one bucket, placed in the phase it fell in
+type TimelinePoint struct {
+	Start         time.Time                   `json:"start"`
+	Phase         string                      `json:"phase,omitempty"`
+	Reactions     map[string]int64            `json:"reactions"` // side -> count; unattributed reactions count for the side speaking
+	ReactionTypes map[string]int64            `json:"reactionTypes,omitempty"`
+	Votes         map[string]map[string]int64 `json:"votes,omitempty"`      // Votes cast in the bucket
+	PollTotals    map[string]map[string]int64 `json:"pollTotals,omitempty"` // Running tallies, showing how polls swung
+	Questions     int64                       `json:"questions"`
+}
+
+// SentimentTimeline is a debate's audience activity over time, aligned with its phases
+type SentimentTimeline struct {
+	DebateID      string          `json:"debateId"`
+	BucketSeconds int             `json:"bucketSeconds"`
+	Phases        []TimelinePhase `json:"phases"`
+	Points        []TimelinePoint `json:"points"`
+}
@@ -0,0 +1,39 @@
+package models
+
+// Timing modes for a live debate room
+const (
+	TimingModeFixed    = "fixed"    // Each phase has a fixed duration (default)
+	TimingModeTimeBank = "timebank" // Each side draws from a chess-clock style time bank
+)
+
+// TimeBankConfig configures chess-clock timing for a room. Each side starts with
+// TotalSeconds, spends it while speaking and is cut off when the bank runs out.
+type TimeBankConfig struct {
+	TotalSeconds     int `json:"totalSeconds" bson:"totalSeconds"`                             // Budget per side across all speaking phases
+	IncrementSeconds int `json:"incrementSeconds,omitempty" bson:"incrementSeconds,omitempty"` // Added to a side's bank when its phase starts
+	// Speaking time guaranteed in every phase, even once the bank is empty
+	MinPhaseSeconds map[string]int `json:"minPhaseSeconds,omitempty" bson:"minPhaseSeconds,omitempty"`
+	// Rating handicap: the lower-rated side gets this many extra seconds per 100 rating points of difference
+	HandicapSecondsPer100 int `json:"handicapSecondsPer100,omitempty" bson:"handicapSecondsPer100,omitempty"`
+	MaxHandicapSeconds    int `json:"maxHandicapSeconds,omitempty" bson:"maxHandicapSeconds,omitempty"`
+}
+
+// RoomTiming is how a live room's phases are timed
+type RoomTiming struct {
+	Mode     string          `bson:"timingMode"`
+	TimeBank *TimeBankConfig `bson:"timeBank"`
+	// Length of each phase in fixed timing; phases left out keep the server default
+	PhaseSeconds map[string]int `bson:"phaseSeconds"`
+}
+
+// TimeBankState is the broadcast view of a room's time banks
+type TimeBankState struct {
+	Remaining    map[string]float64 `json:"remaining"` // Seconds left per side ("for", "against")
+	Handicap     map[string]int     `json:"handicap,omitempty"`
+	ActiveSide   string             `json:"activeSide,omitempty"`
+	Phase        string             `json:"phase,omitempty"`
+	PhaseElapsed float64            `json:"phaseElapsed"`
+	PhaseMinimum int                `json:"phaseMinimum,omitempty"`
+	Running      bool               `json:"running"`
+	CutOff       bool               `json:"cutOff"`
+}
@@ -16,9 +16,11 @@ type DebateTranscript struct {
 }
 
 type DebateResult struct {
-	RoomID    string    `bson:"roomId" json:"roomId"`
-	Result    string    `bson:"result" json:"result"`
-	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	RoomID              string               `bson:"roomId" json:"roomId"`
+	Result              string               `bson:"result" json:"result"`
+	PointsOfInformation []PointOfInformation `bson:"pointsOfInformation,omitempty" json:"pointsOfInformation,omitempty"`
+	AudienceQuestions   []AudienceQuestion   `bson:"audienceQuestions,omitempty" json:"audienceQuestions,omitempty"`
+	CreatedAt           time.Time            `bson:"createdAt" json:"createdAt"`
 }
 
 // SavedDebateTranscript represents a saved debate transcript that users can view later
@@ -32,8 +34,14 @@ type SavedDebateTranscript struct {
 	Result      string             `bson:"result" json:"result"`     // "win", "loss", "draw", "pending"
 	Messages    []Message          `bson:"messages" json:"messages"`
 	Transcripts map[string]string  `bson:"transcripts,omitempty" json:"transcripts,omitempty"` // For user vs user debates
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// Room of a user vs user debate
+	RoomID string `bson:"roomId,omitempty" json:"roomId,omitempty"`
+	// Points of information offered during a user vs user debate
+	PointsOfInformation []PointOfInformation `bson:"pointsOfInformation,omitempty" json:"pointsOfInformation,omitempty"`
+	// Spectator questions the debaters answered
+	AudienceQuestions []AudienceQuestion `bson:"audienceQuestions,omitempty" json:"audienceQuestions,omitempty"`
+	CreatedAt         time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt         time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 func (s SavedDebateTranscript) MarshalJSON() ([]byte, error) {
@@ -22,6 +22,7 @@ func SetupAdminRoutes(router *gin.Engine, configPath string) {
 		// Analytics
 		admin.GET("/analytics", controllers.GetAnalytics)
 		admin.GET("/analytics/history", controllers.GetAnalyticsHistory)
+		admin.GET("/analytics/websockets", middlewares.RBACMiddleware("analytics", "read"), controllers.GetWebsocketMetrics)
 		
 		// Debates management
 		admin.GET("/debates", controllers.GetDebates)
@@ -33,6 +34,18 @@ func SetupAdminRoutes(router *gin.Engine, configPath string) {
 		admin.DELETE("/comments/:id", middlewares.RBACMiddleware("comment", "delete"), controllers.DeleteComment)
 		admin.DELETE("/comments/bulk", middlewares.RBACMiddleware("comment", "delete"), controllers.BulkDeleteComments)
 		
+		// Bot personalities
+		admin.GET("/bots", controllers.ListBotPersonalities)
+		admin.GET("/bots/:name", controllers.GetBotPersonality)
+		admin.POST("/bots", middlewares.RBACMiddleware("bot", "write"), controllers.CreateBotPersonality)
+		admin.PUT("/bots/:name", middlewares.RBACMiddleware("bot", "write"), controllers.UpdateBotPersonality)
+		admin.DELETE("/bots/:name", middlewares.RBACMiddleware("bot", "write"), controllers.DeleteBotPersonality)
+		admin.POST("/bots/:name/versions/:version/restore", middlewares.RBACMiddleware("bot", "write"), controllers.RestoreBotPersonalityVersion)
+
+		// User-created bots (admin and moderator can hide them)
+		admin.GET("/custom-bots", controllers.ListCustomBotsForReview)
+		admin.PUT("/custom-bots/:id/status", middlewares.RBACMiddleware("custom_bot", "moderate"), controllers.ModerateCustomBot)
+
 		// Admin action logs
 		admin.GET("/logs", controllers.GetAdminActionLogs)
 	}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAIUsageRoutes registers the user's view of their daily AI token budget
+func SetupAIUsageRoutes(router *gin.RouterGroup) {
+	router.GET("/ai/usage", controllers.GetAIUsageHandler)
+}
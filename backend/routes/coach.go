@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"arguehub/controllers"
 	"arguehub/services"
 	"net/http"
 
@@ -17,7 +18,7 @@ func GetWeakStatement(c *gin.Context) {
 		return
 	}
 
-	weakStatement, err := services.GenerateWeakStatement(topic, stance)
+	weakStatement, err := services.GenerateWeakStatement(controllers.LLMContext(c), topic, stance)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate weak statement"})
 		return
@@ -39,7 +40,7 @@ func EvaluateStrengthenedArgument(c *gin.Context) {
 	}
 
 	// Evaluate the argument using the Gemini API with all required arguments
-	evaluation, err := services.EvaluateArgument(req.Topic, req.Stance, req.WeakStatementText, req.UserResponse)
+	evaluation, err := services.EvaluateArgument(controllers.LLMContext(c), req.Topic, req.Stance, req.WeakStatementText, req.UserResponse)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to evaluate argument"})
 		return
@@ -2,13 +2,15 @@ package routes
 
 import (
 	"arguehub/controllers"
+	"arguehub/internal/debate"
+	"arguehub/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupCommunityRoutes(router *gin.RouterGroup) {
 	// Post routes (specific routes must come before parameterized routes)
-	router.POST("/posts", controllers.CreatePostHandler)
+	router.POST("/posts", middlewares.RateLimitMiddleware(debate.ActionPost), controllers.CreatePostHandler)
 	router.GET("/posts/feed", controllers.GetFeedHandler)
 	router.GET("/posts/top/likes", controllers.GetTopLikedPostsHandler)
 
@@ -21,7 +23,7 @@ func SetupCommunityRoutes(router *gin.RouterGroup) {
 	router.DELETE("/posts/:id", controllers.DeletePostHandler)
 
 	// Comment routes
-	router.POST("/comments", controllers.CreateCommentHandler)
+	router.POST("/comments", middlewares.RateLimitMiddleware(debate.ActionComment), controllers.CreateCommentHandler)
 	router.GET("/comments/:transcriptId", controllers.GetCommentsHandler)
 	router.DELETE("/comments/:id", controllers.DeleteCommentHandler)
 
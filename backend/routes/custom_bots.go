@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCustomBotRoutes registers the API for user-created debate bots
+func SetupCustomBotRoutes(router *gin.RouterGroup) {
+	bots := router.Group("/custom-bots")
+	{
+		bots.GET("", controllers.ListMyCustomBots)
+		bots.GET("/community", controllers.ListCommunityCustomBots)
+		bots.POST("", controllers.CreateCustomBot)
+		bots.GET("/:id", controllers.GetCustomBot)
+		bots.PUT("/:id", controllers.UpdateCustomBot)
+		bots.DELETE("/:id", controllers.DeleteCustomBot)
+		bots.POST("/:id/report", controllers.ReportCustomBot)
+	}
+}
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"arguehub/controllers"
+	"arguehub/internal/debate"
+	"arguehub/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,8 +12,10 @@ import (
 func SetupDebateVsBotRoutes(router *gin.RouterGroup) {
 	vsbot := router.Group("/vsbot")
 	{
+		vsbot.GET("/bots", controllers.ListBots)
 		vsbot.POST("/create", controllers.CreateDebate)
-		vsbot.POST("/debate", controllers.SendDebateMessage)
+		vsbot.POST("/debate", middlewares.RateLimitMiddleware(debate.ActionVsBotMessage), controllers.SendDebateMessage)
+		vsbot.POST("/turn", controllers.RecordDebateTurn)
 		vsbot.POST("/judge", controllers.JudgeDebate)
 	}
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLiveDebateRoutes registers the live debates directory. It needs no login so
+// anonymous spectators can find debates to watch.
+func SetupLiveDebateRoutes(router gin.IRouter) {
+	router.GET("/debates/live", controllers.GetLiveDebatesHandler)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/models"
 	"arguehub/services"
 
 	"github.com/gin-gonic/gin"
@@ -23,6 +24,23 @@ type Room struct {
 	Type         string        `json:"type" bson:"type"`
 	OwnerID      string        `json:"ownerId" bson:"ownerId"`
 	Participants []Participant `json:"participants" bson:"participants"`
+	// Adjudication settings
+	DecisionMode string   `json:"decisionMode,omitempty" bson:"decisionMode,omitempty"` // ai, human or blend
+	HumanWeight  *float64 `json:"humanWeight,omitempty" bson:"humanWeight,omitempty"`
+	Judges       []string `json:"judges,omitempty" bson:"judges,omitempty"`
+	Moderators   []string `json:"moderators,omitempty" bson:"moderators,omitempty"`
+	// Timing settings
+	TimingMode string                 `json:"timingMode,omitempty" bson:"timingMode,omitempty"` // fixed or timebank
+	TimeBank   *models.TimeBankConfig `json:"timeBank,omitempty" bson:"timeBank,omitempty"`
+	// Custom phase lengths in seconds for the server phase timer
+	PhaseSeconds map[string]int `json:"phaseSeconds,omitempty" bson:"phaseSeconds,omitempty"`
+	// Audience swing settings
+	WinnerSource string   `json:"winnerSource,omitempty" bson:"winnerSource,omitempty"` // judge, swing or mix
+	SwingWeight  *float64 `json:"swingWeight,omitempty" bson:"swingWeight,omitempty"`
+	// Only votes from logged-in spectators count
+	RequireLoggedInSpectators bool `json:"requireLoggedInSpectators,omitempty" bson:"requireLoggedInSpectators,omitempty"`
+	// AI commentary for spectators after each phase
+	Commentary bool `json:"commentary,omitempty" bson:"commentary,omitempty"`
 }
 
 // Participant represents a user in a room.
@@ -44,7 +62,20 @@ func generateRoomID() string {
 // CreateRoomHandler handles POST /rooms and creates a new debate room.
 func CreateRoomHandler(c *gin.Context) {
 	type CreateRoomInput struct {
-		Type string `json:"type"` // public, private, invite
+		Type         string                 `json:"type"` // public, private, invite
+		DecisionMode string                 `json:"decisionMode"`
+		HumanWeight  *float64               `json:"humanWeight"` // Unset uses the default share
+		Judges       []string               `json:"judges"`
+		Moderators   []string               `json:"moderators"`
+		TimingMode   string                 `json:"timingMode"`
+		TimeBank     *models.TimeBankConfig `json:"timeBank"`
+		PhaseSeconds map[string]int         `json:"phaseSeconds"` // Phases left out keep the default length
+		WinnerSource string                 `json:"winnerSource"` // judge, swing or mix
+		SwingWeight  *float64               `json:"swingWeight"`  // Unset uses the default share
+		// Only votes from logged-in spectators count
+		RequireLoggedInSpectators bool `json:"requireLoggedInSpectators"`
+		// AI commentary for spectators after each phase
+		Commentary bool `json:"commentary"`
 	}
 
 	var input CreateRoomInput
@@ -52,6 +83,27 @@ func CreateRoomHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
 		return
 	}
+	if err := services.ValidateDecisionSettings(input.DecisionMode, input.HumanWeight, input.Judges); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := services.ValidateSwingWeight(input.SwingWeight); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	timingMode := services.NormalizeTimingMode(input.TimingMode)
+	if timingMode == models.TimingModeTimeBank {
+		if err := services.ValidateTimeBankConfig(input.TimeBank); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		input.TimeBank = nil
+	}
+	if err := services.ValidatePhaseSeconds(input.PhaseSeconds); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get user email from middleware-set context
 	email, exists := c.Get("email")
@@ -94,6 +146,18 @@ func CreateRoomHandler(c *gin.Context) {
 		Type:         input.Type,
 		OwnerID:      creatorParticipant.ID,
 		Participants: []Participant{creatorParticipant},
+		DecisionMode: services.NormalizeDecisionMode(input.DecisionMode),
+		HumanWeight:  input.HumanWeight,
+		Judges:       input.Judges,
+		Moderators:   input.Moderators,
+		TimingMode:   timingMode,
+		TimeBank:     input.TimeBank,
+		PhaseSeconds: input.PhaseSeconds,
+		WinnerSource: services.NormalizeWinnerSource(input.WinnerSource),
+		SwingWeight:  input.SwingWeight,
+
+		RequireLoggedInSpectators: input.RequireLoggedInSpectators,
+		Commentary:                input.Commentary,
 	}
 
 	roomCollection := db.MongoClient.Database("DebateAI").Collection("rooms")
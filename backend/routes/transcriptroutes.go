@@ -28,4 +28,24 @@ func SetupTranscriptRoutes(router *gin.RouterGroup) {
 
 	// Update specific transcript result
 	router.PUT("/transcript/:id/result", controllers.UpdateTranscriptResultHandler)
+
+	// Debate audio and the server-side transcript built from it
+	router.POST("/debate/:roomId/audio", controllers.UploadAudioChunkHandler)
+	router.GET("/debate/:roomId/audio-transcript", controllers.GetAudioTranscriptHandler)
+	router.POST("/debate/:roomId/audio/retry", controllers.RetryAudioTranscriptHandler)
+
+	// Spectator Q&A queue for a debate
+	router.GET("/debate/:roomId/questions", controllers.GetAudienceQuestionsHandler)
+
+	// Spectator polls, managed by the debate host or a moderator
+	router.POST("/debate/:roomId/polls", controllers.CreatePollHandler)
+	router.POST("/debate/:roomId/polls/:pollId/open", controllers.OpenPollHandler)
+	router.POST("/debate/:roomId/polls/:pollId/close", controllers.ClosePollHandler)
+	router.GET("/debate/:roomId/polls/results", controllers.GetArchivedPollsHandler)
+
+	// Spectator events of an ended debate
+	router.GET("/debate/:roomId/events", controllers.GetArchivedEventsHandler)
+
+	// Audience sentiment over the course of a debate
+	router.GET("/debate/:roomId/sentiment", controllers.GetSentimentTimelineHandler)
 }
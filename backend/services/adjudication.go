@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ballotCriterion describes which AI judge criterion and side a debate phase is scored under
+type ballotCriterion struct {
+	Criterion string
+	Side      string
+}
+
+// phaseBallotCriteria maps live debate phases onto the criteria used by JudgeDebateHumanVsHuman
+var phaseBallotCriteria = map[string]ballotCriterion{
+	"openingFor":           {Criterion: "opening_statement", Side: "for"},
+	"openingAgainst":       {Criterion: "opening_statement", Side: "against"},
+	"crossForQuestion":     {Criterion: "cross_examination_questions", Side: "for"},
+	"crossAgainstQuestion": {Criterion: "cross_examination_questions", Side: "against"},
+	"crossForAnswer":       {Criterion: "cross_examination_answers", Side: "for"},
+	"crossAgainstAnswer":   {Criterion: "cross_examination_answers", Side: "against"},
+	"closingFor":           {Criterion: "closing", Side: "for"},
+	"closingAgainst":       {Criterion: "closing", Side: "against"},
+}
+
+// defaultHumanWeight is the share of human ballots in blend mode when none is configured
+const defaultHumanWeight = 0.5
+
+// BallotCriterionForPhase returns the judging criterion and side scored in a phase
+func BallotCriterionForPhase(phase string) (string, string, bool) {
+	c, ok := phaseBallotCriteria[phase]
+	return c.Criterion, c.Side, ok
+}
+
+// NormalizeDecisionMode returns a supported decision mode, defaulting to the AI judge
+func NormalizeDecisionMode(mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case models.DecisionModeHuman:
+		return models.DecisionModeHuman
+	case models.DecisionModeBlend:
+		return models.DecisionModeBlend
+	default:
+		return models.DecisionModeAI
+	}
+}
+
+// GetRoomAdjudication loads the adjudication settings stored on a room
+func GetRoomAdjudication(ctx context.Context, roomID string) (*models.RoomAdjudication, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	var adjudication models.RoomAdjudication
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}).Decode(&adjudication); err != nil {
+		return nil, err
+	}
+	adjudication.DecisionMode = NormalizeDecisionMode(adjudication.DecisionMode)
+	return &adjudication, nil
+}
+
+// IsRoomHost reports whether a user hosts a room: its owner, or its first participant when
+// the room has no owner recorded
+func IsRoomHost(ctx context.Context, roomID, userID string) bool {
+	if db.MongoDatabase == nil || userID == "" {
+		return false
+	}
+
+	var room struct {
+		OwnerID      string `bson:"ownerId"`
+		Participants []struct {
+			ID string `bson:"id"`
+		} `bson:"participants"`
+	}
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}).Decode(&room); err != nil {
+		return false
+	}
+	if room.OwnerID != "" {
+		return room.OwnerID == userID
+	}
+	return len(room.Participants) > 0 && room.Participants[0].ID == userID
+}
+
+// IsDebateParticipant reports whether a user is one of a debate's debaters
+func IsDebateParticipant(ctx context.Context, roomID, userID string) bool {
+	if db.MongoDatabase == nil || userID == "" {
+		return false
+	}
+	filter := bson.M{"_id": roomID, "participants.id": userID}
+	count, err := db.MongoDatabase.Collection("rooms").CountDocuments(ctx, filter)
+	return err == nil && count > 0
+}
+
+// roomSeats is the part of a room that decides who may take its official seats
+type roomSeats struct {
+	OwnerID      string   `bson:"ownerId"`
+	Judges       []string `bson:"judges"`
+	Moderators   []string `bson:"moderators"`
+	Participants []struct {
+		ID string `bson:"id"`
+	} `bson:"participants"`
+}
+
+// CanTakeSeat reports whether a user may occupy a judge or moderator seat in a room.
+// Debaters never take an official seat, as both seats can decide or steer their debate.
+// Moderators must be the room owner or listed as a moderator; judges must be listed as a
+// judge, so a room without a judge list has no judge seats.
+func CanTakeSeat(ctx context.Context, roomID, userID, seat string) bool {
+	if db.MongoDatabase == nil || userID == "" {
+		return false
+	}
+	var room roomSeats
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}).Decode(&room); err != nil {
+		return false
+	}
+	return room.allows(userID, seat)
+}
+
+// allows reports whether the room lets a user take a seat
+func (room roomSeats) allows(userID, seat string) bool {
+	contains := func(ids []string) bool {
+		for _, id := range ids {
+			if id == userID {
+				return true
+			}
+		}
+		return false
+	}
+	for _, p := range room.Participants {
+		if p.ID == userID {
+			return false
+		}
+	}
+
+	switch seat {
+	case "moderator":
+		return room.OwnerID == userID || contains(room.Moderators)
+	case "judge":
+		return contains(room.Judges)
+	default:
+		return false
+	}
+}
+
+// ValidateDecisionSettings checks a room's decision mode and human weight. Human and blend
+// rooms are decided by their judges' ballots, so they need a judge list.
+func ValidateDecisionSettings(mode string, humanWeight *float64, judges []string) error {
+	if humanWeight != nil && (*humanWeight < 0 || *humanWeight > 1) {
+		return errors.New("humanWeight must be between 0 and 1")
+	}
+	if NormalizeDecisionMode(mode) != models.DecisionModeAI && len(judges) == 0 {
+		return errors.New("human and blend decisions need a judges list")
+	}
+	return nil
+}
+
+// SaveJudgeBallot stores a judge's ballot, replacing any earlier ballot for the same phase
+func SaveJudgeBallot(ctx context.Context, ballot *models.JudgeBallot) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	criterion, side, ok := BallotCriterionForPhase(ballot.Phase)
+	if !ok {
+		return errors.New("phase is not scored")
+	}
+	if ballot.Score < 0 || ballot.Score > 10 {
+		return errors.New("score must be between 0 and 10")
+	}
+	ballot.Criterion = criterion
+	ballot.Side = side
+	ballot.SubmittedAt = time.Now()
+
+	filter := bson.M{"roomId": ballot.RoomID, "judgeId": ballot.JudgeID, "phase": ballot.Phase}
+	update := bson.M{"$set": bson.M{
+		"judgeName":   ballot.JudgeName,
+		"criterion":   ballot.Criterion,
+		"side":        ballot.Side,
+		"score":       ballot.Score,
+		"reason":      ballot.Reason,
+		"submittedAt": ballot.SubmittedAt,
+	}}
+	_, err := db.MongoDatabase.Collection("debate_ballots").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// GetJudgeBallots returns every ballot submitted for a room
+func GetJudgeBallots(ctx context.Context, roomID string) ([]models.JudgeBallot, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	cursor, err := db.MongoDatabase.Collection("debate_ballots").Find(ctx, bson.M{"roomId": roomID})
+	if err != nil {
+		return nil, err
+	}
+	var ballots []models.JudgeBallot
+	if err := cursor.All(ctx, &ballots); err != nil {
+		return nil, err
+	}
+	return ballots, nil
+}
+
+// SetRoomDecisionMode updates how a room's winner is decided. A nil humanWeight restores
+// the default share.
+func SetRoomDecisionMode(ctx context.Context, roomID, mode string, humanWeight *float64) error {
+	adjudication, err := GetRoomAdjudication(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if err := ValidateDecisionSettings(mode, humanWeight, adjudication.Judges); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"decisionMode": NormalizeDecisionMode(mode)}}
+	if humanWeight != nil {
+		update["$set"].(bson.M)["humanWeight"] = *humanWeight
+	} else {
+		update["$unset"] = bson.M{"humanWeight": ""}
+	}
+	_, err = db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
+
+// RecordModeratorWarning appends a moderator warning to the room record
+func RecordModeratorWarning(ctx context.Context, roomID string, warning models.ModeratorWarning) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	update := bson.M{"$push": bson.M{"moderatorWarnings": warning}}
+	_, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
+
+// MarkRoomEnded records that a moderator ended the debate
+func MarkRoomEnded(ctx context.Context, roomID, moderatorID string) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	update := bson.M{"$set": bson.M{
+		"endedBy": moderatorID,
+		"endedAt": time.Now(),
+		"status":  "completed",
+	}}
+	_, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
+
+// TallyBallots averages the judges' totals per side, over the judges who scored that
+// side, so a judge who only scored one side does not pull the other side's average down.
+// Ballots are stored once per judge and phase, so each judge's total is on the same 40
+// point scale as the AI judge.
+func TallyBallots(ballots []models.JudgeBallot) (float64, float64, int) {
+	judges := make(map[string]struct{})
+	sideJudges := map[string]map[string]struct{}{"for": {}, "against": {}}
+	sums := map[string]float64{}
+	for _, b := range ballots {
+		_, side, ok := BallotCriterionForPhase(b.Phase)
+		if !ok {
+			continue
+		}
+		judges[b.JudgeID] = struct{}{}
+		sideJudges[side][b.JudgeID] = struct{}{}
+		sums[side] += float64(b.Score)
+	}
+
+	average := func(side string) float64 {
+		if len(sideJudges[side]) == 0 {
+			return 0
+		}
+		return sums[side] / float64(len(sideJudges[side]))
+	}
+	return average("for"), average("against"), len(judges)
+}
+
+// sideTotals reads the "total" section of a human-vs-human judge result
+func sideTotals(result map[string]interface{}) (float64, float64) {
+	total, ok := result["total"].(map[string]interface{})
+	if !ok {
+		return 0, 0
+	}
+	forTotal, _ := total["for"].(float64)
+	againstTotal, _ := total["against"].(float64)
+	return forTotal, againstTotal
+}
+
+// winnerFromTotals names the winning side for the verdict
+func winnerFromTotals(forTotal, againstTotal float64) string {
+	switch {
+	case forTotal > againstTotal:
+		return "For"
+	case againstTotal > forTotal:
+		return "Against"
+	default:
+		return "Draw"
+	}
+}
+
+// DecideRoomResult turns the AI judge's result into the room's decision before it is
+// stored: human ballots are combined according to the decision mode, then the audience
+// swing decides or contributes to the winner according to the winner source.
+func DecideRoomResult(ctx context.Context, roomID, aiResult string) string {
+	return ApplyAudienceSwing(ctx, roomID, ApplyRoomDecision(ctx, roomID, aiResult))
+}
+
+// ApplyRoomDecision combines the AI judge result with any human ballots according to
+// the room's decision mode. The AI result is returned unchanged when the room has no
+// ballots or the result is not JSON.
+func ApplyRoomDecision(ctx context.Context, roomID, aiResult string) string {
+	if db.MongoDatabase == nil {
+		return aiResult
+	}
+	ballots, err := GetJudgeBallots(ctx, roomID)
+	if err != nil || len(ballots) == 0 {
+		return aiResult
+	}
+
+	var judged map[string]interface{}
+	if err := json.Unmarshal([]byte(aiResult), &judged); err != nil {
+		return aiResult
+	}
+
+	mode := models.DecisionModeAI
+	humanWeight := defaultHumanWeight
+	if adjudication, err := GetRoomAdjudication(ctx, roomID); err == nil {
+		mode = adjudication.DecisionMode
+		if adjudication.HumanWeight != nil {
+			humanWeight = *adjudication.HumanWeight
+		}
+	}
+
+	aiFor, aiAgainst := sideTotals(judged)
+	humanFor, humanAgainst, judgeCount := TallyBallots(ballots)
+
+	finalFor, finalAgainst := aiFor, aiAgainst
+	switch mode {
+	case models.DecisionModeHuman:
+		finalFor, finalAgainst = humanFor, humanAgainst
+	case models.DecisionModeBlend:
+		finalFor = (1-humanWeight)*aiFor + humanWeight*humanFor
+		finalAgainst = (1-humanWeight)*aiAgainst + humanWeight*humanAgainst
+	}
+
+	judged["decision"] = map[string]interface{}{
+		"mode":        mode,
+		"humanWeight": humanWeight,
+		"ai":          map[string]float64{"for": aiFor, "against": aiAgainst},
+		"human":       map[string]interface{}{"for": humanFor, "against": humanAgainst, "judges": judgeCount},
+		"final":       map[string]float64{"for": finalFor, "against": finalAgainst},
+	}
+	judged["human_ballots"] = ballots
+
+	if mode != models.DecisionModeAI {
+		judged["total"] = map[string]float64{"for": finalFor, "against": finalAgainst}
+		verdict, ok := judged["verdict"].(map[string]interface{})
+		if !ok {
+			verdict = map[string]interface{}{}
+		}
+		verdict["winner"] = winnerFromTotals(finalFor, finalAgainst)
+		judged["verdict"] = verdict
+	}
+
+	out, err := json.Marshal(judged)
+	if err != nil {
+		return aiResult
+	}
+	return string(out)
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestTallyBallots(t *testing.T) {
+	ballots := []models.JudgeBallot{
+		{JudgeID: "j1", Phase: "openingFor", Score: 8},
+		{JudgeID: "j1", Phase: "openingAgainst", Score: 6},
+		{JudgeID: "j1", Phase: "closingFor", Score: 7},
+		{JudgeID: "j2", Phase: "openingFor", Score: 6},
+		{JudgeID: "j2", Phase: "openingAgainst", Score: 9},
+		{JudgeID: "j2", Phase: "setup", Score: 10}, // not a scored phase
+	}
+
+	forTotal, againstTotal, judges := TallyBallots(ballots)
+	if judges != 2 {
+		t.Fatalf("Expected 2 judges, got %d", judges)
+	}
+	if forTotal != 10.5 {
+		t.Errorf("Expected average for total 10.5, got %v", forTotal)
+	}
+	if againstTotal != 7.5 {
+		t.Errorf("Expected average against total 7.5, got %v", againstTotal)
+	}
+
+	if _, _, judges := TallyBallots(nil); judges != 0 {
+		t.Errorf("Expected no judges for empty ballots, got %d", judges)
+	}
+
+	// A judge who only scored For counts towards For's average alone
+	partial := append(ballots, models.JudgeBallot{JudgeID: "j3", Phase: "openingFor", Score: 9})
+	forTotal, againstTotal, judges = TallyBallots(partial)
+	if judges != 3 || forTotal != 10 || againstTotal != 7.5 {
+		t.Errorf("Expected 10 and 7.5 from 3 judges, got %v and %v from %d", forTotal, againstTotal, judges)
+	}
+}
+
+func TestOfficialSeats(t *testing.T) {
+	room := roomSeats{OwnerID: "owner", Judges: []string{"judge", "owner"}, Moderators: []string{"mod"}}
+	room.Participants = append(room.Participants, struct {
+		ID string `bson:"id"`
+	}{ID: "owner"})
+
+	cases := []struct {
+		userID, seat string
+		want         bool
+	}{
+		{"mod", "moderator", true},
+		{"judge", "judge", true},
+		{"owner", "moderator", false}, // The owner is debating
+		{"owner", "judge", false},
+		{"friend", "judge", false}, // Not on the judge list
+		{"judge", "moderator", false},
+	}
+	for _, tc := range cases {
+		if got := room.allows(tc.userID, tc.seat); got != tc.want {
+			t.Errorf("Expected %s taking the %s seat to be %v, got %v", tc.userID, tc.seat, tc.want, got)
+		}
+	}
+	if (roomSeats{}).allows("anyone", "judge") {
+		t.Error("Expected a room without a judge list to have no judge seats")
+	}
+}
+
+func TestValidateDecisionSettings(t *testing.T) {
+	zero, tooMuch := 0.0, 1.5
+	if err := ValidateDecisionSettings(models.DecisionModeBlend, &zero, []string{"judge"}); err != nil {
+		t.Errorf("Expected a blend room with a human weight of 0 to be valid, got %v", err)
+	}
+	if err := ValidateDecisionSettings(models.DecisionModeBlend, &tooMuch, []string{"judge"}); err == nil {
+		t.Error("Expected a human weight above 1 to be refused")
+	}
+	if err := ValidateDecisionSettings(models.DecisionModeHuman, nil, nil); err == nil {
+		t.Error("Expected a human room without judges to be refused")
+	}
+	if err := ValidateDecisionSettings(models.DecisionModeAI, nil, nil); err != nil {
+		t.Errorf("Expected an AI room without judges to be valid, got %v", err)
+	}
+}
+
+func TestNormalizeDecisionMode(t *testing.T) {
+	cases := map[string]string{
+		"":       models.DecisionModeAI,
+		"AI":     models.DecisionModeAI,
+		"human":  models.DecisionModeHuman,
+		" Blend": models.DecisionModeBlend,
+		"other":  models.DecisionModeAI,
+	}
+	for input, want := range cases {
+		if got := NormalizeDecisionMode(input); got != want {
+			t.Errorf("NormalizeDecisionMode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxAudienceQuestionLength bounds the text of a spectator question
+const MaxAudienceQuestionLength = 500
+
+// ErrAudienceQuestionNotFound is returned when a question does not exist in a debate
+var ErrAudienceQuestionNotFound = errors.New("question not found")
+
+func audienceQuestionCollection() (*mongo.Collection, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	return db.MongoDatabase.Collection("audience_questions"), nil
+}
+
+// SubmitAudienceQuestion adds a spectator question to a debate's queue as pending, under an
+// ID generated here
+func SubmitAudienceQuestion(ctx context.Context, debateID, text, spectatorHash string) (*models.AudienceQuestion, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("question text is required")
+	}
+	if len(text) > MaxAudienceQuestionLength {
+		return nil, errors.New("question is too long")
+	}
+
+	question := &models.AudienceQuestion{
+		ID:            uuid.New().String(),
+		DebateID:      debateID,
+		Text:          text,
+		SpectatorHash: spectatorHash,
+		Status:        models.AudienceQuestionPending,
+		CreatedAt:     time.Now(),
+	}
+	if _, err := collection.InsertOne(ctx, question); err != nil {
+		return nil, err
+	}
+	return question, nil
+}
+
+// UpvoteAudienceQuestion records one upvote per spectator. It reports false when the
+// spectator already upvoted the question or it can no longer be voted on.
+func UpvoteAudienceQuestion(ctx context.Context, debateID, questionID, spectatorHash string) (*models.AudienceQuestion, bool, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, false, err
+	}
+	filter := bson.M{
+		"_id":      questionID,
+		"debateId": debateID,
+		"status":   bson.M{"$in": []string{models.AudienceQuestionPending, models.AudienceQuestionApproved}},
+		"upvoters": bson.M{"$ne": spectatorHash},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"upvoters": spectatorHash},
+		"$inc":      bson.M{"upvotes": 1},
+	}
+	var question models.AudienceQuestion
+	err = collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&question)
+	if err == mongo.ErrNoDocuments {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &question, true, nil
+}
+
+// ModerateAudienceQuestion approves or hides a question on behalf of a moderator or the host
+func ModerateAudienceQuestion(ctx context.Context, debateID, questionID, status, moderatorID string) (*models.AudienceQuestion, error) {
+	if status != models.AudienceQuestionApproved && status != models.AudienceQuestionHidden {
+		return nil, errors.New("status must be approved or hidden")
+	}
+	return updateAudienceQuestion(ctx, debateID, questionID, bson.M{
+		"status":      status,
+		"moderatedBy": moderatorID,
+		"moderatedAt": time.Now(),
+	})
+}
+
+// MarkAudienceQuestionAnswered marks a question as answered so it is kept with the debate record
+func MarkAudienceQuestionAnswered(ctx context.Context, debateID, questionID string) (*models.AudienceQuestion, error) {
+	return updateAudienceQuestion(ctx, debateID, questionID, bson.M{
+		"status":     models.AudienceQuestionAnswered,
+		"answeredAt": time.Now(),
+	})
+}
+
+func updateAudienceQuestion(ctx context.Context, debateID, questionID string, set bson.M) (*models.AudienceQuestion, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, err
+	}
+	var question models.AudienceQuestion
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": questionID, "debateId": debateID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&question)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrAudienceQuestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// TakeTopAudienceQuestions returns the most upvoted approved questions not yet put to the
+// debaters and stamps them as asked
+func TakeTopAudienceQuestions(ctx context.Context, debateID string, limit int) ([]models.AudienceQuestion, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "upvotes", Value: -1}, {Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := collection.Find(ctx, bson.M{
+		"debateId": debateID,
+		"status":   models.AudienceQuestionApproved,
+		"askedAt":  bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var questions []models.AudienceQuestion
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	if len(questions) == 0 {
+		return questions, nil
+	}
+
+	now := time.Now()
+	ids := make([]string, len(questions))
+	for i := range questions {
+		ids[i] = questions[i].ID
+		questions[i].AskedAt = &now
+	}
+	if _, err := collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"askedAt": now}}); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// GetAudienceQuestions lists a debate's questions, most upvoted first. Moderators see the
+// whole queue; everyone else only sees approved and answered questions.
+func GetAudienceQuestions(ctx context.Context, debateID string, moderator bool) ([]models.AudienceQuestion, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"debateId": debateID}
+	if !moderator {
+		filter["status"] = bson.M{"$in": []string{models.AudienceQuestionApproved, models.AudienceQuestionAnswered}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "upvotes", Value: -1}, {Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	questions := []models.AudienceQuestion{}
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// GetAnsweredAudienceQuestions returns the questions the debaters answered, in answer order
+func GetAnsweredAudienceQuestions(ctx context.Context, debateID string) ([]models.AudienceQuestion, error) {
+	collection, err := audienceQuestionCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "answeredAt", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"debateId": debateID, "status": models.AudienceQuestionAnswered}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var questions []models.AudienceQuestion
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MaxAudioChunkBytes caps the size of a single uploaded audio chunk
+const MaxAudioChunkBytes = 5 << 20
+
+// Transcription runs on a fixed pool of workers, so a burst of chunks queues up instead
+// of starting unbounded work
+const (
+	audioWorkers   = 4
+	audioQueueSize = 64
+)
+
+// Audio retention policies decide when a chunk's audio file is deleted. Audio is never
+// deleted before its chunk has been transcribed.
+const (
+	AudioRetentionTranscribed = "transcribed" // Delete the file once the chunk is transcribed
+	AudioRetentionKeep        = "keep"        // Keep every file; clean-up is left to the operator
+)
+
+// A chunk whose transcription fails is retried in the background, waiting a little longer
+// before each attempt
+const (
+	defaultAudioAttempts = 3
+	audioRetryBaseDelay  = 30 * time.Second
+)
+
+// ErrAudioQueueFull is returned when too many chunks are waiting to be transcribed
+var ErrAudioQueueFull = errors.New("too many audio chunks are waiting to be transcribed, try again shortly")
+
+// audioJob is a chunk waiting for a transcription worker. Retries carry no data; the
+// worker reads the audio back from the stored file.
+type audioJob struct {
+	chunk *models.AudioChunk
+	data  []byte
+	done  func(error)
+}
+
+var (
+	audioQueue     chan audioJob
+	audioQueueOnce sync.Once
+)
+
+// audioDir is where audio chunks are written; set from the transcription config
+var audioDir = filepath.Join("uploads", "audio")
+
+// SetAudioDir changes where audio chunks are stored
+func SetAudioDir(dir string) {
+	if dir != "" {
+		audioDir = dir
+	}
+}
+
+var (
+	audioRetention = AudioRetentionTranscribed
+	audioAttempts  = defaultAudioAttempts
+)
+
+// NormalizeAudioRetention returns a supported retention policy, defaulting to deleting
+// audio once it has been transcribed
+func NormalizeAudioRetention(policy string) string {
+	if strings.EqualFold(strings.TrimSpace(policy), AudioRetentionKeep) {
+		return AudioRetentionKeep
+	}
+	return AudioRetentionTranscribed
+}
+
+// SetAudioRetention sets the retention policy and how many times a chunk is sent to the
+// transcription backend before it is left for RetryRoomAudio
+func SetAudioRetention(policy string, maxAttempts int) {
+	audioRetention = NormalizeAudioRetention(policy)
+	if maxAttempts > 0 {
+		audioAttempts = maxAttempts
+	}
+}
+
+// audioRetryDelay is how long to wait before the next attempt at a chunk that has failed
+// attempts times
+func audioRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 6 {
+		attempts = 6
+	}
+	return audioRetryBaseDelay << (attempts - 1)
+}
+
+// safePathComponent keeps IDs and phase names from escaping the audio directory
+func safePathComponent(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+// audioChunkFilter identifies a chunk by room, speaker, phase and sequence number
+func audioChunkFilter(chunk *models.AudioChunk) bson.M {
+	return bson.M{"roomId": chunk.RoomID, "userId": chunk.UserID, "phase": chunk.Phase, "seq": chunk.Seq}
+}
+
+// transcriptionFilter matches a chunk only while it is still the same upload and no other
+// attempt has recorded a result since attempt started, so a slow attempt at a replaced
+// chunk cannot overwrite the newer upload's transcript
+func transcriptionFilter(chunk *models.AudioChunk, attempt int) bson.M {
+	filter := audioChunkFilter(chunk)
+	filter["uploadId"] = chunk.UploadID
+	filter["attempts"] = attempt
+	return filter
+}
+
+// validateAudioChunk rejects a chunk that cannot be stored
+func validateAudioChunk(chunk *models.AudioChunk, data []byte) error {
+	if chunk.RoomID == "" || chunk.UserID == "" || chunk.Phase == "" {
+		return errors.New("roomId, userId and phase are required")
+	}
+	if len(data) == 0 {
+		return errors.New("audio chunk is empty")
+	}
+	if len(data) > MaxAudioChunkBytes {
+		return fmt.Errorf("audio chunk exceeds %d bytes", MaxAudioChunkBytes)
+	}
+	return nil
+}
+
+// QueueAudioChunk hands a chunk to the transcription workers, which call done with the
+// result of IngestAudioChunk. Invalid chunks are rejected straight away, and
+// ErrAudioQueueFull is returned when the workers are too far behind.
+func QueueAudioChunk(chunk *models.AudioChunk, data []byte, done func(error)) error {
+	if err := validateAudioChunk(chunk, data); err != nil {
+		return err
+	}
+	return enqueueAudioJob(audioJob{chunk: chunk, data: data, done: done})
+}
+
+// enqueueAudioJob hands a job to the transcription workers without waiting
+func enqueueAudioJob(job audioJob) error {
+	audioQueueOnce.Do(startAudioWorkers)
+	select {
+	case audioQueue <- job:
+		return nil
+	default:
+		return ErrAudioQueueFull
+	}
+}
+
+// startAudioWorkers starts the transcription workers
+func startAudioWorkers() {
+	audioQueue = make(chan audioJob, audioQueueSize)
+	for i := 0; i < audioWorkers; i++ {
+		go func() {
+			for job := range audioQueue {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+				var err error
+				if job.data != nil {
+					err = IngestAudioChunk(ctx, job.chunk, job.data)
+				} else {
+					err = retryAudioChunk(ctx, job.chunk)
+				}
+				cancel()
+				if job.done != nil {
+					job.done(err)
+				}
+			}
+		}()
+	}
+}
+
+// IngestAudioChunk stores an audio chunk for a phase and transcribes it with the configured
+// backend. Re-sending a chunk with the same sequence number replaces the earlier one, and
+// any attempt still running on the earlier upload is discarded. The
+// audio file is kept until the chunk has been transcribed, and afterwards unless the
+// retention policy says otherwise; a failed chunk is retried in the background. When no
+// backend is configured the chunk is stored and left pending.
+func IngestAudioChunk(ctx context.Context, chunk *models.AudioChunk, data []byte) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	if err := validateAudioChunk(chunk, data); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(audioDir, safePathComponent(chunk.RoomID), safePathComponent(chunk.Phase))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create audio directory: %w", err)
+	}
+	chunk.UploadID = uuid.New().String()
+	name := fmt.Sprintf("%s-%06d-%s%s", safePathComponent(chunk.UserID), chunk.Seq, chunk.UploadID, audioExtension(chunk.MimeType))
+	chunk.Path = filepath.Join(dir, name)
+	if err := os.WriteFile(chunk.Path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to store audio chunk: %w", err)
+	}
+
+	chunk.Size = int64(len(data))
+	chunk.Status = models.AudioStatusPending
+	chunk.Attempts = 0
+	chunk.ReceivedAt = time.Now()
+	collection := db.MongoDatabase.Collection("debate_audio_chunks")
+	var previous models.AudioChunk
+	opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.Before)
+	err := collection.FindOneAndReplace(ctx, audioChunkFilter(chunk), chunk, opts).Decode(&previous)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to record audio chunk: %w", err)
+	}
+	// The replaced upload's audio is no longer needed
+	if previous.Path != "" && previous.Path != chunk.Path {
+		if removeErr := os.Remove(previous.Path); removeErr != nil && !os.IsNotExist(removeErr) {
+			log.Printf("Failed to delete replaced audio chunk: path=%s err=%v", previous.Path, removeErr)
+		}
+	}
+
+	if GetTranscriber() == nil {
+		return nil
+	}
+	return transcribeAudioChunk(ctx, chunk, data)
+}
+
+// transcribeAudioChunk sends a stored chunk to the transcription backend and records the
+// result. The audio file is only deleted once the chunk is transcribed. A result for an
+// upload that has been replaced, or that another attempt has already recorded, is dropped.
+func transcribeAudioChunk(ctx context.Context, chunk *models.AudioChunk, data []byte) error {
+	t := GetTranscriber()
+	if t == nil {
+		return errors.New("transcription is disabled")
+	}
+
+	result, err := t.Transcribe(ctx, data, chunk.MimeType)
+	filter := transcriptionFilter(chunk, chunk.Attempts)
+	chunk.Attempts++
+	audioPath := chunk.Path
+	if err != nil {
+		chunk.Status = models.AudioStatusFailed
+		chunk.Error = err.Error()
+	} else {
+		// Shift segment times from the chunk onto the phase timeline
+		offset := float64(chunk.OffsetMs) / 1000
+		chunk.Status = models.AudioStatusTranscribed
+		chunk.Error = ""
+		chunk.Text = result.Text
+		chunk.Segments = make([]models.TranscriptSegment, 0, len(result.Segments))
+		for _, s := range result.Segments {
+			chunk.Segments = append(chunk.Segments, models.TranscriptSegment{
+				Start: s.Start + offset,
+				End:   s.End + offset,
+				Text:  s.Text,
+			})
+		}
+		if audioRetention == AudioRetentionTranscribed {
+			chunk.Path = ""
+		}
+	}
+
+	update := bson.M{"$set": bson.M{
+		"status":   chunk.Status,
+		"path":     chunk.Path,
+		"attempts": chunk.Attempts,
+		"error":    chunk.Error,
+		"text":     chunk.Text,
+		"segments": chunk.Segments,
+	}}
+	collection := db.MongoDatabase.Collection("debate_audio_chunks")
+	updated, updateErr := collection.UpdateOne(ctx, filter, update)
+	if updateErr != nil {
+		return fmt.Errorf("failed to record transcription: %w", updateErr)
+	}
+	if updated.MatchedCount == 0 {
+		log.Printf("Dropped a stale transcription: room=%s user=%s phase=%s seq=%d", chunk.RoomID, chunk.UserID, chunk.Phase, chunk.Seq)
+		return nil
+	}
+	if err == nil && chunk.Path == "" && audioPath != "" {
+		if removeErr := os.Remove(audioPath); removeErr != nil && !os.IsNotExist(removeErr) {
+			log.Printf("Failed to delete audio chunk: path=%s err=%v", audioPath, removeErr)
+		}
+	}
+	if err != nil {
+		scheduleAudioRetry(chunk)
+		return fmt.Errorf("transcription failed: %w", err)
+	}
+	return nil
+}
+
+// scheduleAudioRetry queues another attempt at a failed chunk after a back-off. Once the
+// attempts are used up the chunk waits, with its audio, for RetryRoomAudio.
+func scheduleAudioRetry(chunk *models.AudioChunk) {
+	if chunk.Attempts >= audioAttempts {
+		log.Printf("Audio chunk left untranscribed: room=%s user=%s phase=%s seq=%d attempts=%d", chunk.RoomID, chunk.UserID, chunk.Phase, chunk.Seq, chunk.Attempts)
+		return
+	}
+	retry := &models.AudioChunk{RoomID: chunk.RoomID, UserID: chunk.UserID, Phase: chunk.Phase, Seq: chunk.Seq, UploadID: chunk.UploadID}
+	time.AfterFunc(audioRetryDelay(chunk.Attempts), func() {
+		if err := enqueueAudioJob(audioJob{chunk: retry}); err != nil {
+			log.Printf("Failed to queue audio retry: room=%s seq=%d err=%v", retry.RoomID, retry.Seq, err)
+		}
+	})
+}
+
+// retryAudioChunk transcribes a stored chunk again from its audio file. Chunks that have
+// been transcribed or replaced in the meantime are left alone.
+func retryAudioChunk(ctx context.Context, chunk *models.AudioChunk) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	var stored models.AudioChunk
+	if err := db.MongoDatabase.Collection("debate_audio_chunks").FindOne(ctx, audioChunkFilter(chunk)).Decode(&stored); err != nil {
+		return err
+	}
+	if stored.Status == models.AudioStatusTranscribed || stored.Path == "" {
+		return nil
+	}
+	if chunk.UploadID != "" && stored.UploadID != chunk.UploadID {
+		return nil
+	}
+	data, err := os.ReadFile(stored.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read stored audio chunk: %w", err)
+	}
+	return transcribeAudioChunk(ctx, &stored, data)
+}
+
+// RetryRoomAudio queues every chunk of a room that has not been transcribed yet, e.g. once
+// the transcription backend is back after an outage. It returns how many were queued.
+func RetryRoomAudio(ctx context.Context, roomID string) (int, error) {
+	if db.MongoDatabase == nil {
+		return 0, errors.New("database not initialized")
+	}
+	if GetTranscriber() == nil {
+		return 0, errors.New("transcription is disabled")
+	}
+	filter := bson.M{"roomId": roomID, "status": bson.M{"$ne": models.AudioStatusTranscribed}, "path": bson.M{"$ne": ""}}
+	cursor, err := db.MongoDatabase.Collection("debate_audio_chunks").Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	var chunks []models.AudioChunk
+	if err := cursor.All(ctx, &chunks); err != nil {
+		return 0, err
+	}
+	for i := range chunks {
+		if err := enqueueAudioJob(audioJob{chunk: &chunks[i]}); err != nil {
+			return i, err
+		}
+	}
+	return len(chunks), nil
+}
+
+// CanViewAudioTranscript reports whether a user may read a room's audio transcript: its
+// debaters, its owner and the judges and moderators listed for it
+func CanViewAudioTranscript(ctx context.Context, roomID, userID string) bool {
+	if db.MongoDatabase == nil || userID == "" {
+		return false
+	}
+	filter := bson.M{"_id": roomID, "$or": []bson.M{
+		{"participants.id": userID},
+		{"ownerId": userID},
+		{"judges": userID},
+		{"moderators": userID},
+	}}
+	count, err := db.MongoDatabase.Collection("rooms").CountDocuments(ctx, filter)
+	return err == nil && count > 0
+}
+
+// GetPhaseTranscripts assembles the server-side transcript of a room from its transcribed
+// audio chunks, one entry per speaker and phase in the order the phases were recorded
+func GetPhaseTranscripts(ctx context.Context, roomID string) ([]models.PhaseTranscript, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	filter := bson.M{"roomId": roomID, "status": models.AudioStatusTranscribed}
+	opts := options.Find().SetSort(bson.D{{Key: "receivedAt", Value: 1}})
+	cursor, err := db.MongoDatabase.Collection("debate_audio_chunks").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []models.AudioChunk
+	if err := cursor.All(ctx, &chunks); err != nil {
+		return nil, err
+	}
+
+	// Group by phase and speaker, keeping the order in which phases were first heard
+	index := make(map[string]int)
+	grouped := make([][]models.AudioChunk, 0)
+	for _, chunk := range chunks {
+		key := chunk.Phase + "|" + chunk.UserID
+		i, ok := index[key]
+		if !ok {
+			i = len(grouped)
+			index[key] = i
+			grouped = append(grouped, nil)
+		}
+		grouped[i] = append(grouped[i], chunk)
+	}
+
+	transcripts := make([]models.PhaseTranscript, 0, len(grouped))
+	for _, group := range grouped {
+		sort.Slice(group, func(a, b int) bool { return group[a].Seq < group[b].Seq })
+		pt := models.PhaseTranscript{
+			Phase:    group[0].Phase,
+			UserID:   group[0].UserID,
+			Role:     group[0].Role,
+			Segments: make([]models.TranscriptSegment, 0),
+		}
+		texts := make([]string, 0, len(group))
+		for _, chunk := range group {
+			if chunk.Text != "" {
+				texts = append(texts, chunk.Text)
+			}
+			pt.Segments = append(pt.Segments, chunk.Segments...)
+		}
+		pt.Text = strings.Join(texts, " ")
+		transcripts = append(transcripts, pt)
+	}
+	return transcripts, nil
+}
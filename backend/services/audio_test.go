@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestQueueAudioChunkRejectsInvalidChunks(t *testing.T) {
+	chunk := &models.AudioChunk{RoomID: "r1", UserID: "u1", Phase: "openingFor"}
+	called := false
+	if err := QueueAudioChunk(chunk, nil, func(error) { called = true }); err == nil {
+		t.Error("Expected an empty chunk to be rejected")
+	}
+	if err := QueueAudioChunk(chunk, make([]byte, MaxAudioChunkBytes+1), nil); err == nil {
+		t.Error("Expected an oversized chunk to be rejected")
+	}
+	if err := QueueAudioChunk(&models.AudioChunk{RoomID: "r1"}, []byte("audio"), nil); err == nil {
+		t.Error("Expected a chunk without a user and phase to be rejected")
+	}
+	if called {
+		t.Error("Expected rejected chunks not to reach the workers")
+	}
+}
+
+func TestAudioRetention(t *testing.T) {
+	if got := NormalizeAudioRetention(" Keep "); got != AudioRetentionKeep {
+		t.Errorf("Expected keep, got %s", got)
+	}
+	for _, policy := range []string{"", "transcribed", "forever"} {
+		if got := NormalizeAudioRetention(policy); got != AudioRetentionTranscribed {
+			t.Errorf("Expected %q to delete audio once transcribed, got %s", policy, got)
+		}
+	}
+}
+
+func TestAudioRetryDelayBacksOff(t *testing.T) {
+	if got := audioRetryDelay(1); got != audioRetryBaseDelay {
+		t.Errorf("Expected the first retry after %v, got %v", audioRetryBaseDelay, got)
+	}
+	if got := audioRetryDelay(3); got != 4*audioRetryBaseDelay {
+		t.Errorf("Expected the third retry after %v, got %v", 4*audioRetryBaseDelay, got)
+	}
+	if audioRetryDelay(50) != audioRetryDelay(6) {
+		t.Error("Expected the back-off to be capped")
+	}
+}
+
+func TestTranscriptionFilterMatchesOneAttempt(t *testing.T) {
+	chunk := &models.AudioChunk{RoomID: "room1", UserID: "u1", Phase: "openingFor", Seq: 3, UploadID: "upload-2"}
+	filter := transcriptionFilter(chunk, 1)
+	if filter["uploadId"] != "upload-2" || filter["attempts"] != 1 || filter["seq"] != 3 {
+		t.Errorf("Expected the filter to pin the upload and the attempt, got %v", filter)
+	}
+}
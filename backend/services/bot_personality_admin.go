@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"arguehub/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// botPersonalityRefreshInterval is how often each server picks up edits made on another
+const botPersonalityRefreshInterval = time.Minute
+
+var (
+	// ErrBotPersonalityExists is returned when creating a bot whose name is taken
+	ErrBotPersonalityExists = errors.New("a bot with this name already exists")
+	// ErrBotPersonalityNotFound is returned for bots or versions that do not exist
+	ErrBotPersonalityNotFound = errors.New("bot personality not found")
+	// ErrBotPersonalityConflict is returned when two admins save the same bot at once
+	ErrBotPersonalityConflict = errors.New("the bot was edited at the same time; reload and try again")
+	// ErrDefaultPersonality is returned when deleting the default persona
+	ErrDefaultPersonality = errors.New("the default personality cannot be deleted")
+)
+
+// BotPersonalityVersion is one admin edit of a bot's personality. The latest version of
+// a bot is the one in use and takes precedence over its file definition; a deleted
+// version retires the bot. Version numbers start at 1 per bot.
+type BotPersonalityVersion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Version     int                `bson:"version" json:"version"`
+	Personality *BotPersonality    `bson:"personality,omitempty" json:"personality,omitempty"` // Nil when Deleted
+	Deleted     bool               `bson:"deleted,omitempty" json:"deleted,omitempty"`
+	EditedBy    string             `bson:"editedBy" json:"editedBy"` // Admin email
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+func botPersonalityCollection() (*mongo.Collection, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	return db.MongoDatabase.Collection("bot_personality_versions"), nil
+}
+
+// ensureBotPersonalityIndexes makes version numbers unique per bot, so concurrent edits
+// conflict instead of both claiming the same version
+func ensureBotPersonalityIndexes(ctx context.Context) error {
+	collection, err := botPersonalityCollection()
+	if err != nil {
+		return err
+	}
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "version", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// RefreshBotPersonalities rebuilds the personalities in use from the file definitions and
+// the latest admin version of each bot
+func RefreshBotPersonalities() error {
+	currentBotPersonalities()
+	botPersonalitiesMu.RLock()
+	files := filePersonalities
+	botPersonalitiesMu.RUnlock()
+
+	merged := make(map[string]BotPersonality, len(files))
+	for name, b := range files {
+		merged[name] = b
+	}
+	var loadErr error
+	if db.MongoDatabase != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		latest, err := latestBotPersonalityVersions(ctx)
+		if err != nil {
+			// Keep the edits already in use rather than reverting to the files
+			return err
+		}
+		for _, v := range latest {
+			if v.Deleted || v.Personality == nil {
+				if v.Name != defaultPersonalityName {
+					delete(merged, v.Name)
+				}
+				continue
+			}
+			if err := v.Personality.Validate(); err != nil {
+				log.Printf("Skipping stored bot personality: name=%s version=%d err=%v", v.Name, v.Version, err)
+				loadErr = err
+				continue
+			}
+			merged[v.Name] = *v.Personality
+		}
+	}
+
+	botPersonalitiesMu.Lock()
+	botPersonalities = merged
+	botPersonalitiesMu.Unlock()
+	return loadErr
+}
+
+// WatchBotPersonalities periodically refreshes the personalities so edits made through
+// another server take effect everywhere
+func WatchBotPersonalities() {
+	ticker := time.NewTicker(botPersonalityRefreshInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := RefreshBotPersonalities(); err != nil {
+			log.Printf("Failed to refresh bot personalities: %v", err)
+		}
+	}
+}
+
+// latestBotPersonalityVersions returns the newest version of every edited bot
+func latestBotPersonalityVersions(ctx context.Context) ([]BotPersonalityVersion, error) {
+	collection, err := botPersonalityCollection()
+	if err != nil {
+		return nil, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}, {Key: "version", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$name", "doc": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$doc"}}},
+	}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	var versions []BotPersonalityVersion
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// botPersonalityExists reports whether a bot has a personality in use, as opposed to
+// falling back to the default persona
+func botPersonalityExists(name string) bool {
+	_, ok := LookupBotPersonality(name)
+	return ok
+}
+
+// BotPersonalityHistory returns a bot's stored versions, newest first
+func BotPersonalityHistory(ctx context.Context, name string) ([]BotPersonalityVersion, error) {
+	collection, err := botPersonalityCollection()
+	if err != nil {
+		return nil, err
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"name": name}, opts)
+	if err != nil {
+		return nil, err
+	}
+	versions := []BotPersonalityVersion{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// SaveBotPersonality validates b and stores it as the bot's next version. With create the
+// name must be free; otherwise the bot must already exist.
+func SaveBotPersonality(ctx context.Context, b BotPersonality, editor string, create bool) (*BotPersonalityVersion, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	exists := botPersonalityExists(b.Name)
+	if create && exists {
+		return nil, ErrBotPersonalityExists
+	}
+	if !create && !exists {
+		return nil, ErrBotPersonalityNotFound
+	}
+	return appendBotPersonalityVersion(ctx, BotPersonalityVersion{Name: b.Name, Personality: &b, EditedBy: editor})
+}
+
+// DeleteBotPersonality retires a bot by storing a deleted version; its earlier versions
+// stay available to restore
+func DeleteBotPersonality(ctx context.Context, name, editor string) (*BotPersonalityVersion, error) {
+	if name == defaultPersonalityName {
+		return nil, ErrDefaultPersonality
+	}
+	if !botPersonalityExists(name) {
+		return nil, ErrBotPersonalityNotFound
+	}
+	return appendBotPersonalityVersion(ctx, BotPersonalityVersion{Name: name, Deleted: true, EditedBy: editor})
+}
+
+// RestoreBotPersonalityVersion stores an earlier version of a bot as its newest, which
+// also brings back a deleted bot
+func RestoreBotPersonalityVersion(ctx context.Context, name string, version int, editor string) (*BotPersonalityVersion, error) {
+	collection, err := botPersonalityCollection()
+	if err != nil {
+		return nil, err
+	}
+	var old BotPersonalityVersion
+	err = collection.FindOne(ctx, bson.M{"name": name, "version": version}).Decode(&old)
+	if err == mongo.ErrNoDocuments || (err == nil && old.Personality == nil) {
+		return nil, ErrBotPersonalityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return appendBotPersonalityVersion(ctx, BotPersonalityVersion{Name: name, Personality: old.Personality, EditedBy: editor})
+}
+
+// appendBotPersonalityVersion stores v as the bot's next version and puts it in use on
+// this server; others pick it up on their next refresh
+func appendBotPersonalityVersion(ctx context.Context, v BotPersonalityVersion) (*BotPersonalityVersion, error) {
+	collection, err := botPersonalityCollection()
+	if err != nil {
+		return nil, err
+	}
+	var latest BotPersonalityVersion
+	opts := options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}})
+	err = collection.FindOne(ctx, bson.M{"name": v.Name}, opts).Decode(&latest)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	v.ID = primitive.NewObjectID()
+	v.Version = latest.Version + 1
+	v.CreatedAt = time.Now()
+	if _, err := collection.InsertOne(ctx, v); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrBotPersonalityConflict
+		}
+		return nil, err
+	}
+
+	currentBotPersonalities()
+	botPersonalitiesMu.Lock()
+	updated := make(map[string]BotPersonality, len(botPersonalities)+1)
+	for name, b := range botPersonalities {
+		updated[name] = b
+	}
+	if v.Deleted {
+		delete(updated, v.Name)
+	} else {
+		updated[v.Name] = *v.Personality
+	}
+	botPersonalities = updated
+	botPersonalitiesMu.Unlock()
+	return &v, nil
+}
@@ -15,13 +15,13 @@ import (
 func InitCoachService() {
 }
 
-// GenerateWeakStatement generates a weak opening statement for a given topic and stance using Gemini
-func GenerateWeakStatement(topic, stance string) (models.WeakStatement, error) {
-	if geminiClient == nil {
-		return models.WeakStatement{}, errors.New("Gemini client not initialized")
+// GenerateWeakStatement generates a weak opening statement for a given topic and stance with the configured model
+func GenerateWeakStatement(ctx context.Context, topic, stance string) (models.WeakStatement, error) {
+	if !llmAvailable(LLMTaskWeakStatement) {
+		return models.WeakStatement{}, ErrLLMUnavailable
 	}
 
-	// Construct the prompt for Gemini to generate a full-fledged weak statement
+	// Construct the prompt to generate a full-fledged weak statement
 	prompt := fmt.Sprintf(
 		`Act as a debate coach and generate a weak opening statement for the topic "%s" taking the stance "%s". 
 The statement should:
@@ -40,8 +40,7 @@ Provide ONLY the JSON output without additional text or markdown formatting.`,
 		topic, stance,
 	)
 
-	ctx := context.Background()
-	response, err := generateDefaultModelText(ctx, prompt)
+	response, err := generateTaskText(ctx, LLMTaskWeakStatement, prompt)
 	if err != nil {
 		return models.WeakStatement{}, fmt.Errorf("failed to generate weak statement: %v", err)
 	}
@@ -71,9 +70,9 @@ Provide ONLY the JSON output without additional text or markdown formatting.`,
 }
 
 // EvaluateArgument evaluates the user's improved argument against the weak statement
-func EvaluateArgument(topic, stance, weakStatementText, userResponse string) (models.Evaluation, error) {
-	if geminiClient == nil {
-		return models.Evaluation{}, errors.New("Gemini client not initialized")
+func EvaluateArgument(ctx context.Context, topic, stance, weakStatementText, userResponse string) (models.Evaluation, error) {
+	if !llmAvailable(LLMTaskCoach) {
+		return models.Evaluation{}, ErrLLMUnavailable
 	}
 
 	prompt := fmt.Sprintf(
@@ -97,8 +96,7 @@ Provide ONLY the JSON output without additional text or markdown formatting.`,
 		topic, stance, weakStatementText, userResponse,
 	)
 
-	ctx := context.Background()
-	response, err := generateDefaultModelText(ctx, prompt)
+	response, err := generateTaskText(ctx, LLMTaskCoach, prompt)
 	if err != nil {
 		return models.Evaluation{}, fmt.Errorf("failed to evaluate argument: %v", err)
 	}
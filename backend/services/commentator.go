@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// commentaryTimeout bounds one call to the commentary model
+	commentaryTimeout = 30 * time.Second
+	// maxCommentaryTranscript keeps the most recent part of long debates in the prompt
+	maxCommentaryTranscript = 12000
+)
+
+// CommentarySpeech is what one side said during a phase
+type CommentarySpeech struct {
+	Phase string
+	Side  string // "for" or "against"
+	Text  string
+}
+
+// CommentaryEnabled reports whether a debate has the AI commentator switched on.
+// Debates that cannot be looked up have it off.
+func CommentaryEnabled(ctx context.Context, roomID string) bool {
+	if db.MongoDatabase == nil {
+		return false
+	}
+	var room struct {
+		Commentary bool `bson:"commentary"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"commentary": 1})
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}, opts).Decode(&room); err != nil {
+		return false
+	}
+	return room.Commentary
+}
+
+// SetCommentaryEnabled switches a debate's AI commentator on or off
+func SetCommentaryEnabled(ctx context.Context, roomID string, enabled bool) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	update := bson.M{"$set": bson.M{"commentary": enabled}}
+	_, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
+
+// CommentOnPhase has the AI commentator analyse a phase that just finished and publishes
+// the result to the debate's spectators. It does nothing when the debate has commentary
+// off, nothing was said in the phase or the debate's commentary rate is used up.
+func CommentOnPhase(roomID, topic, phase string, speech []CommentarySpeech) {
+	ctx, cancel := context.WithTimeout(context.Background(), commentaryTimeout)
+	defer cancel()
+
+	if !CommentaryEnabled(ctx, roomID) {
+		return
+	}
+	spoken := false
+	for _, s := range speech {
+		if s.Phase == phase && strings.TrimSpace(s.Text) != "" {
+			spoken = true
+			break
+		}
+	}
+	if !spoken {
+		return
+	}
+	if result, err := debate.NewRateLimiter().Allow(debate.ActionCommentary, roomID); err != nil || !result.Allowed {
+		return
+	}
+
+	text, err := generateTaskText(ctx, LLMTaskCommentary, buildCommentaryPrompt(topic, phase, speech))
+	if err != nil {
+		log.Printf("Failed to generate commentary: debate=%s phase=%s err=%v", roomID, phase, err)
+		return
+	}
+	commentary, err := parseCommentary(text)
+	if err != nil {
+		log.Printf("Failed to parse commentary: debate=%s phase=%s err=%v", roomID, phase, err)
+		return
+	}
+	commentary.Phase = phase
+	commentary.Timestamp = time.Now().Unix()
+	publishSpectatorEvent(roomID, "commentary", commentary)
+}
+
+// buildCommentaryPrompt asks for a neutral analysis of the phase, with the rest of the
+// debate so far as context for who is ahead
+func buildCommentaryPrompt(topic, phase string, speech []CommentarySpeech) string {
+	var transcript strings.Builder
+	for _, s := range speech {
+		text := strings.TrimSpace(s.Text)
+		if text == "" {
+			continue
+		}
+		role := "For"
+		if s.Side == "against" {
+			role = "Against"
+		}
+		fmt.Fprintf(&transcript, "%s (%s): %s\n", role, s.Phase, text)
+	}
+	lines := transcript.String()
+	if len(lines) > maxCommentaryTranscript {
+		start := len(lines) - maxCommentaryTranscript
+		for start < len(lines) && !utf8.RuneStart(lines[start]) {
+			start++
+		}
+		lines = "..." + lines[start:]
+	}
+	if topic == "" {
+		topic = "(not given)"
+	}
+
+	return fmt.Sprintf(`You are a neutral colour commentator for a live debate, writing for the audience.
+The debaters never see your commentary.
+
+Topic: %s
+
+Transcript so far:
+%s
+The phase "%s" has just finished. Analyse it in the context of the debate so far.
+Do not take sides on the topic itself; judge only how well each side is arguing.
+
+Respond in STRICT JSON with exactly these fields:
+{
+  "summary": "two or three sentences on what happened in this phase",
+  "keyClaims": [{"side": "for" or "against", "claim": "a key claim made in this phase"}],
+  "clashes": ["a point where the sides directly disagree"],
+  "leading": "for", "against" or "even"
+}`, topic, lines, phase)
+}
+
+// parseCommentary reads the model's JSON answer, tolerating code fences and normalising
+// the sides it names
+func parseCommentary(text string) (*debate.CommentaryPayload, error) {
+	var commentary debate.CommentaryPayload
+	if err := json.Unmarshal([]byte(cleanModelOutput(text)), &commentary); err != nil {
+		return nil, err
+	}
+	commentary.Summary = strings.TrimSpace(commentary.Summary)
+	if commentary.Summary == "" {
+		return nil, errors.New("commentary has no summary")
+	}
+
+	claims := make([]debate.CommentaryClaim, 0, len(commentary.KeyClaims))
+	for _, claim := range commentary.KeyClaims {
+		side := strings.ToLower(strings.TrimSpace(claim.Side))
+		if (side == "for" || side == "against") && strings.TrimSpace(claim.Claim) != "" {
+			claims = append(claims, debate.CommentaryClaim{Side: side, Claim: strings.TrimSpace(claim.Claim)})
+		}
+	}
+	commentary.KeyClaims = claims
+	if commentary.Clashes == nil {
+		commentary.Clashes = []string{}
+	}
+
+	switch leading := strings.ToLower(strings.TrimSpace(commentary.Leading)); leading {
+	case "for", "against":
+		commentary.Leading = leading
+	default:
+		commentary.Leading = "even"
+	}
+	return &commentary, nil
+}
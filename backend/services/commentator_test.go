@@ -0,0 +1,49 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommentary(t *testing.T) {
+	text := "```json\n" + `{
+  "summary": " For opened on cost. ",
+  "keyClaims": [{"side": "For", "claim": "It saves money"}, {"side": "judge", "claim": "ignored"}],
+  "leading": "FOR"
+}` + "\n```"
+
+	commentary, err := parseCommentary(text)
+	if err != nil {
+		t.Fatalf("parseCommentary: %v", err)
+	}
+	if commentary.Summary != "For opened on cost." {
+		t.Errorf("summary = %q", commentary.Summary)
+	}
+	if len(commentary.KeyClaims) != 1 || commentary.KeyClaims[0].Side != "for" {
+		t.Errorf("key claims = %+v, want only the for claim", commentary.KeyClaims)
+	}
+	if commentary.Leading != "for" || commentary.Clashes == nil {
+		t.Errorf("leading = %q clashes = %v", commentary.Leading, commentary.Clashes)
+	}
+
+	if commentary, _ := parseCommentary(`{"summary": "Even so far", "leading": "nobody"}`); commentary.Leading != "even" {
+		t.Errorf("an unknown leader should read as even, got %q", commentary.Leading)
+	}
+	if _, err := parseCommentary(`{"leading": "for"}`); err == nil {
+		t.Error("commentary without a summary should be rejected")
+	}
+}
+
+func TestBuildCommentaryPromptKeepsRecentSpeech(t *testing.T) {
+	speech := []CommentarySpeech{
+		{Phase: "openingFor", Side: "for", Text: strings.Repeat("old ", maxCommentaryTranscript)},
+		{Phase: "openingAgainst", Side: "against", Text: "the latest rebuttal"},
+	}
+	prompt := buildCommentaryPrompt("", "openingAgainst", speech)
+	if !strings.Contains(prompt, "Against (openingAgainst): the latest rebuttal") {
+		t.Error("the prompt should keep the most recent speech")
+	}
+	if len(prompt) > maxCommentaryTranscript+2000 {
+		t.Errorf("the prompt should be truncated, got %d bytes", len(prompt))
+	}
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CustomBotPrefix starts the bot name of debates against a custom bot, followed by the
+// bot's ID, e.g. "custom:65f0c2...". Built-in bots are referred to by their name.
+const CustomBotPrefix = "custom:"
+
+// Custom bot limits
+const (
+	MaxCustomBotsPerUser       = 20
+	MaxCustomBotNameLength     = 40
+	MaxCustomBotTextLength     = 500 // Tone, strategy and the other persona fields
+	MaxCustomBotCatchphrases   = 8
+	MaxReferenceMaterialLength = 8000
+	// customBotReportsToHide hides a published bot pending review once this many users report it
+	customBotReportsToHide = 5
+)
+
+// customBotRatings gives custom bots the rating of a typical built-in bot of their level
+var customBotRatings = map[string]int{
+	"Easy":    1200,
+	"Medium":  1500,
+	"Hard":    1700,
+	"Expert":  2000,
+	"Legends": 2300,
+}
+
+var (
+	// ErrCustomBotNotFound is returned for bots that do not exist or that the user cannot see
+	ErrCustomBotNotFound = errors.New("custom bot not found")
+	// ErrCustomBotLimit is returned when a user already has MaxCustomBotsPerUser bots
+	ErrCustomBotLimit = fmt.Errorf("you can create at most %d bots", MaxCustomBotsPerUser)
+)
+
+// CustomBotRejection is returned when a bot definition breaks the content rules
+type CustomBotRejection struct {
+	Field  string
+	Reason string
+}
+
+func (e *CustomBotRejection) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Reason)
+}
+
+// CustomBotInput is a user's definition of a custom bot
+type CustomBotInput struct {
+	Name              string   `json:"name"`
+	Level             string   `json:"level"`
+	Description       string   `json:"description"`
+	Tone              string   `json:"tone"`
+	RhetoricalStyle   string   `json:"rhetoricalStyle"`
+	DebateStrategy    string   `json:"debateStrategy"`
+	InteractionStyle  string   `json:"interactionStyle"`
+	Catchphrases      []string `json:"catchphrases"`
+	ReferenceMaterial string   `json:"referenceMaterial"`
+	Visibility        string   `json:"visibility"` // "private" (the default) or "public"
+}
+
+func customBotCollection() (*mongo.Collection, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	return db.MongoDatabase.Collection("custom_bots"), nil
+}
+
+// normalize trims the input and fills in defaults
+func (in *CustomBotInput) normalize() {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	in.Tone = strings.TrimSpace(in.Tone)
+	in.RhetoricalStyle = strings.TrimSpace(in.RhetoricalStyle)
+	in.DebateStrategy = strings.TrimSpace(in.DebateStrategy)
+	in.InteractionStyle = strings.TrimSpace(in.InteractionStyle)
+	in.ReferenceMaterial = strings.TrimSpace(in.ReferenceMaterial)
+	catchphrases := make([]string, 0, len(in.Catchphrases))
+	for _, phrase := range in.Catchphrases {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			catchphrases = append(catchphrases, phrase)
+		}
+	}
+	in.Catchphrases = catchphrases
+	if in.Visibility == "" {
+		in.Visibility = models.CustomBotPrivate
+	}
+}
+
+// moderate checks a definition against the required fields, the length limits and the
+// content rules: no blocked words anywhere, and no links outside the reference material,
+// since names, descriptions and catchphrases are shown to other users
+func (in CustomBotInput) moderate() error {
+	if n := utf8.RuneCountInString(in.Name); n < 2 || n > MaxCustomBotNameLength {
+		return &CustomBotRejection{"name", fmt.Sprintf("must be 2 to %d characters", MaxCustomBotNameLength)}
+	}
+	if strings.HasPrefix(in.Name, CustomBotPrefix) {
+		return &CustomBotRejection{"name", "cannot start with " + CustomBotPrefix}
+	}
+	if !isValidBotLevel(in.Level) {
+		return &CustomBotRejection{"level", "must be one of " + strings.Join(validBotLevels, ", ")}
+	}
+	if in.Visibility != models.CustomBotPrivate && in.Visibility != models.CustomBotPublic {
+		return &CustomBotRejection{"visibility", "must be private or public"}
+	}
+	if in.Tone == "" {
+		return &CustomBotRejection{"tone", "is required"}
+	}
+	if in.DebateStrategy == "" {
+		return &CustomBotRejection{"debateStrategy", "is required"}
+	}
+	if len(in.Catchphrases) == 0 || len(in.Catchphrases) > MaxCustomBotCatchphrases {
+		return &CustomBotRejection{"catchphrases", fmt.Sprintf("must have 1 to %d phrases", MaxCustomBotCatchphrases)}
+	}
+	if utf8.RuneCountInString(in.ReferenceMaterial) > MaxReferenceMaterialLength {
+		return &CustomBotRejection{"referenceMaterial", fmt.Sprintf("must be at most %d characters", MaxReferenceMaterialLength)}
+	}
+
+	fields := []struct{ name, value string }{
+		{"name", in.Name},
+		{"description", in.Description},
+		{"tone", in.Tone},
+		{"rhetoricalStyle", in.RhetoricalStyle},
+		{"debateStrategy", in.DebateStrategy},
+		{"interactionStyle", in.InteractionStyle},
+	}
+	for _, phrase := range in.Catchphrases {
+		fields = append(fields, struct{ name, value string }{"catchphrases", phrase})
+	}
+	blockedWords := debate.GetChatConfig().BlockedWords
+	for _, field := range fields {
+		if utf8.RuneCountInString(field.value) > MaxCustomBotTextLength {
+			return &CustomBotRejection{field.name, fmt.Sprintf("must be at most %d characters", MaxCustomBotTextLength)}
+		}
+		if debate.ContainsLink(field.value) {
+			return &CustomBotRejection{field.name, "cannot contain links"}
+		}
+		if debate.FilterChatText(field.value, blockedWords) != field.value {
+			return &CustomBotRejection{field.name, "contains blocked words"}
+		}
+	}
+	if debate.FilterChatText(in.ReferenceMaterial, blockedWords) != in.ReferenceMaterial {
+		return &CustomBotRejection{"referenceMaterial", "contains blocked words"}
+	}
+	return nil
+}
+
+// CreateCustomBot checks a user's bot definition and saves it
+func CreateCustomBot(ctx context.Context, ownerID primitive.ObjectID, input CustomBotInput) (*models.CustomBot, error) {
+	collection, err := customBotCollection()
+	if err != nil {
+		return nil, err
+	}
+	input.normalize()
+	if err := input.moderate(); err != nil {
+		return nil, err
+	}
+	count, err := collection.CountDocuments(ctx, bson.M{"ownerId": ownerID})
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxCustomBotsPerUser {
+		return nil, ErrCustomBotLimit
+	}
+
+	now := time.Now()
+	bot := &models.CustomBot{
+		ID:                primitive.NewObjectID(),
+		OwnerID:           ownerID,
+		Name:              input.Name,
+		Level:             input.Level,
+		Description:       input.Description,
+		Tone:              input.Tone,
+		RhetoricalStyle:   input.RhetoricalStyle,
+		DebateStrategy:    input.DebateStrategy,
+		InteractionStyle:  input.InteractionStyle,
+		Catchphrases:      input.Catchphrases,
+		ReferenceMaterial: input.ReferenceMaterial,
+		Visibility:        input.Visibility,
+		Status:            models.CustomBotActive,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if _, err := collection.InsertOne(ctx, bot); err != nil {
+		return nil, err
+	}
+	return bot, nil
+}
+
+// UpdateCustomBot replaces the definition of one of the user's bots. Its stats and
+// moderation state are kept.
+func UpdateCustomBot(ctx context.Context, ownerID, botID primitive.ObjectID, input CustomBotInput) (*models.CustomBot, error) {
+	collection, err := customBotCollection()
+	if err != nil {
+		return nil, err
+	}
+	input.normalize()
+	if err := input.moderate(); err != nil {
+		return nil, err
+	}
+	update := bson.M{"$set": bson.M{
+		"name":              input.Name,
+		"level":             input.Level,
+		"description":       input.Description,
+		"tone":              input.Tone,
+		"rhetoricalStyle":   input.RhetoricalStyle,
+		"debateStrategy":    input.DebateStrategy,
+		"interactionStyle":  input.InteractionStyle,
+		"catchphrases":      input.Catchphrases,
+		"referenceMaterial": input.ReferenceMaterial,
+		"visibility":        input.Visibility,
+		"updatedAt":         time.Now(),
+	}}
+	var bot models.CustomBot
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": botID, "ownerId": ownerID}, update, opts).Decode(&bot)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCustomBotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+// DeleteCustomBot deletes one of the user's bots. Past debates against it keep their history.
+func DeleteCustomBot(ctx context.Context, ownerID, botID primitive.ObjectID) error {
+	collection, err := customBotCollection()
+	if err != nil {
+		return err
+	}
+	result, err := collection.DeleteOne(ctx, bson.M{"_id": botID, "ownerId": ownerID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrCustomBotNotFound
+	}
+	return nil
+}
+
+// ListCustomBots returns the bots a user created, newest first
+func ListCustomBots(ctx context.Context, ownerID primitive.ObjectID) ([]models.CustomBot, error) {
+	return findCustomBots(ctx, bson.M{"ownerId": ownerID}, options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+}
+
+// ListPublishedCustomBots returns a page of the community's published bots, most debated first
+func ListPublishedCustomBots(ctx context.Context, page, limit int) ([]models.CustomBot, error) {
+	filter := bson.M{"visibility": models.CustomBotPublic, "status": models.CustomBotActive}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "stats.debates", Value: -1}, {Key: "createdAt", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"referenceMaterial": 0})
+	return findCustomBots(ctx, filter, opts)
+}
+
+// ListCustomBotsForReview returns bots in a moderation state, most reported first
+func ListCustomBotsForReview(ctx context.Context, status string, page, limit int) ([]models.CustomBot, error) {
+	filter := bson.M{"status": status}
+	if status == models.CustomBotActive {
+		filter["reportCount"] = bson.M{"$gt": 0}
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "reportCount", Value: -1}, {Key: "updatedAt", Value: -1}}).
+		SetSkip(int64((page - 1) * limit)).
+		SetLimit(int64(limit))
+	return findCustomBots(ctx, filter, opts)
+}
+
+func findCustomBots(ctx context.Context, filter bson.M, opts *options.FindOptions) ([]models.CustomBot, error) {
+	collection, err := customBotCollection()
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	bots := []models.CustomBot{}
+	if err := cursor.All(ctx, &bots); err != nil {
+		return nil, err
+	}
+	return bots, nil
+}
+
+// GetCustomBot returns a bot the viewer may debate: one of their own, or an active
+// published bot. Other bots are reported as not found.
+func GetCustomBot(ctx context.Context, botID primitive.ObjectID, viewerID string) (*models.CustomBot, error) {
+	collection, err := customBotCollection()
+	if err != nil {
+		return nil, err
+	}
+	var bot models.CustomBot
+	err = collection.FindOne(ctx, bson.M{"_id": botID}).Decode(&bot)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCustomBotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bot.OwnerID.Hex() == viewerID {
+		return &bot, nil
+	}
+	if bot.Visibility != models.CustomBotPublic || bot.Status != models.CustomBotActive {
+		return nil, ErrCustomBotNotFound
+	}
+	return &bot, nil
+}
+
+// ReportCustomBot records a user's report of a published bot, once per user. Enough
+// reports hide the bot until a moderator reviews it.
+func ReportCustomBot(ctx context.Context, botID, reporterID primitive.ObjectID) error {
+	collection, err := customBotCollection()
+	if err != nil {
+		return err
+	}
+	filter := bson.M{
+		"_id":        botID,
+		"visibility": models.CustomBotPublic,
+		"status":     models.CustomBotActive,
+		"ownerId":    bson.M{"$ne": reporterID},
+		"reporters":  bson.M{"$ne": reporterID},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"reporters": reporterID},
+		"$inc":      bson.M{"reportCount": 1},
+	}
+	var bot models.CustomBot
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&bot)
+	if err == mongo.ErrNoDocuments {
+		// Already reported by this user, or not a bot they can report
+		if _, getErr := GetCustomBot(ctx, botID, reporterID.Hex()); getErr != nil {
+			return getErr
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if bot.ReportCount >= customBotReportsToHide {
+		_, err = collection.UpdateOne(ctx, bson.M{"_id": botID, "status": models.CustomBotActive}, bson.M{"$set": bson.M{
+			"status":         models.CustomBotHidden,
+			"moderationNote": "Hidden automatically after user reports; pending review",
+		}})
+	}
+	return err
+}
+
+// SetCustomBotStatus hides or restores a bot on a moderator's behalf. Restoring clears
+// its reports.
+func SetCustomBotStatus(ctx context.Context, botID primitive.ObjectID, status, note string) (*models.CustomBot, error) {
+	collection, err := customBotCollection()
+	if err != nil {
+		return nil, err
+	}
+	if status != models.CustomBotActive && status != models.CustomBotHidden {
+		return nil, &CustomBotRejection{"status", "must be active or hidden"}
+	}
+	set := bson.M{"status": status, "moderationNote": note}
+	if status == models.CustomBotActive {
+		set["reportCount"] = 0
+		set["reporters"] = []primitive.ObjectID{}
+	}
+	var bot models.CustomBot
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = collection.FindOneAndUpdate(ctx, bson.M{"_id": botID}, bson.M{"$set": set}, opts).Decode(&bot)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCustomBotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &bot, nil
+}
+
+// CustomBotPersonality turns a custom bot into a personality for constructPrompt
+func CustomBotPersonality(bot *models.CustomBot) BotPersonality {
+	return BotPersonality{
+		Name:              bot.Name,
+		Rating:            customBotRatings[bot.Level],
+		Level:             bot.Level,
+		Tone:              bot.Tone,
+		RhetoricalStyle:   bot.RhetoricalStyle,
+		DebateStrategy:    bot.DebateStrategy,
+		InteractionStyle:  bot.InteractionStyle,
+		Catchphrases:      bot.Catchphrases,
+		Description:       bot.Description,
+		ReferenceMaterial: bot.ReferenceMaterial,
+	}
+}
+
+// customBotID returns the custom bot a debate's bot name refers to, if any
+func customBotID(botName string) (primitive.ObjectID, bool) {
+	hex, ok := strings.CutPrefix(botName, CustomBotPrefix)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(hex)
+	return id, err == nil
+}
+
+// IsCustomBotName reports whether a debate's bot name refers to a custom bot
+func IsCustomBotName(botName string) bool {
+	return strings.HasPrefix(botName, CustomBotPrefix)
+}
+
+// GetCustomBotByName returns the custom bot a debate's bot name refers to, if the viewer
+// may debate it
+func GetCustomBotByName(ctx context.Context, botName, viewerID string) (*models.CustomBot, error) {
+	id, ok := customBotID(botName)
+	if !ok {
+		return nil, ErrCustomBotNotFound
+	}
+	return GetCustomBot(ctx, id, viewerID)
+}
+
+// resolveBotPersonality returns the personality for a debate's bot name. Custom bots must
+// be visible to the user attached to ctx.
+func resolveBotPersonality(ctx context.Context, botName string) (BotPersonality, error) {
+	if !IsCustomBotName(botName) {
+		return GetBotPersonality(botName), nil
+	}
+	bot, err := GetCustomBotByName(ctx, botName, llmUserFromContext(ctx))
+	if err != nil {
+		return BotPersonality{}, err
+	}
+	return CustomBotPersonality(bot), nil
+}
+
+// RecordCustomBotDebate counts a debate started against a custom bot. Other bot names
+// are ignored.
+func RecordCustomBotDebate(botName string) {
+	incrementCustomBotStat(botName, "stats.debates")
+}
+
+// RecordCustomBotResult counts a judged debate against a custom bot by its outcome for
+// the user: "win", "loss" or "draw"
+func RecordCustomBotResult(botName, result string) {
+	switch result {
+	case "win":
+		incrementCustomBotStat(botName, "stats.userWins")
+	case "loss":
+		incrementCustomBotStat(botName, "stats.botWins")
+	case "draw":
+		incrementCustomBotStat(botName, "stats.draws")
+	}
+}
+
+// incrementCustomBotStat adds one to a custom bot's stat. Failures are only logged.
+func incrementCustomBotStat(botName, field string) {
+	id, ok := customBotID(botName)
+	if !ok {
+		return
+	}
+	collection, err := customBotCollection()
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	update := bson.M{"$inc": bson.M{field: 1}, "$set": bson.M{"stats.lastUsedAt": time.Now()}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		log.Printf("Failed to update custom bot stats: bot=%s err=%v", id.Hex(), err)
+	}
+}
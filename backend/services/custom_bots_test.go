@@ -0,0 +1,94 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func validCustomBotInput() CustomBotInput {
+	return CustomBotInput{
+		Name:           " Pundit Pat ",
+		Level:          "Medium",
+		Tone:           "Chirpy and breathless",
+		DebateStrategy: "Repeat the headline until it sticks",
+		Catchphrases:   []string{"Breaking news!", "  "},
+	}
+}
+
+func TestCustomBotModeration(t *testing.T) {
+	debate.SetChatConfig(debate.ChatConfig{BlockedWords: []string{"darn"}})
+	defer debate.SetChatConfig(debate.DefaultChatConfig())
+
+	input := validCustomBotInput()
+	input.normalize()
+	if err := input.moderate(); err != nil {
+		t.Fatalf("Expected a valid definition, got %v", err)
+	}
+	if input.Name != "Pundit Pat" || len(input.Catchphrases) != 1 || input.Visibility != models.CustomBotPrivate {
+		t.Errorf("Expected the input to be trimmed and private by default, got %+v", input)
+	}
+
+	cases := []struct {
+		field  string
+		modify func(*CustomBotInput)
+	}{
+		{"level", func(in *CustomBotInput) { in.Level = "Godlike" }},
+		{"tone", func(in *CustomBotInput) { in.Tone = "" }},
+		{"catchphrases", func(in *CustomBotInput) { in.Catchphrases = []string{"Visit www.example.com"} }},
+		{"description", func(in *CustomBotInput) { in.Description = "A darn good debater" }},
+		{"name", func(in *CustomBotInput) { in.Name = "custom:abc" }},
+		{"referenceMaterial", func(in *CustomBotInput) { in.ReferenceMaterial = strings.Repeat("a", MaxReferenceMaterialLength+1) }},
+	}
+	for _, tc := range cases {
+		in := validCustomBotInput()
+		tc.modify(&in)
+		in.normalize()
+		var rejection *CustomBotRejection
+		if err := in.moderate(); !errors.As(err, &rejection) || rejection.Field != tc.field {
+			t.Errorf("Expected %s to be rejected, got %v", tc.field, err)
+		}
+	}
+
+	// Links are fine in reference material, which is never shown to other users
+	in := validCustomBotInput()
+	in.ReferenceMaterial = "See https://example.com/report for the figures."
+	in.normalize()
+	if err := in.moderate(); err != nil {
+		t.Errorf("Expected links in reference material to be allowed, got %v", err)
+	}
+}
+
+func TestCustomBotPersonalityFeedsPrompt(t *testing.T) {
+	bot := &models.CustomBot{
+		ID:                primitive.NewObjectID(),
+		Name:              "Pundit Pat",
+		Level:             "Hard",
+		Tone:              "Chirpy",
+		DebateStrategy:    "Repeat the headline",
+		Catchphrases:      []string{"Breaking news!"},
+		ReferenceMaterial: "Tea exports rose 4% last year.",
+	}
+	personality := CustomBotPersonality(bot)
+	if personality.Rating != 1700 {
+		t.Errorf("Expected the Hard level rating, got %d", personality.Rating)
+	}
+	prompt := constructPrompt(personality, "Tea is better than coffee", nil, "for", "", 100)
+	for _, want := range []string{"You are Pundit Pat, a Hard-level debate bot", "Breaking news!", "Tea exports rose 4% last year."} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected the prompt to contain %q", want)
+		}
+	}
+
+	if id, ok := customBotID(CustomBotPrefix + bot.ID.Hex()); !ok || id != bot.ID {
+		t.Errorf("Expected the bot name to resolve to %s, got %s", bot.ID.Hex(), id.Hex())
+	}
+	if _, ok := customBotID("Yoda"); ok {
+		t.Error("Expected a built-in bot name not to be a custom bot")
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// endedDebateTTL is how long an ended debate's live state stays in Redis after it has
+// been archived, so connected spectators still see the results
+const endedDebateTTL = time.Hour
+
+// ArchiveEndedDebate takes an ended debate out of the live directory, stores its polls and
+// event stream in MongoDB and lets its live state expire. It is safe to call more than once.
+func ArchiveEndedDebate(ctx context.Context, debateID string) {
+	RemoveLiveDebate(debateID)
+	// Audio that never made it through transcription gets another attempt; its files stay
+	// on disk until it does
+	if GetTranscriber() != nil {
+		if _, err := RetryRoomAudio(ctx, debateID); err != nil {
+			log.Printf("Failed to retry debate audio: debate=%s err=%v", debateID, err)
+		}
+	}
+	if err := ArchiveDebatePolls(ctx, debateID); err != nil {
+		log.Printf("Failed to archive polls: debate=%s err=%v", debateID, err)
+	}
+	// Archived after the polls so the poll_closed events they publish are kept too
+	if err := ArchiveDebateEvents(ctx, debateID); err != nil {
+		log.Printf("Failed to archive events: debate=%s err=%v", debateID, err)
+		return
+	}
+	if err := debate.ExpireDebateState(debateID, endedDebateTTL); err != nil {
+		log.Printf("Failed to expire debate state: debate=%s err=%v", debateID, err)
+	}
+}
+
+// ArchiveDebateEvents copies a debate's event stream into MongoDB
+func ArchiveDebateEvents(ctx context.Context, debateID string) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	events, err := debate.ReadAllEvents(debateID)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(events))
+	for _, event := range events {
+		var payload interface{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			payload = string(event.Payload)
+		}
+		archived := models.ArchivedEvent{
+			ID:         debateID + ":" + event.ID,
+			DebateID:   debateID,
+			EventID:    event.ID,
+			Type:       event.Type,
+			Payload:    payload,
+			Timestamp:  event.Timestamp,
+			ArchivedAt: now,
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": archived.ID}).
+			SetReplacement(archived).
+			SetUpsert(true))
+	}
+	_, err = db.MongoDatabase.Collection("debate_events").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// GetArchivedEvents returns an ended debate's spectator events in stream order
+func GetArchivedEvents(ctx context.Context, debateID string) ([]models.ArchivedEvent, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	cursor, err := db.MongoDatabase.Collection("debate_events").Find(ctx, bson.M{"debateId": debateID})
+	if err != nil {
+		return nil, err
+	}
+	events := []models.ArchivedEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return debate.CompareEventIDs(events[i].EventID, events[j].EventID) < 0
+	})
+	return events, nil
+}
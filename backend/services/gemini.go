@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"errors"
 	"strings"
 
 	"google.golang.org/genai"
@@ -10,42 +9,83 @@ import (
 
 const defaultGeminiModel = "gemini-2.5-flash"
 
-func initGemini(apiKey string) (*genai.Client, error) {
-	config := &genai.ClientConfig{}
-	if apiKey != "" {
-		config.APIKey = apiKey
+// GeminiProvider generates text with Google's Gemini models
+type GeminiProvider struct {
+	client *genai.Client
+	Model  string
+}
+
+// NewGeminiProvider creates a Gemini client for the API key. An empty model uses
+// defaultGeminiModel.
+func NewGeminiProvider(apiKey, model string) (*GeminiProvider, error) {
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{APIKey: apiKey})
+	if err != nil {
+		return nil, err
 	}
-	return genai.NewClient(context.Background(), config)
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &GeminiProvider{client: client, Model: model}, nil
 }
 
-func generateModelText(ctx context.Context, modelName, prompt string) (string, error) {
-	if geminiClient == nil {
-		return "", errors.New("gemini client not initialized")
+// Name identifies the Gemini provider
+func (g *GeminiProvider) Name() string { return "gemini" }
+
+// model returns the requested model, or the provider's default
+func (g *GeminiProvider) model(req LLMRequest) string {
+	if req.Model != "" {
+		return req.Model
 	}
-	model := geminiClient.GenerativeModel(modelName)
-	model.SafetySettings = []*genai.SafetySetting{
-		{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockNone},
-		{Category: genai.HarmCategoryHateSpeech, Threshold: genai.HarmBlockNone},
-		{Category: genai.HarmCategorySexuallyExplicit, Threshold: genai.HarmBlockNone},
-		{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockNone},
+	return g.Model
+}
+
+// geminiConfig turns safety blocking off, since debate bots argue deliberately
+// provocative positions
+func geminiConfig() *genai.GenerateContentConfig {
+	return &genai.GenerateContentConfig{
+		SafetySettings: []*genai.SafetySetting{
+			{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockThresholdBlockNone},
+			{Category: genai.HarmCategoryHateSpeech, Threshold: genai.HarmBlockThresholdBlockNone},
+			{Category: genai.HarmCategorySexuallyExplicit, Threshold: genai.HarmBlockThresholdBlockNone},
+			{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockThresholdBlockNone},
+		},
 	}
+}
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+// Generate sends the prompt to Gemini
+func (g *GeminiProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	resp, err := g.client.Models.GenerateContent(ctx, g.model(req), genai.Text(req.Prompt), geminiConfig())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return cleanModelOutput(resp.Text()), nil
+	result := &LLMResponse{Text: resp.Text()}
+	addGeminiUsage(result, resp.UsageMetadata)
+	return result, nil
 }
 
-func cleanModelOutput(text string) string {
-	cleaned := strings.TrimSpace(text)
-	cleaned = strings.TrimPrefix(cleaned, "```json")
-	cleaned = strings.TrimPrefix(cleaned, "```JSON")
-	cleaned = strings.TrimPrefix(cleaned, "```")
-	cleaned = strings.TrimSuffix(cleaned, "```")
-	return strings.TrimSpace(cleaned)
+// GenerateStream sends the prompt to Gemini and passes on each part of the reply as it arrives
+func (g *GeminiProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(string)) (*LLMResponse, error) {
+	var text strings.Builder
+	result := &LLMResponse{}
+	for resp, err := range g.client.Models.GenerateContentStream(ctx, g.model(req), genai.Text(req.Prompt), geminiConfig()) {
+		if err != nil {
+			return nil, err
+		}
+		if chunk := resp.Text(); chunk != "" {
+			text.WriteString(chunk)
+			onChunk(chunk)
+		}
+		// Usage is cumulative, so the last report covers the whole reply
+		addGeminiUsage(result, resp.UsageMetadata)
+	}
+	result.Text = text.String()
+	return result, nil
 }
 
-func generateDefaultModelText(ctx context.Context, prompt string) (string, error) {
-	return generateModelText(ctx, defaultGeminiModel, prompt)
+// addGeminiUsage copies Gemini's token counts into a response
+func addGeminiUsage(result *LLMResponse, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage != nil {
+		result.InputTokens = int(usage.PromptTokenCount)
+		result.OutputTokens = int(usage.CandidatesTokenCount)
+	}
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Orders of the live debates directory
+const (
+	LiveSortPopular = "popular" // Most spectators first
+	LiveSortRating  = "rating"  // Highest average debater rating first
+)
+
+// maxLiveDebates bounds one page of the live directory
+const maxLiveDebates = 100
+
+// isPublicRoom reports whether a room is listed publicly. Rooms that cannot be looked up
+// are treated as private.
+func isPublicRoom(ctx context.Context, roomID string) bool {
+	if db.MongoDatabase == nil {
+		return false
+	}
+	var room struct {
+		Type string `bson:"type"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"type": 1})
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}, opts).Decode(&room); err != nil {
+		return false
+	}
+	return room.Type == "public"
+}
+
+// UpdateLiveDebate lists a public debate in the live directory with its current phase
+// and debaters. Private and invite-only debates are never listed.
+func UpdateLiveDebate(ctx context.Context, roomID, phase string, participants []debate.LiveParticipant) {
+	if !isPublicRoom(ctx, roomID) {
+		return
+	}
+	if err := debate.NewLiveRegistry().MarkLive(roomID, phase, participants); err != nil {
+		log.Printf("Failed to update live debate: debate=%s err=%v", roomID, err)
+	}
+}
+
+// SetLiveDebateTopic records the topic shown for a debate in the live directory
+func SetLiveDebateTopic(roomID, topic string) {
+	if err := debate.NewLiveRegistry().SetTopic(roomID, topic); err != nil {
+		log.Printf("Failed to set live debate topic: debate=%s err=%v", roomID, err)
+	}
+}
+
+// RemoveLiveDebate takes an ended debate out of the live directory
+func RemoveLiveDebate(roomID string) {
+	if err := debate.NewLiveRegistry().Remove(roomID); err != nil {
+		log.Printf("Failed to remove live debate: debate=%s err=%v", roomID, err)
+	}
+}
+
+// ListLiveDebates returns up to limit live debates in the given order
+func ListLiveDebates(sortBy string, limit int) ([]debate.LiveDebate, error) {
+	debates, err := debate.NewLiveRegistry().List()
+	if err != nil {
+		return nil, err
+	}
+	sortLiveDebates(debates, sortBy)
+	if limit <= 0 || limit > maxLiveDebates {
+		limit = maxLiveDebates
+	}
+	if len(debates) > limit {
+		debates = debates[:limit]
+	}
+	return debates, nil
+}
+
+// sortLiveDebates orders debates by popularity (the default) or rating, newest first on ties
+func sortLiveDebates(debates []debate.LiveDebate, sortBy string) {
+	sort.SliceStable(debates, func(i, j int) bool {
+		a, b := debates[i], debates[j]
+		if sortBy == LiveSortRating {
+			if ra, rb := averageRating(a), averageRating(b); ra != rb {
+				return ra > rb
+			}
+		} else if a.Spectators != b.Spectators {
+			return a.Spectators > b.Spectators
+		}
+		return a.StartedAt > b.StartedAt
+	})
+}
+
+// averageRating is the mean rating of a debate's debaters
+func averageRating(live debate.LiveDebate) float64 {
+	if len(live.Participants) == 0 {
+		return 0
+	}
+	total := 0
+	for _, p := range live.Participants {
+		total += p.Rating
+	}
+	return float64(total) / float64(len(live.Participants))
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/internal/debate"
+)
+
+func TestSortLiveDebates(t *testing.T) {
+	debates := []debate.LiveDebate{
+		{DebateID: "quiet", Spectators: 2, StartedAt: 1, Participants: []debate.LiveParticipant{{Rating: 2100}, {Rating: 1900}}},
+		{DebateID: "busy", Spectators: 40, StartedAt: 2, Participants: []debate.LiveParticipant{{Rating: 1500}, {Rating: 1500}}},
+		{DebateID: "new", Spectators: 2, StartedAt: 3},
+	}
+
+	sortLiveDebates(debates, LiveSortPopular)
+	if debates[0].DebateID != "busy" || debates[1].DebateID != "new" {
+		t.Errorf("popular order = %s, %s, %s", debates[0].DebateID, debates[1].DebateID, debates[2].DebateID)
+	}
+
+	sortLiveDebates(debates, LiveSortRating)
+	if debates[0].DebateID != "quiet" || debates[2].DebateID != "new" {
+		t.Errorf("rating order = %s, %s, %s", debates[0].DebateID, debates[1].DebateID, debates[2].DebateID)
+	}
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/config"
+)
+
+// Tasks that call a language model. Each can be routed to its own provider in the config.
+const (
+	LLMTaskBot           = "bot"            // Debate bot replies
+	LLMTaskJudge         = "judge"          // Judging bot and human debates
+	LLMTaskCoach         = "coach"          // Feedback on strengthened arguments
+	LLMTaskWeakStatement = "weak_statement" // Weak statements for the coach to strengthen
+	LLMTaskProsCons      = "pros_cons"      // Topic generation and pros/cons evaluation
+	LLMTaskCommentary    = "commentary"     // Live commentary for spectators
+)
+
+// ErrLLMUnavailable is returned when no model provider is configured for a task
+var ErrLLMUnavailable = errors.New("AI model not configured")
+
+// defaultLLMTimeout bounds one request to an HTTP model provider
+const defaultLLMTimeout = 60 * time.Second
+
+// LLMRequest is one text generation call
+type LLMRequest struct {
+	Model  string // Provider-specific model name; empty uses the provider's default
+	Prompt string
+}
+
+// LLMResponse is a model's reply and the tokens the call used. Providers that do not
+// report usage leave the counts at zero.
+type LLMResponse struct {
+	Text         string
+	InputTokens  int
+	OutputTokens int
+}
+
+// LLMProvider generates text with a language model
+type LLMProvider interface {
+	// Name identifies the provider in logs, e.g. "gemini"
+	Name() string
+	// Generate returns the model's reply to the request's prompt
+	Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error)
+}
+
+// LLMStreamer is implemented by providers that can deliver a reply while it is generated
+type LLMStreamer interface {
+	// GenerateStream calls onChunk with each piece of the reply as it arrives and returns
+	// the whole reply once the model is done. Cancelling ctx stops generation.
+	GenerateStream(ctx context.Context, req LLMRequest, onChunk func(string)) (*LLMResponse, error)
+}
+
+var (
+	// llmProviders maps a task to its provider; the "" entry is the default for unlisted tasks
+	llmProviders   = map[string]LLMProvider{}
+	llmProvidersMu sync.RWMutex
+)
+
+// InitLLMProviders builds the model providers named in the config, assigns them to tasks
+// and loads the model routes and daily budgets. Tasks whose provider cannot be built fall
+// back to the default provider, and AI features are disabled when that cannot be built
+// either.
+func InitLLMProviders(cfg *config.Config) {
+	defaultName := strings.ToLower(cfg.LLM.Provider)
+	if defaultName == "" {
+		defaultName = "gemini"
+	}
+
+	built := map[string]LLMProvider{}
+	provider := func(name, task string) LLMProvider {
+		if name == "fake" {
+			// Fake providers are built per task so each gets its own canned reply
+			return newConfiguredFakeProvider(cfg, task)
+		}
+		if p, ok := built[name]; ok {
+			return p
+		}
+		p, err := newLLMProvider(cfg, name)
+		if err != nil {
+			log.Printf("⚠️  Warning: %v", err)
+		}
+		built[name] = p
+		return p
+	}
+
+	SetLLMProvider("", provider(defaultName, ""))
+	for task, name := range cfg.LLM.Tasks {
+		if name = strings.ToLower(name); name != "" {
+			SetLLMProvider(task, provider(name, task))
+		}
+	}
+
+	routes := make([]LLMRoute, 0, len(cfg.LLM.Routes))
+	for _, r := range cfg.LLM.Routes {
+		routes = append(routes, LLMRoute{Provider: strings.ToLower(r.Provider), Task: r.Task, Level: r.Level, Model: r.Model})
+	}
+	b := cfg.LLM.Budgets
+	SetLLMRouting(routes, LLMBudget{
+		UserDailyTokens:   b.UserDailyTokens,
+		GlobalDailyTokens: b.GlobalDailyTokens,
+		DegradedModels:    b.DegradedModels,
+	})
+
+	if GetLLMProvider("") == nil {
+		log.Printf("⚠️  Server will continue but debate bot and AI features will not work")
+		return
+	}
+	log.Printf("✅ AI model provider %s initialized", defaultName)
+}
+
+// newLLMProvider builds a provider by name from its config section
+func newLLMProvider(cfg *config.Config, name string) (LLMProvider, error) {
+	timeout := time.Duration(cfg.LLM.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = defaultLLMTimeout
+	}
+	switch name {
+	case "gemini":
+		if cfg.Gemini.ApiKey == "" || cfg.Gemini.ApiKey == "<YOUR_GEMINI_API_KEY>" {
+			return nil, errors.New("Gemini API key not configured")
+		}
+		p, err := NewGeminiProvider(cfg.Gemini.ApiKey, cfg.Gemini.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Gemini client: %w", err)
+		}
+		return p, nil
+	case "openai":
+		if cfg.Openai.GptApiKey == "" {
+			return nil, errors.New("OpenAI API key not configured")
+		}
+		return NewOpenAIProvider(cfg.Openai.GptApiKey, cfg.Openai.Model, timeout), nil
+	case "local":
+		if cfg.LLM.LocalURL == "" {
+			return nil, errors.New("local model provider selected but llm.localUrl is not set")
+		}
+		return NewLocalLLMProvider(cfg.LLM.LocalURL, cfg.LLM.LocalModel, cfg.LLM.LocalAPIKey, timeout), nil
+	default:
+		return nil, errors.New("unknown model provider " + name)
+	}
+}
+
+// newConfiguredFakeProvider returns a fake that answers a task with its canned reply from
+// the config
+func newConfiguredFakeProvider(cfg *config.Config, task string) LLMProvider {
+	fake := &FakeLLMProvider{}
+	if reply, ok := cfg.LLM.FakeResponses[task]; ok {
+		fake.Responses = []string{reply}
+	}
+	return fake
+}
+
+// SetLLMProvider routes a task to a provider, e.g. to a FakeLLMProvider in tests. An empty
+// task sets the default provider; a nil provider makes the task use the default.
+func SetLLMProvider(task string, p LLMProvider) {
+	llmProvidersMu.Lock()
+	defer llmProvidersMu.Unlock()
+	if p == nil {
+		delete(llmProviders, task)
+		return
+	}
+	llmProviders[task] = p
+}
+
+// GetLLMProvider returns the provider for a task, falling back to the default provider,
+// or nil when AI features are disabled
+func GetLLMProvider(task string) LLMProvider {
+	llmProvidersMu.RLock()
+	defer llmProvidersMu.RUnlock()
+	if p, ok := llmProviders[task]; ok {
+		return p
+	}
+	return llmProviders[""]
+}
+
+// llmAvailable reports whether a task has a model provider
+func llmAvailable(task string) bool {
+	return GetLLMProvider(task) != nil
+}
+
+// cleanModelOutput strips the markdown code fences models often wrap JSON in
+func cleanModelOutput(text string) string {
+	cleaned := strings.TrimSpace(text)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```JSON")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+	return strings.TrimSpace(cleaned)
+}
+
+// FakeLLMProvider returns scripted replies without calling a model, for tests and offline
+// development. Replies are returned in order and the last one repeats once the script
+// runs out. Every prompt and requested model is recorded so tests can assert on what
+// was sent.
+type FakeLLMProvider struct {
+	Responses  []string
+	Err        error
+	ChunkDelay time.Duration // Pause between streamed words, to test cancellation
+	mu         sync.Mutex
+	Prompts    []string
+	Models     []string
+}
+
+// Name identifies the fake provider
+func (f *FakeLLMProvider) Name() string { return "fake" }
+
+// GenerateStream returns the next scripted reply one word at a time, stopping early if
+// ctx is cancelled
+func (f *FakeLLMProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(string)) (*LLMResponse, error) {
+	resp, err := f.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	words := strings.SplitAfter(resp.Text, " ")
+	for _, word := range words {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		onChunk(word)
+		if f.ChunkDelay > 0 {
+			time.Sleep(f.ChunkDelay)
+		}
+	}
+	return resp, nil
+}
+
+// Generate returns the next scripted reply, with token counts estimated from its length
+func (f *FakeLLMProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	call := len(f.Prompts)
+	f.Prompts = append(f.Prompts, req.Prompt)
+	f.Models = append(f.Models, req.Model)
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	var text string
+	switch {
+	case len(f.Responses) == 0:
+		text = "This is a scripted reply from the fake model provider."
+	case call < len(f.Responses):
+		text = f.Responses[call]
+	default:
+		text = f.Responses[len(f.Responses)-1]
+	}
+	return &LLMResponse{Text: text, InputTokens: estimateTokens(req.Prompt), OutputTokens: estimateTokens(text)}, nil
+}
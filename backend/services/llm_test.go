@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalLLMProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Expected no Authorization header without an API key")
+		}
+		var req chatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if req.Model != "llama3.1" || len(req.Messages) != 1 || req.Messages[0].Content != "Argue for tea" {
+			t.Errorf("Unexpected request: %+v", req)
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Tea is calming."}}],"usage":{"prompt_tokens":12,"completion_tokens":4}}`))
+	}))
+	defer server.Close()
+
+	provider := NewLocalLLMProvider(server.URL+"/v1/", "llama3.1", "", 5*time.Second)
+	resp, err := provider.Generate(context.Background(), LLMRequest{Prompt: "Argue for tea"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if resp.Text != "Tea is calming." || provider.Name() != "local" {
+		t.Errorf("Unexpected reply %q from %s", resp.Text, provider.Name())
+	}
+	if resp.InputTokens != 12 || resp.OutputTokens != 4 {
+		t.Errorf("Expected the reported usage, got %d in and %d out", resp.InputTokens, resp.OutputTokens)
+	}
+}
+
+func TestOpenAIProviderReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("key", "", 5*time.Second)
+	provider.BaseURL = server.URL
+	if _, err := provider.Generate(context.Background(), LLMRequest{Prompt: "hi"}); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Expected a 429 error, got %v", err)
+	}
+}
+
+func TestGenerateTaskTextRoutesByTask(t *testing.T) {
+	defaultFake := &FakeLLMProvider{Responses: []string{"default"}}
+	judgeFake := &FakeLLMProvider{Responses: []string{"```json\n{\"winner\":\"for\"}\n```", "second"}}
+	SetLLMProvider("", defaultFake)
+	SetLLMProvider(LLMTaskJudge, judgeFake)
+	defer SetLLMProvider("", nil)
+	defer SetLLMProvider(LLMTaskJudge, nil)
+
+	ctx := context.Background()
+	if text, _ := generateTaskText(ctx, LLMTaskJudge, "judge this"); text != `{"winner":"for"}` {
+		t.Errorf("Expected the judge's fenced reply to be cleaned, got %q", text)
+	}
+	if text, _ := generateTaskText(ctx, LLMTaskJudge, "again"); text != "second" {
+		t.Errorf("Expected the second scripted reply, got %q", text)
+	}
+	if text, _ := generateTaskText(ctx, LLMTaskJudge, "and again"); text != "second" {
+		t.Errorf("Expected the last reply to repeat, got %q", text)
+	}
+	if text, _ := generateTaskText(ctx, LLMTaskCoach, "coach me"); text != "default" {
+		t.Errorf("Expected an unrouted task to use the default provider, got %q", text)
+	}
+	if len(judgeFake.Prompts) != 3 || judgeFake.Prompts[0] != "judge this" {
+		t.Errorf("Unexpected judge prompts: %v", judgeFake.Prompts)
+	}
+
+	SetLLMProvider("", nil)
+	if _, err := generateTaskText(ctx, LLMTaskCoach, "coach me"); err != ErrLLMUnavailable {
+		t.Errorf("Expected ErrLLMUnavailable without providers, got %v", err)
+	}
+}
+
+func TestResolveLLMModelPrefersBotLevel(t *testing.T) {
+	SetLLMRouting([]LLMRoute{
+		{Provider: "gemini", Task: LLMTaskBot, Model: "standard"},
+		{Provider: "gemini", Task: LLMTaskBot, Level: "Easy", Model: "cheap"},
+		{Provider: "gemini", Task: LLMTaskJudge, Model: "strong"},
+		{Provider: "openai", Task: LLMTaskJudge, Model: "gpt-strong"},
+	}, LLMBudget{})
+	defer SetLLMRouting(nil, LLMBudget{})
+
+	cases := []struct{ provider, task, level, want string }{
+		{"gemini", LLMTaskBot, "easy", "cheap"},
+		{"gemini", LLMTaskBot, "Legends", "standard"},
+		{"gemini", LLMTaskJudge, "", "strong"},
+		{"gemini", LLMTaskCoach, "", ""},
+		{"openai", LLMTaskJudge, "", "gpt-strong"},
+		// Another provider's model names are never sent to the local server
+		{"local", LLMTaskBot, "Easy", ""},
+	}
+	for _, tc := range cases {
+		if got := resolveLLMModel(tc.provider, tc.task, tc.level); got != tc.want {
+			t.Errorf("resolveLLMModel(%q, %q, %q) = %q, want %q", tc.provider, tc.task, tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestGenerateRoutedTextDegradesWhenBudgetSpent(t *testing.T) {
+	fake := &FakeLLMProvider{Responses: []string{strings.Repeat("word ", 40)}}
+	SetLLMProvider("", fake)
+	defer SetLLMProvider("", nil)
+	SetLLMRouting([]LLMRoute{{Provider: "fake", Task: LLMTaskBot, Model: "standard"}}, LLMBudget{
+		UserDailyTokens: 50,
+		DegradedModels:  map[string]string{"fake": "lite", "gemini": "gemini-lite"},
+	})
+	defer SetLLMRouting(nil, LLMBudget{})
+
+	ctx := WithLLMUser(context.Background(), "budget-test-user")
+	if _, err := generateRoutedText(ctx, LLMTaskBot, "Easy", "first"); err != nil {
+		t.Fatalf("generateRoutedText: %v", err)
+	}
+	if _, err := generateRoutedText(ctx, LLMTaskBot, "Easy", "second"); err != nil {
+		t.Fatalf("generateRoutedText: %v", err)
+	}
+	if len(fake.Models) != 2 || fake.Models[0] != "standard" || fake.Models[1] != "lite" {
+		t.Errorf("Expected the degraded model once the budget was spent, got %v", fake.Models)
+	}
+
+	SetLLMRouting(nil, LLMBudget{UserDailyTokens: 50, DegradedModels: map[string]string{"gemini": "gemini-lite"}})
+	if _, err := generateRoutedText(ctx, LLMTaskBot, "Easy", "third"); err != ErrLLMBudgetExceeded {
+		t.Errorf("Expected ErrLLMBudgetExceeded without a degraded model for the provider, got %v", err)
+	}
+	if usage, _ := GetAIUsage("budget-test-user"); !usage.BudgetExhausted || usage.TokensUsed < 50 {
+		t.Errorf("Unexpected usage summary: %+v", usage)
+	}
+}
+
+func TestOpenAIProviderStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream || req.StreamOptions == nil || !req.StreamOptions.IncludeUsage {
+			t.Errorf("Expected a streamed request with usage, got %+v", req)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Tea \"}}]}\n\n"))
+		w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"wins.\"}}]}\n\n"))
+		w.Write([]byte("data: {\"choices\":[],\"usage\":{\"prompt_tokens\":9,\"completion_tokens\":2}}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	provider := NewLocalLLMProvider(server.URL, "llama3.1", "", 5*time.Second)
+	var chunks []string
+	resp, err := provider.GenerateStream(context.Background(), LLMRequest{Prompt: "Argue"}, func(chunk string) {
+		chunks = append(chunks, chunk)
+	})
+	if err != nil {
+		t.Fatalf("GenerateStream failed: %v", err)
+	}
+	if len(chunks) != 2 || resp.Text != "Tea wins." || resp.OutputTokens != 2 {
+		t.Errorf("Unexpected stream: chunks %q, reply %+v", chunks, resp)
+	}
+}
+
+func TestStreamRoutedTextStopsWhenCancelled(t *testing.T) {
+	fake := &FakeLLMProvider{Responses: []string{"one two three four five six"}, ChunkDelay: 5 * time.Millisecond}
+	SetLLMProvider("", fake)
+	defer SetLLMProvider("", nil)
+
+	var streamed strings.Builder
+	text, err := streamRoutedText(context.Background(), LLMTaskBot, "", "go", func(chunk string) {
+		streamed.WriteString(chunk)
+	})
+	if err != nil || streamed.String() != text {
+		t.Fatalf("Expected the chunks to add up to the reply, got %q and %q (%v)", streamed.String(), text, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks := 0
+	_, err = streamRoutedText(ctx, LLMTaskBot, "", "go", func(string) {
+		if chunks++; chunks == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled || chunks != 2 {
+		t.Errorf("Expected generation to stop after 2 chunks, got %d chunks and %v", chunks, err)
+	}
+}
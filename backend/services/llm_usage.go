@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+)
+
+// ErrLLMBudgetExceeded is returned when a daily token budget is spent and no degraded
+// model is configured to take over
+var ErrLLMBudgetExceeded = errors.New("daily AI budget exhausted")
+
+// LLMRoute picks the model for a task, or for one bot level of the bot task. A model name
+// only means something to its own provider, so the route is ignored while the task is
+// served by another one.
+type LLMRoute struct {
+	Provider string // Provider name such as "gemini" or "openai"
+	Task     string
+	Level    string // Bot level such as "Easy"; empty matches every level
+	Model    string
+}
+
+// LLMBudget caps the model tokens spent per UTC day. Zero limits are unlimited.
+type LLMBudget struct {
+	UserDailyTokens   int64
+	GlobalDailyTokens int64
+	// DegradedModels is the model that answers once a budget is spent, by provider name,
+	// usually the provider's cheapest model. For a provider without one, calls fail with
+	// ErrLLMBudgetExceeded and each feature falls back to its canned behaviour.
+	DegradedModels map[string]string
+}
+
+var (
+	llmRoutes   []LLMRoute
+	llmBudget   LLMBudget
+	llmPolicyMu sync.RWMutex
+)
+
+// SetLLMRouting replaces the model routes and daily budgets
+func SetLLMRouting(routes []LLMRoute, budget LLMBudget) {
+	llmPolicyMu.Lock()
+	defer llmPolicyMu.Unlock()
+	llmRoutes = append([]LLMRoute(nil), routes...)
+	llmBudget = budget
+}
+
+// resolveLLMModel returns the provider's model routed for a task and bot level, preferring
+// a route for the level over one for the whole task. Empty means the provider's default model.
+func resolveLLMModel(provider, task, level string) string {
+	llmPolicyMu.RLock()
+	defer llmPolicyMu.RUnlock()
+	model := ""
+	for _, route := range llmRoutes {
+		if route.Task != task || !strings.EqualFold(route.Provider, provider) {
+			continue
+		}
+		if route.Level == "" && model == "" {
+			model = route.Model
+		} else if route.Level != "" && strings.EqualFold(route.Level, level) {
+			return route.Model
+		}
+	}
+	return model
+}
+
+// currentLLMBudget returns the daily budgets
+func currentLLMBudget() LLMBudget {
+	llmPolicyMu.RLock()
+	defer llmPolicyMu.RUnlock()
+	return llmBudget
+}
+
+type llmUserKey struct{}
+
+// WithLLMUser attributes the model calls made with ctx to a user, for metering and the
+// per-user budget
+func WithLLMUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, llmUserKey{}, userID)
+}
+
+// llmUserFromContext returns the user model calls are attributed to, or "" for none
+func llmUserFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(llmUserKey{}).(string)
+	return userID
+}
+
+// exhausted reports whether the user's or the global budget for today is spent
+func (b LLMBudget) exhausted(userID string, userTokens, globalTokens int64) bool {
+	if b.GlobalDailyTokens > 0 && globalTokens >= b.GlobalDailyTokens {
+		return true
+	}
+	return userID != "" && b.UserDailyTokens > 0 && userTokens >= b.UserDailyTokens
+}
+
+// estimateTokens approximates a token count for providers that do not report usage,
+// at about four characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// generateTaskText asks the task's provider for a reply to prompt, with code fences removed
+func generateTaskText(ctx context.Context, task, prompt string) (string, error) {
+	return generateRoutedText(ctx, task, "", prompt)
+}
+
+// generateRoutedText asks the task's provider for a reply with the model routed for the
+// task and bot level. Once the user's or the global daily budget is spent the degraded
+// model answers instead. Every call's tokens are metered against the budgets and recorded.
+func generateRoutedText(ctx context.Context, task, level, prompt string) (string, error) {
+	return streamRoutedText(ctx, task, level, prompt, nil)
+}
+
+// streamRoutedText is generateRoutedText that also passes each piece of the reply to
+// onChunk as it arrives. Providers that cannot stream deliver the reply as one piece.
+// A nil onChunk generates without streaming.
+func streamRoutedText(ctx context.Context, task, level, prompt string, onChunk func(string)) (string, error) {
+	p := GetLLMProvider(task)
+	if p == nil {
+		return "", ErrLLMUnavailable
+	}
+
+	userID := llmUserFromContext(ctx)
+	model := resolveLLMModel(p.Name(), task, level)
+	degraded := false
+	meter := debate.NewTokenMeter()
+	budget := currentLLMBudget()
+	if budget.UserDailyTokens > 0 || budget.GlobalDailyTokens > 0 {
+		userTokens, globalTokens, err := meter.Usage(userID)
+		if err != nil {
+			// Budgets fail open like the rate limiter, so an outage does not stop AI features
+			log.Printf("Failed to read AI token usage: user=%s err=%v", userID, err)
+		} else if budget.exhausted(userID, userTokens, globalTokens) {
+			degradedModel := budget.DegradedModels[p.Name()]
+			if degradedModel == "" {
+				return "", ErrLLMBudgetExceeded
+			}
+			model = degradedModel
+			degraded = true
+		}
+	}
+
+	req := LLMRequest{Model: model, Prompt: prompt}
+	var resp *LLMResponse
+	var err error
+	if streamer, ok := p.(LLMStreamer); ok && onChunk != nil {
+		resp, err = streamer.GenerateStream(ctx, req, onChunk)
+	} else {
+		resp, err = p.Generate(ctx, req)
+		if err == nil && onChunk != nil {
+			onChunk(resp.Text)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	usage := models.AIUsage{
+		UserID:       userID,
+		Task:         task,
+		Level:        level,
+		Provider:     p.Name(),
+		Model:        model,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+		Degraded:     degraded,
+		CreatedAt:    time.Now(),
+	}
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		usage.InputTokens = estimateTokens(prompt)
+		usage.OutputTokens = estimateTokens(resp.Text)
+	}
+	if err := meter.Add(userID, int64(usage.InputTokens+usage.OutputTokens)); err != nil {
+		log.Printf("Failed to meter AI tokens: user=%s err=%v", userID, err)
+	}
+	recordLLMUsage(usage)
+	return cleanModelOutput(resp.Text), nil
+}
+
+// recordLLMUsage stores one call's usage for reporting. Failures are only logged.
+func recordLLMUsage(usage models.AIUsage) {
+	if db.MongoDatabase == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := db.MongoDatabase.Collection("ai_usage").InsertOne(ctx, usage); err != nil {
+		log.Printf("Failed to record AI usage: task=%s err=%v", usage.Task, err)
+	}
+}
+
+// GetAIUsage returns how many model tokens a user has spent today against their budget
+func GetAIUsage(userID string) (*models.AIUsageSummary, error) {
+	userTokens, globalTokens, err := debate.NewTokenMeter().Usage(userID)
+	if err != nil {
+		return nil, err
+	}
+	budget := currentLLMBudget()
+	return &models.AIUsageSummary{
+		Date:            time.Now().UTC().Format("2006-01-02"),
+		TokensUsed:      userTokens,
+		DailyLimit:      budget.UserDailyTokens,
+		BudgetExhausted: budget.exhausted(userID, userTokens, globalTokens),
+	}, nil
+}
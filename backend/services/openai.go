@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIURL   = "https://api.openai.com/v1"
+	defaultOpenAIModel = "gpt-4o-mini"
+)
+
+// OpenAIProvider calls an OpenAI-style /chat/completions endpoint. Besides OpenAI itself
+// this covers local servers that speak the same API, such as Ollama and llama.cpp.
+type OpenAIProvider struct {
+	name    string
+	BaseURL string
+	APIKey  string // Sent as a bearer token when set
+	Model   string
+	Client  *http.Client
+}
+
+// NewOpenAIProvider creates a provider for OpenAI's API. An empty model uses defaultOpenAIModel.
+func NewOpenAIProvider(apiKey, model string, timeout time.Duration) *OpenAIProvider {
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	return &OpenAIProvider{
+		name:    "openai",
+		BaseURL: defaultOpenAIURL,
+		APIKey:  apiKey,
+		Model:   model,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// NewLocalLLMProvider creates a provider for an OpenAI-compatible server at baseURL, e.g.
+// http://localhost:11434/v1 for Ollama. Most local servers need no API key.
+func NewLocalLLMProvider(baseURL, model, apiKey string, timeout time.Duration) *OpenAIProvider {
+	return &OpenAIProvider{
+		name:    "local",
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Model:   model,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name identifies the provider as "openai" or "local"
+func (o *OpenAIProvider) Name() string { return o.name }
+
+type chatCompletionMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model         string                       `json:"model"`
+	Messages      []chatCompletionMessage      `json:"messages"`
+	Stream        bool                         `json:"stream,omitempty"`
+	StreamOptions *chatCompletionStreamOptions `json:"stream_options,omitempty"`
+}
+
+type chatCompletionStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Report token usage in the last event
+}
+
+type chatCompletionUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type chatCompletionError struct {
+	Message string `json:"message"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatCompletionMessage `json:"message"`
+	} `json:"choices"`
+	Usage *chatCompletionUsage `json:"usage"`
+	Error *chatCompletionError `json:"error"`
+}
+
+// chatCompletionChunk is one server-sent event of a streamed completion
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *chatCompletionUsage `json:"usage"`
+	Error *chatCompletionError `json:"error"`
+}
+
+// post sends the prompt as a single user message and returns the response once its
+// status is known to be OK
+func (o *OpenAIProvider) post(ctx context.Context, req LLMRequest, stream bool) (*http.Response, error) {
+	body := chatCompletionRequest{
+		Model:    req.Model,
+		Messages: []chatCompletionMessage{{Role: "user", Content: req.Prompt}},
+		Stream:   stream,
+	}
+	if body.Model == "" {
+		body.Model = o.Model
+	}
+	if stream {
+		body.StreamOptions = &chatCompletionStreamOptions{IncludeUsage: true}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, o.BaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.APIKey)
+	}
+
+	resp, err := o.Client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", o.name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%s returned %d: %s", o.name, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return resp, nil
+}
+
+// Generate returns the first choice of a completion
+func (o *OpenAIProvider) Generate(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	resp, err := o.post(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var parsed chatCompletionResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid %s response: %w", o.name, err)
+	}
+	if parsed.Error != nil {
+		return nil, errors.New(parsed.Error.Message)
+	}
+	if len(parsed.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", o.name)
+	}
+	result := &LLMResponse{Text: parsed.Choices[0].Message.Content}
+	if parsed.Usage != nil {
+		result.InputTokens = parsed.Usage.PromptTokens
+		result.OutputTokens = parsed.Usage.CompletionTokens
+	}
+	return result, nil
+}
+
+// GenerateStream reads a streamed completion's server-sent events and passes on each
+// piece of text as it arrives
+func (o *OpenAIProvider) GenerateStream(ctx context.Context, req LLMRequest, onChunk func(string)) (*LLMResponse, error) {
+	resp, err := o.post(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var text strings.Builder
+	result := &LLMResponse{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk chatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("invalid %s stream event: %w", o.name, err)
+		}
+		if chunk.Error != nil {
+			return nil, errors.New(chunk.Error.Message)
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				text.WriteString(choice.Delta.Content)
+				onChunk(choice.Delta.Content)
+			}
+		}
+		if chunk.Usage != nil {
+			result.InputTokens = chunk.Usage.PromptTokens
+			result.OutputTokens = chunk.Usage.CompletionTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s stream failed: %w", o.name, err)
+	}
+	result.Text = text.String()
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinPersonalitiesAreValid(t *testing.T) {
+	loaded, err := loadPersonalityFiles(builtinPersonalityFiles, "personalities")
+	if err != nil {
+		t.Fatalf("Failed to load built-in personalities: %v", err)
+	}
+	if len(loaded) != 14 {
+		t.Errorf("Expected 13 bots and the default, got %d", len(loaded))
+	}
+	yoda := loaded["Yoda"]
+	if yoda.Level != "Legends" || yoda.Rating != 2400 || !strings.HasPrefix(yoda.ErrorResponse, yoda.Catchphrases[0]) {
+		t.Errorf("Unexpected Yoda: level %s, rating %d, error line %q", yoda.Level, yoda.Rating, yoda.ErrorResponse)
+	}
+}
+
+func TestGetBotPersonalityFallsBackToDefault(t *testing.T) {
+	b := GetBotPersonality("Nobody In Particular")
+	if b.Name != "Nobody In Particular" || b.Level != "Medium" {
+		t.Errorf("Expected the default persona under the requested name, got %s (%s)", b.Name, b.Level)
+	}
+	if got := personalityErrorResponse(b, "fallback"); got != "fallback" {
+		t.Errorf("Expected the caller's message for a bot without an error line, got %q", got)
+	}
+	if got := personalityClarificationRequest(GetBotPersonality("Rafiki")); !strings.Contains(got, "Pride Rock") {
+		t.Errorf("Expected Rafiki's clarification request, got %q", got)
+	}
+}
+
+func TestParseBotPersonalityRejectsUnknownFields(t *testing.T) {
+	if _, err := ParseBotPersonality([]byte("name: Pat\ntonne: calm\n"), "yaml"); err == nil {
+		t.Error("Expected an unknown YAML field to be rejected")
+	}
+	if _, err := ParseBotPersonality([]byte(`{"name":"Pat","tonne":"calm"}`), "json"); err == nil {
+		t.Error("Expected an unknown JSON field to be rejected")
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	err := BotPersonality{Name: "Pat", Level: "Impossible", Rating: 5}.Validate()
+	if err == nil {
+		t.Fatal("Expected an incomplete personality to be invalid")
+	}
+	for _, want := range []string{"level", "rating", "tone", "catchphrase"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestInitBotPersonalitiesLoadsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	pat := `{"name":"Pundit Pat","rating":1400,"level":"Easy","tone":"Chirpy","rhetoricalStyle":"Soundbites",` +
+		`"linguisticQuirks":"Hashtags","debateStrategy":"Repeat the headline","interactionStyle":"Breezy","catchphrases":["Breaking news!"]}`
+	if err := os.WriteFile(filepath.Join(dir, "pat.json"), []byte(pat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitBotPersonalities(dir); err != nil {
+		t.Fatalf("InitBotPersonalities: %v", err)
+	}
+	defer InitBotPersonalities("")
+
+	if b := GetBotPersonality("Pundit Pat"); b.Tone != "Chirpy" {
+		t.Errorf("Expected the personality from the directory, got %+v", b)
+	}
+	if list := ListBotPersonalities(); len(list) != 14 || list[0].Name != "Rookie Rick" {
+		t.Errorf("Expected 14 bots easiest first, got %d starting with %s", len(list), list[0].Name)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.yaml"), []byte("name: Broken\nlevel: Easy\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitBotPersonalities(dir); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("Expected the invalid file to be named in the error, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecordPointOfInformation stores the latest state of a point of information
+func RecordPointOfInformation(ctx context.Context, poi models.PointOfInformation) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	_, err := db.MongoDatabase.Collection("debate_pois").ReplaceOne(ctx, bson.M{"_id": poi.ID}, poi, options.Replace().SetUpsert(true))
+	return err
+}
+
+// GetPointsOfInformation returns the points of information offered in a room, oldest first
+func GetPointsOfInformation(ctx context.Context, roomID string) ([]models.PointOfInformation, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "requestedAt", Value: 1}})
+	cursor, err := db.MongoDatabase.Collection("debate_pois").Find(ctx, bson.M{"roomId": roomID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var pois []models.PointOfInformation
+	if err := cursor.All(ctx, &pois); err != nil {
+		return nil, err
+	}
+	return pois, nil
+}
+
+// maxPOIPromptText bounds the characters of a point of information's text the judge is shown
+const maxPOIPromptText = 300
+
+// poiPromptText is a point of information's text as the judge sees it: on one line,
+// cut to maxPOIPromptText characters and quoted, so a debater cannot start new prompt
+// lines or pass their text off as instructions
+func poiPromptText(content string) string {
+	text := strings.Join(strings.Fields(content), " ")
+	if runes := []rune(text); len(runes) > maxPOIPromptText {
+		text = string(runes[:maxPOIPromptText]) + "..."
+	}
+	return fmt.Sprintf("%q", text)
+}
+
+// formatPointsOfInformation renders points of information as transcript lines for the judge
+func formatPointsOfInformation(pois []models.PointOfInformation) string {
+	var b strings.Builder
+	for _, poi := range pois {
+		offerer := "For"
+		if poi.OffererRole == "against" {
+			offerer = "Against"
+		}
+		outcome := poi.Status
+		if outcome == models.POIStatusEnded {
+			outcome = models.POIStatusAccepted
+		}
+		line := fmt.Sprintf("%s offered a point of information during %s: %s", offerer, poi.Phase, outcome)
+		if strings.TrimSpace(poi.Content) != "" {
+			line += " (" + poiPromptText(poi.Content) + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrPollPermission is returned when a user who is neither the host nor a moderator manages polls
+	ErrPollPermission = errors.New("only the debate host or a moderator can manage polls")
+	// ErrPollReserved is returned when managing one of the motion polls the debate's phases run
+	ErrPollReserved = errors.New("motion polls follow the debate's phases and cannot be managed by hand")
+)
+
+// CreatePollInput is a host or moderator's request to create a spectator poll. The server
+// picks the poll's ID.
+type CreatePollInput struct {
+	Question        string   `json:"question" binding:"required"`
+	Options         []string `json:"options" binding:"required"`
+	Status          string   `json:"status,omitempty"`          // "draft" or "open" (default)
+	DurationSeconds int      `json:"durationSeconds,omitempty"` // Auto-close delay once open
+}
+
+// pollTimers holds the auto-close timers of open polls, keyed by "<debateId>:<pollId>"
+var (
+	pollTimers   = map[string]*time.Timer{}
+	pollTimersMu sync.Mutex
+)
+
+// CanManagePolls reports whether a user may create, open and close polls in a debate: the
+// host, or a user allowed to take the moderator seat. The motion polls are reserved, so a
+// host who is debating cannot use polls to sway the result.
+func CanManagePolls(ctx context.Context, debateID, userID string) bool {
+	return IsRoomHost(ctx, debateID, userID) || CanTakeSeat(ctx, debateID, userID, "moderator")
+}
+
+// CreateDebatePoll creates a spectator poll on behalf of the host or a moderator
+func CreateDebatePoll(ctx context.Context, debateID, userID string, input CreatePollInput) (*debate.PollMetadata, error) {
+	if !CanManagePolls(ctx, debateID, userID) {
+		return nil, ErrPollPermission
+	}
+
+	store := debate.NewPollStore()
+	pollID, err := store.CreatePoll(debateID, "", input.Question, input.Options, debate.PollSettings{
+		Status:          input.Status,
+		DurationSeconds: input.DurationSeconds,
+		CreatedBy:       userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	meta, err := store.GetPoll(debateID, pollID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedulePollClose(debateID, meta)
+	publishSpectatorEvent(debateID, "poll_created", debate.PollCreatedPayload{
+		PollID:    meta.PollID,
+		Question:  meta.Question,
+		Options:   meta.Options,
+		Status:    meta.Status,
+		ClosesAt:  meta.ClosesAt,
+		Timestamp: time.Now().Unix(),
+	})
+	return meta, nil
+}
+
+// OpenDebatePoll starts voting on a draft poll
+func OpenDebatePoll(ctx context.Context, debateID, pollID, userID string) (*debate.PollMetadata, error) {
+	if IsMotionPoll(pollID) {
+		return nil, ErrPollReserved
+	}
+	if !CanManagePolls(ctx, debateID, userID) {
+		return nil, ErrPollPermission
+	}
+
+	meta, err := debate.NewPollStore().OpenPoll(debateID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Status != debate.PollStatusOpen {
+		return meta, nil
+	}
+
+	schedulePollClose(debateID, meta)
+	publishSpectatorEvent(debateID, "poll_opened", debate.PollStatusPayload{
+		PollID:    meta.PollID,
+		Status:    meta.Status,
+		ClosesAt:  meta.ClosesAt,
+		Timestamp: time.Now().Unix(),
+	})
+	return meta, nil
+}
+
+// CloseDebatePoll stops voting on a poll on behalf of the host or a moderator
+func CloseDebatePoll(ctx context.Context, debateID, pollID, userID string) (*debate.PollMetadata, error) {
+	if IsMotionPoll(pollID) {
+		return nil, ErrPollReserved
+	}
+	if !CanManagePolls(ctx, debateID, userID) {
+		return nil, ErrPollPermission
+	}
+	return closeDebatePoll(debateID, pollID)
+}
+
+// CloseExpiredPolls closes and announces every poll of a debate whose close time has
+// passed. The auto-close timer only lives on the instance that opened the poll, so this
+// catches polls whose timer was lost to a restart; the store announces each close once.
+func CloseExpiredPolls(debateID string) {
+	_, _, metadata, err := debate.NewPollStore().GetPollState(debateID)
+	if err != nil {
+		return
+	}
+	now := time.Now().UnixMilli()
+	for pollID, meta := range metadata {
+		if meta.ClosesAt == 0 || meta.ClosesAt > now {
+			continue
+		}
+		if _, err := closeDebatePoll(debateID, pollID); err != nil {
+			log.Printf("Failed to close expired poll: debate=%s poll=%s err=%v", debateID, pollID, err)
+		}
+	}
+}
+
+// closeDebatePoll freezes a poll's results and announces them the first time it closes
+func closeDebatePoll(debateID, pollID string) (*debate.PollMetadata, error) {
+	pollTimersMu.Lock()
+	if timer, ok := pollTimers[debateID+":"+pollID]; ok {
+		timer.Stop()
+		delete(pollTimers, debateID+":"+pollID)
+	}
+	pollTimersMu.Unlock()
+
+	store := debate.NewPollStore()
+	meta, closed, err := store.ClosePoll(debateID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !closed {
+		return meta, nil
+	}
+
+	counts, voters, err := store.PollResults(debateID, pollID)
+	if err != nil {
+		log.Printf("Failed to load poll results: debate=%s poll=%s err=%v", debateID, pollID, err)
+	}
+	publishSpectatorEvent(debateID, "poll_closed", debate.PollStatusPayload{
+		PollID:    pollID,
+		Status:    debate.PollStatusClosed,
+		Counts:    counts,
+		Voters:    voters,
+		Timestamp: time.Now().Unix(),
+	})
+	return meta, nil
+}
+
+// schedulePollClose arms the auto-close timer of an open poll with a close time
+func schedulePollClose(debateID string, meta *debate.PollMetadata) {
+	if meta.Status != debate.PollStatusOpen || meta.ClosesAt == 0 {
+		return
+	}
+	key := debateID + ":" + meta.PollID
+	pollID := meta.PollID
+	wait := time.Until(time.UnixMilli(meta.ClosesAt))
+
+	pollTimersMu.Lock()
+	defer pollTimersMu.Unlock()
+	if timer, ok := pollTimers[key]; ok {
+		timer.Stop()
+	}
+	pollTimers[key] = time.AfterFunc(wait, func() {
+		if _, err := closeDebatePoll(debateID, pollID); err != nil {
+			log.Printf("Failed to auto-close poll: debate=%s poll=%s err=%v", debateID, pollID, err)
+		}
+	})
+}
+
+// publishSpectatorEvent sends a poll or chat event to the debate's spectators
+func publishSpectatorEvent(debateID, eventType string, payload interface{}) {
+	event, err := debate.NewEvent(eventType, payload)
+	if err != nil {
+		return
+	}
+	if err := debate.PublishEvent(debateID, event); err != nil {
+		log.Printf("Failed to publish %s: debate=%s err=%v", eventType, debateID, err)
+	}
+}
+
+// ArchiveDebatePolls closes every poll of an ended debate and stores the frozen results
+// in MongoDB
+func ArchiveDebatePolls(ctx context.Context, debateID string) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	store := debate.NewPollStore()
+	_, _, metadata, err := store.GetPollState(debateID)
+	if err != nil {
+		return err
+	}
+
+	collection := db.MongoDatabase.Collection("debate_polls")
+	now := time.Now()
+	for pollID := range metadata {
+		meta, err := closeDebatePoll(debateID, pollID)
+		if err != nil {
+			return err
+		}
+		counts, voters, err := store.PollResults(debateID, pollID)
+		if err != nil {
+			return err
+		}
+
+		archived := models.ArchivedPoll{
+			ID:         debateID + ":" + pollID,
+			DebateID:   debateID,
+			PollID:     pollID,
+			Question:   meta.Question,
+			Options:    meta.Options,
+			Counts:     counts,
+			Voters:     voters,
+			CreatedBy:  meta.CreatedBy,
+			ClosedAt:   now,
+			ArchivedAt: now,
+		}
+		if meta.OpenedAt > 0 {
+			openedAt := time.UnixMilli(meta.OpenedAt)
+			archived.OpenedAt = &openedAt
+		}
+		if meta.ClosedAt > 0 {
+			archived.ClosedAt = time.UnixMilli(meta.ClosedAt)
+		}
+		if _, err := collection.ReplaceOne(ctx, bson.M{"_id": archived.ID}, archived, options.Replace().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetArchivedPolls returns the frozen poll results of an ended debate
+func GetArchivedPolls(ctx context.Context, debateID string) ([]models.ArchivedPoll, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	cursor, err := db.MongoDatabase.Collection("debate_polls").Find(ctx, bson.M{"debateId": debateID},
+		options.Find().SetSort(bson.D{{Key: "closedAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	polls := []models.ArchivedPoll{}
+	if err := cursor.All(ctx, &polls); err != nil {
+		return nil, err
+	}
+	return polls, nil
+}
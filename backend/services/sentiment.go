@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sentimentBucketSeconds is the resolution of a debate's sentiment timeline
+const sentimentBucketSeconds = 10
+
+// Reactions and poll options become document keys, which may not contain "." or start
+// with "$", so those characters are stored as their full-width forms
+var (
+	sentimentKeyEscaper   = strings.NewReplacer(".", "．", "$", "＄")
+	sentimentKeyUnescaper = strings.NewReplacer("．", ".", "＄", "$")
+)
+
+// recordSentiment adds counts to the timeline bucket that contains at
+func recordSentiment(ctx context.Context, debateID string, at time.Time, inc bson.M) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	start := at.Truncate(sentimentBucketSeconds * time.Second)
+	update := bson.M{
+		"$inc":         inc,
+		"$setOnInsert": bson.M{"debateId": debateID, "start": start},
+	}
+	_, err := db.MongoDatabase.Collection("sentiment_timeline").UpdateOne(ctx,
+		bson.M{"_id": fmt.Sprintf("%s:%d", debateID, start.Unix())}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// RecordReactionBurst adds an aggregated burst of reactions to the debate's timeline
+func RecordReactionBurst(ctx context.Context, debateID string, burst debate.ReactionBurstPayload) error {
+	inc := bson.M{}
+	for side, count := range burst.Sides {
+		inc["reactions."+side] = count
+	}
+	for reaction, count := range burst.Counts {
+		inc["reactionTypes."+sentimentKeyEscaper.Replace(reaction)] = count
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+	return recordSentiment(ctx, debateID, time.UnixMilli(burst.To), inc)
+}
+
+// RecordSentimentVote adds a spectator's poll vote to the debate's timeline
+func RecordSentimentVote(ctx context.Context, debateID, pollID, option string) error {
+	key := fmt.Sprintf("votes.%s.%s", sentimentKeyEscaper.Replace(pollID), sentimentKeyEscaper.Replace(option))
+	return recordSentiment(ctx, debateID, time.Now(), bson.M{key: 1})
+}
+
+// RecordSentimentQuestion adds a spectator question to the debate's timeline
+func RecordSentimentQuestion(ctx context.Context, debateID string) error {
+	return recordSentiment(ctx, debateID, time.Now(), bson.M{"questions": 1})
+}
+
+// RecordDebatePhase marks the start of a debate phase on its timeline. speaker is the
+// side holding the floor, if any.
+func RecordDebatePhase(ctx context.Context, debateID, phase, speaker string) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	_, err := db.MongoDatabase.Collection("debate_phases").InsertOne(ctx, models.DebatePhaseMark{
+		DebateID: debateID,
+		Phase:    phase,
+		Speaker:  speaker,
+		At:       time.Now(),
+	})
+	return err
+}
+
+// GetSentimentTimeline returns a debate's audience activity aligned with its phases
+func GetSentimentTimeline(ctx context.Context, debateID string) (*models.SentimentTimeline, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+
+	var phases []models.DebatePhaseMark
+	cursor, err := db.MongoDatabase.Collection("debate_phases").Find(ctx, bson.M{"debateId": debateID},
+		options.Find().SetSort(bson.D{{Key: "at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &phases); err != nil {
+		return nil, err
+	}
+
+	var buckets []models.SentimentBucket
+	cursor, err = db.MongoDatabase.Collection("sentiment_timeline").Find(ctx, bson.M{"debateId": debateID},
+		options.Find().SetSort(bson.D{{Key: "start", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(ctx, &buckets); err != nil {
+		return nil, err
+	}
+
+	return buildSentimentTimeline(debateID, phases, buckets), nil
+}
+
+// buildSentimentTimeline places each bucket in the phase it started in. Reactions not
+// given a side count for the side speaking at the time, and poll totals run across
+// buckets so swings are visible.
+func buildSentimentTimeline(debateID string, marks []models.DebatePhaseMark, buckets []models.SentimentBucket) *models.SentimentTimeline {
+	timeline := &models.SentimentTimeline{
+		DebateID:      debateID,
+		BucketSeconds: sentimentBucketSeconds,
+		Phases:        make([]models.TimelinePhase, 0, len(marks)),
+		Points:        make([]models.TimelinePoint, 0, len(buckets)),
+	}
+	for i, mark := range marks {
+		phase := models.TimelinePhase{Phase: mark.Phase, Speaker: mark.Speaker, StartedAt: mark.At}
+		if i+1 < len(marks) {
+			endedAt := marks[i+1].At
+			phase.EndedAt = &endedAt
+		}
+		timeline.Phases = append(timeline.Phases, phase)
+	}
+
+	totals := map[string]map[string]int64{}
+	phaseIndex := -1
+	for _, bucket := range buckets {
+		for phaseIndex+1 < len(marks) && !marks[phaseIndex+1].At.After(bucket.Start) {
+			phaseIndex++
+		}
+		point := models.TimelinePoint{
+			Start:         bucket.Start,
+			Reactions:     map[string]int64{},
+			ReactionTypes: map[string]int64{},
+			Questions:     bucket.Questions,
+		}
+		speaker := ""
+		if phaseIndex >= 0 {
+			point.Phase = marks[phaseIndex].Phase
+			speaker = marks[phaseIndex].Speaker
+		}
+
+		for side, count := range bucket.Reactions {
+			if side == debate.ReactionSideNone && speaker != "" {
+				side = speaker
+			}
+			point.Reactions[side] += count
+		}
+		for reaction, count := range bucket.ReactionTypes {
+			point.ReactionTypes[sentimentKeyUnescaper.Replace(reaction)] = count
+		}
+
+		if len(bucket.Votes) > 0 {
+			point.Votes = map[string]map[string]int64{}
+			for pollID, options := range bucket.Votes {
+				pollID = sentimentKeyUnescaper.Replace(pollID)
+				if totals[pollID] == nil {
+					totals[pollID] = map[string]int64{}
+				}
+				point.Votes[pollID] = map[string]int64{}
+				for option, count := range options {
+					option = sentimentKeyUnescaper.Replace(option)
+					point.Votes[pollID][option] = count
+					totals[pollID][option] += count
+				}
+			}
+		}
+		if len(totals) > 0 {
+			point.PollTotals = map[string]map[string]int64{}
+			for pollID, options := range totals {
+				point.PollTotals[pollID] = map[string]int64{}
+				for option, count := range options {
+					point.PollTotals[pollID][option] = count
+				}
+			}
+		}
+		timeline.Points = append(timeline.Points, point)
+	}
+	return timeline
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+)
+
+func TestBuildSentimentTimeline(t *testing.T) {
+	start := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+	marks := []models.DebatePhaseMark{
+		{Phase: "openingFor", Speaker: "for", At: start},
+		{Phase: "openingAgainst", Speaker: "against", At: start.Add(20 * time.Second)},
+	}
+	buckets := []models.SentimentBucket{
+		{
+			Start:         start,
+			Reactions:     map[string]int64{"none": 3, "against": 1},
+			ReactionTypes: map[string]int64{"👏": 4},
+			Votes:         map[string]map[string]int64{"motion-pre": {"for": 2}},
+		},
+		{
+			Start:         start.Add(20 * time.Second),
+			Reactions:     map[string]int64{"none": 2},
+			ReactionTypes: map[string]int64{"a．b": 2},
+			Votes:         map[string]map[string]int64{"motion-pre": {"against": 1}},
+			Questions:     1,
+		},
+	}
+
+	timeline := buildSentimentTimeline("d1", marks, buckets)
+	if len(timeline.Phases) != 2 || timeline.Phases[0].EndedAt == nil || timeline.Phases[1].EndedAt != nil {
+		t.Fatalf("Expected the first phase to end where the second starts, got %+v", timeline.Phases)
+	}
+	if len(timeline.Points) != 2 {
+		t.Fatalf("Expected 2 points, got %d", len(timeline.Points))
+	}
+
+	first, second := timeline.Points[0], timeline.Points[1]
+	if first.Phase != "openingFor" || second.Phase != "openingAgainst" {
+		t.Errorf("Expected points in openingFor and openingAgainst, got %s and %s", first.Phase, second.Phase)
+	}
+	// Unattributed reactions count for the side speaking
+	if first.Reactions["for"] != 3 || first.Reactions["against"] != 1 || second.Reactions["against"] != 2 {
+		t.Errorf("Unexpected reactions: %v then %v", first.Reactions, second.Reactions)
+	}
+	if second.ReactionTypes["a.b"] != 2 {
+		t.Errorf("Expected escaped reaction keys to be restored, got %v", second.ReactionTypes)
+	}
+	totals := second.PollTotals["motion-pre"]
+	if totals["for"] != 2 || totals["against"] != 1 || second.Votes["motion-pre"]["for"] != 0 {
+		t.Errorf("Expected running poll totals, got votes %v totals %v", second.Votes, second.PollTotals)
+	}
+}
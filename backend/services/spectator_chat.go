@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Spectator chat limits
+const (
+	MaxChatMessageLength = 300
+	maxChatSlowMode      = 10 * time.Minute
+	maxChatBlockedWords  = 100
+	defaultChatMute      = 5 * time.Minute
+)
+
+var (
+	// ErrChatPermission is returned when a user who is neither the host nor a moderator moderates the chat
+	ErrChatPermission = errors.New("only the debate host or a moderator can moderate the chat")
+	// ErrChatRestricted is returned when a muted or banned spectator sends a message
+	ErrChatRestricted = errors.New("you cannot send messages in this chat")
+	// ErrChatLinkBlocked is returned for a message with a link when links are blocked
+	ErrChatLinkBlocked = errors.New("links are not allowed in this chat")
+)
+
+// CanModerateSpectators reports whether a user may moderate a debate's spectators: the
+// host, or a user allowed to take the moderator seat
+func CanModerateSpectators(ctx context.Context, debateID, userID string) bool {
+	return IsRoomHost(ctx, debateID, userID) || CanTakeSeat(ctx, debateID, userID, "moderator")
+}
+
+// ChatRateError is returned when a spectator sends messages faster than slow mode or the
+// chat rate limit allow
+type ChatRateError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ChatRateError) Error() string {
+	return fmt.Sprintf("slow down: you can send another message in %ds", int(e.RetryAfter.Round(time.Second)/time.Second))
+}
+
+// ChatSender identifies the spectator sending a chat message
+type ChatSender struct {
+	SpectatorHash string
+	UserID        string // Empty for anonymous spectators
+}
+
+// SendSpectatorChat checks a spectator's message against the debate's chat rules, stores
+// it in the history and publishes it to the debate's spectators
+func SendSpectatorChat(ctx context.Context, debateID string, sender ChatSender, text string) (*debate.ChatMessage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("message text is required")
+	}
+	if utf8.RuneCountInString(text) > MaxChatMessageLength {
+		return nil, errors.New("message is too long")
+	}
+
+	store := debate.NewChatStore()
+	restriction, err := store.Restriction(debateID, sender.SpectatorHash)
+	if err != nil {
+		return nil, err
+	}
+	if restriction.Active(time.Now()) {
+		return nil, ErrChatRestricted
+	}
+
+	settings, err := store.GetSettings(debateID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.BlockLinks && debate.ContainsLink(text) {
+		return nil, ErrChatLinkBlocked
+	}
+
+	limiter := debate.NewRateLimiter()
+	result, err := limiter.Allow(debate.ActionChat, debateID+":"+sender.SpectatorHash)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Allowed {
+		return nil, &ChatRateError{RetryAfter: result.RetryAfter}
+	}
+	if settings.SlowModeSeconds > 0 {
+		slowMode := debate.RateLimitPolicy{Limit: 1, Window: time.Duration(settings.SlowModeSeconds) * time.Second}
+		result, err := limiter.AllowPolicy(fmt.Sprintf("rate:chat_slow:%s:%s", debateID, sender.SpectatorHash), slowMode)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Allowed {
+			return nil, &ChatRateError{RetryAfter: result.RetryAfter}
+		}
+	}
+
+	text = debate.FilterChatText(text, debate.GetChatConfig().BlockedWords)
+	text = debate.FilterChatText(text, settings.BlockedWords)
+	message := &debate.ChatMessage{
+		ID:            uuid.New().String(),
+		SpectatorHash: sender.SpectatorHash,
+		Name:          spectatorChatName(ctx, sender),
+		Text:          text,
+		Timestamp:     time.Now().UnixMilli(),
+	}
+	if err := store.AppendMessage(debateID, *message); err != nil {
+		return nil, err
+	}
+	publishSpectatorEvent(debateID, "chat_message", message)
+	return message, nil
+}
+
+// spectatorChatName is the name shown with a spectator's messages: a logged-in user's
+// display name, or a guest name derived from the spectator's hash
+func spectatorChatName(ctx context.Context, sender ChatSender) string {
+	if sender.UserID != "" && db.MongoDatabase != nil {
+		if id, err := primitive.ObjectIDFromHex(sender.UserID); err == nil {
+			var user models.User
+			opts := options.FindOne().SetProjection(bson.M{"displayName": 1})
+			if err := db.MongoDatabase.Collection("users").FindOne(ctx, bson.M{"_id": id}, opts).Decode(&user); err == nil && user.DisplayName != "" {
+				return user.DisplayName
+			}
+		}
+	}
+	suffix := sender.SpectatorHash
+	if len(suffix) > 6 {
+		suffix = suffix[:6]
+	}
+	return "Guest " + suffix
+}
+
+// GetSpectatorChat returns a debate's recent chat and its chat rules, for spectators who join late
+func GetSpectatorChat(debateID string) ([]debate.ChatMessage, debate.ChatSettings, error) {
+	store := debate.NewChatStore()
+	messages, err := store.History(debateID)
+	if err != nil {
+		return nil, debate.ChatSettings{}, err
+	}
+	settings, err := store.GetSettings(debateID)
+	if err != nil {
+		return nil, debate.ChatSettings{}, err
+	}
+	return messages, settings, nil
+}
+
+// UpdateChatSettings lets the host or a moderator change a debate's slow mode, word
+// filter and link blocking
+func UpdateChatSettings(ctx context.Context, debateID, userID string, settings debate.ChatSettings) (*debate.ChatSettings, error) {
+	if !CanModerateSpectators(ctx, debateID, userID) {
+		return nil, ErrChatPermission
+	}
+	if settings.SlowModeSeconds < 0 || time.Duration(settings.SlowModeSeconds)*time.Second > maxChatSlowMode {
+		return nil, fmt.Errorf("slow mode must be between 0 and %d seconds", int(maxChatSlowMode.Seconds()))
+	}
+	if len(settings.BlockedWords) > maxChatBlockedWords {
+		return nil, fmt.Errorf("at most %d blocked words are allowed", maxChatBlockedWords)
+	}
+	words := make([]string, 0, len(settings.BlockedWords))
+	for _, word := range settings.BlockedWords {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	settings.BlockedWords = words
+
+	if err := debate.NewChatStore().SetSettings(debateID, settings); err != nil {
+		return nil, err
+	}
+	publishSpectatorEvent(debateID, "chat_settings", settings)
+	return &settings, nil
+}
+
+// ModerateSpectatorChat applies a host or moderator's chat action: deleting a message,
+// or muting, banning or restoring the spectator who sent it. Banning also removes the
+// spectator's messages from the history.
+func ModerateSpectatorChat(ctx context.Context, debateID, userID string, action debate.ChatModeratePayload) error {
+	if !CanModerateSpectators(ctx, debateID, userID) {
+		return ErrChatPermission
+	}
+
+	if action.MessageID == "" {
+		return errors.New("a message ID is required")
+	}
+	store := debate.NewChatStore()
+	now := time.Now()
+	switch action.Action {
+	case "delete":
+		deleted, err := store.DeleteMessage(debateID, action.MessageID)
+		if err != nil {
+			return err
+		}
+		if deleted {
+			publishSpectatorEvent(debateID, "chat_deleted", debate.ChatDeletedPayload{MessageID: action.MessageID, Timestamp: now.Unix()})
+		}
+		return nil
+	case "mute", "ban", "unmute", "unban":
+	default:
+		return errors.New("action must be delete, mute, unmute, ban or unban")
+	}
+
+	spectatorHash, err := store.MessageSender(debateID, action.MessageID)
+	if err != nil {
+		return err
+	}
+	if spectatorHash == "" {
+		return errors.New("message not found")
+	}
+	restriction := debate.ChatRestriction{MessageID: action.MessageID}
+	switch action.Action {
+	case "mute":
+		duration := time.Duration(action.DurationSeconds) * time.Second
+		if duration <= 0 {
+			duration = defaultChatMute
+		}
+		restriction.MutedUntil = now.Add(duration).UnixMilli()
+		if err := store.Restrict(debateID, spectatorHash, restriction); err != nil {
+			return err
+		}
+	case "ban":
+		restriction.Banned = true
+		if err := store.Restrict(debateID, spectatorHash, restriction); err != nil {
+			return err
+		}
+		deleted, err := store.DeleteMessagesFrom(debateID, spectatorHash)
+		if err != nil {
+			return err
+		}
+		if len(deleted) > 0 {
+			publishSpectatorEvent(debateID, "chat_deleted", debate.ChatDeletedPayload{MessageIDs: deleted, Timestamp: now.Unix()})
+		}
+	default:
+		if err := store.Unrestrict(debateID, spectatorHash); err != nil {
+			return err
+		}
+	}
+
+	publishSpectatorEvent(debateID, "chat_restricted", debate.ChatRestrictedPayload{
+		MessageID:  action.MessageID,
+		Banned:     restriction.Banned,
+		MutedUntil: restriction.MutedUntil,
+		Timestamp:  now.Unix(),
+	})
+	return nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"arguehub/db"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RequiresLoggedInSpectators reports whether only logged-in spectators' votes count in a
+// debate. Debates that cannot be looked up accept anonymous votes.
+func RequiresLoggedInSpectators(ctx context.Context, debateID string) bool {
+	if db.MongoDatabase == nil {
+		return false
+	}
+	var room struct {
+		RequireLoggedInSpectators bool `bson:"requireLoggedInSpectators"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"requireLoggedInSpectators": 1})
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": debateID}, opts).Decode(&room); err != nil {
+		return false
+	}
+	return room.RequireLoggedInSpectators
+}
+
+// SetRequireLoggedInSpectators updates whether anonymous spectators may vote in a room
+func SetRequireLoggedInSpectators(ctx context.Context, roomID string, required bool) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	update := bson.M{"$set": bson.M{"requireLoggedInSpectators": required}}
+	_, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/config"
+	"arguehub/models"
+)
+
+// Transcriber turns recorded debate audio into timed text
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio []byte, mimeType string) (*TranscriptionResult, error)
+}
+
+// TranscriptionResult is the output of a Transcriber. Segment times are relative to the
+// start of the audio that was transcribed.
+type TranscriptionResult struct {
+	Text     string
+	Segments []models.TranscriptSegment
+}
+
+var (
+	transcriber   Transcriber
+	transcriberMu sync.RWMutex
+)
+
+// InitTranscriptionService selects the speech-to-text backend from the config
+func InitTranscriptionService(cfg *config.Config) {
+	tc := cfg.Transcription
+	SetAudioDir(tc.AudioDir)
+	SetAudioRetention(tc.AudioRetention, tc.MaxAttempts)
+	switch strings.ToLower(tc.Provider) {
+	case "whisper":
+		if tc.WhisperURL == "" {
+			log.Printf("⚠️  Warning: transcription provider is whisper but whisperUrl is not set")
+			return
+		}
+		timeout := time.Duration(tc.TimeoutSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = 60 * time.Second
+		}
+		SetTranscriber(NewWhisperHTTPTranscriber(tc.WhisperURL, tc.Model, tc.Language, timeout))
+		log.Printf("✅ Whisper transcription enabled at %s", tc.WhisperURL)
+	case "fake":
+		SetTranscriber(&FakeTranscriber{})
+		log.Println("Using fake transcription backend")
+	default:
+		log.Println("Server-side transcription disabled; audio chunks will be stored without transcripts")
+	}
+}
+
+// SetTranscriber replaces the speech-to-text backend, e.g. with a FakeTranscriber in tests
+func SetTranscriber(t Transcriber) {
+	transcriberMu.Lock()
+	defer transcriberMu.Unlock()
+	transcriber = t
+}
+
+// GetTranscriber returns the configured speech-to-text backend, or nil when disabled
+func GetTranscriber() Transcriber {
+	transcriberMu.RLock()
+	defer transcriberMu.RUnlock()
+	return transcriber
+}
+
+// WhisperHTTPTranscriber calls a Whisper-compatible service exposing the
+// /v1/audio/transcriptions endpoint (whisper.cpp server, faster-whisper-server,
+// or transcribeService.py).
+type WhisperHTTPTranscriber struct {
+	BaseURL  string
+	Model    string
+	Language string
+	Client   *http.Client
+}
+
+// NewWhisperHTTPTranscriber creates a transcriber for the service at baseURL
+func NewWhisperHTTPTranscriber(baseURL, model, language string, timeout time.Duration) *WhisperHTTPTranscriber {
+	if model == "" {
+		model = "whisper-1"
+	}
+	return &WhisperHTTPTranscriber{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		Model:    model,
+		Language: language,
+		Client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// whisperResponse is the verbose_json response of a Whisper-compatible service
+type whisperResponse struct {
+	Text     string `json:"text"`
+	Segments []struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+	Error string `json:"error"`
+}
+
+// Transcribe uploads the audio and returns the service's segments
+func (w *WhisperHTTPTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string) (*TranscriptionResult, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "chunk"+audioExtension(mimeType))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return nil, err
+	}
+	form.WriteField("model", w.Model)
+	form.WriteField("response_format", "verbose_json")
+	if w.Language != "" {
+		form.WriteField("language", w.Language)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.BaseURL+"/v1/audio/transcriptions", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("transcription request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transcription service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	var parsed whisperResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid transcription response: %w", err)
+	}
+	if parsed.Error != "" {
+		return nil, errors.New(parsed.Error)
+	}
+
+	result := &TranscriptionResult{Text: strings.TrimSpace(parsed.Text)}
+	for _, s := range parsed.Segments {
+		result.Segments = append(result.Segments, models.TranscriptSegment{
+			Start: s.Start,
+			End:   s.End,
+			Text:  strings.TrimSpace(s.Text),
+		})
+	}
+	if len(result.Segments) == 0 && result.Text != "" {
+		result.Segments = []models.TranscriptSegment{{Text: result.Text}}
+	}
+	return result, nil
+}
+
+// FakeTranscriber returns canned text without calling a service. Each call is recorded
+// so tests can assert on what was sent.
+type FakeTranscriber struct {
+	Text  string // Returned for every chunk; defaults to a description of the audio
+	Err   error
+	mu    sync.Mutex
+	Calls int
+}
+
+// Transcribe returns the canned text as a single segment
+func (f *FakeTranscriber) Transcribe(ctx context.Context, audio []byte, mimeType string) (*TranscriptionResult, error) {
+	f.mu.Lock()
+	f.Calls++
+	f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	text := f.Text
+	if text == "" {
+		text = fmt.Sprintf("[%d bytes of %s audio]", len(audio), mimeType)
+	}
+	return &TranscriptionResult{
+		Text:     text,
+		Segments: []models.TranscriptSegment{{Start: 0, End: 0, Text: text}},
+	}, nil
+}
+
+// audioExtension picks a file extension for an audio MIME type
+func audioExtension(mimeType string) string {
+	switch {
+	case strings.Contains(mimeType, "webm"):
+		return ".webm"
+	case strings.Contains(mimeType, "ogg"):
+		return ".ogg"
+	case strings.Contains(mimeType, "wav"):
+		return ".wav"
+	case strings.Contains(mimeType, "mpeg"), strings.Contains(mimeType, "mp3"):
+		return ".mp3"
+	case strings.Contains(mimeType, "mp4"), strings.Contains(mimeType, "m4a"):
+		return ".m4a"
+	default:
+		return ".bin"
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWhisperHTTPTranscriber(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/transcriptions" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Failed to parse form: %v", err)
+		}
+		if got := r.FormValue("response_format"); got != "verbose_json" {
+			t.Errorf("Expected verbose_json response format, got %q", got)
+		}
+		w.Write([]byte(`{"text":" Hello judges. ","segments":[{"start":0,"end":1.5,"text":" Hello judges. "}]}`))
+	}))
+	defer server.Close()
+
+	transcriber := NewWhisperHTTPTranscriber(server.URL+"/", "", "en", 5*time.Second)
+	result, err := transcriber.Transcribe(context.Background(), []byte("audio"), "audio/webm")
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if result.Text != "Hello judges." {
+		t.Errorf("Expected trimmed text, got %q", result.Text)
+	}
+	if len(result.Segments) != 1 || result.Segments[0].End != 1.5 {
+		t.Errorf("Unexpected segments: %+v", result.Segments)
+	}
+}
+
+func TestFakeTranscriber(t *testing.T) {
+	fake := &FakeTranscriber{Text: "scripted"}
+	result, err := fake.Transcribe(context.Background(), []byte("abc"), "audio/ogg")
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if result.Text != "scripted" || fake.Calls != 1 {
+		t.Errorf("Unexpected fake result %q after %d calls", result.Text, fake.Calls)
+	}
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Motion polls run automatically in every live debate, driven by the room's phases: the
+// pre-debate vote is open while the debaters set up until the opening speeches start, the
+// post-debate vote from the closing speeches until the debate finishes.
+const (
+	MotionPrePollID  = motionPollPrefix + "pre"
+	MotionPostPollID = motionPollPrefix + "post"
+
+	// motionPollPrefix marks the poll IDs reserved for the motion polls
+	motionPollPrefix = "motion-"
+
+	motionOptionFor       = "For"
+	motionOptionAgainst   = "Against"
+	motionOptionUndecided = "Undecided"
+
+	// defaultSwingWeight is the share of the swing in mix mode when none is configured
+	defaultSwingWeight = 0.5
+)
+
+var motionOptions = []string{motionOptionFor, motionOptionAgainst, motionOptionUndecided}
+
+// IsMotionPoll reports whether a poll ID belongs to the motion polls the phases drive
+func IsMotionPoll(pollID string) bool {
+	return strings.HasPrefix(pollID, motionPollPrefix)
+}
+
+// NormalizeWinnerSource returns a supported winner source, defaulting to the judges
+func NormalizeWinnerSource(source string) string {
+	switch strings.ToLower(strings.TrimSpace(source)) {
+	case models.WinnerSourceSwing:
+		return models.WinnerSourceSwing
+	case models.WinnerSourceMix:
+		return models.WinnerSourceMix
+	default:
+		return models.WinnerSourceJudge
+	}
+}
+
+// ValidateSwingWeight checks a room's swing weight; nil uses the default share
+func ValidateSwingWeight(swingWeight *float64) error {
+	if swingWeight != nil && (*swingWeight < 0 || *swingWeight > 1) {
+		return errors.New("swingWeight must be between 0 and 1")
+	}
+	return nil
+}
+
+// SetRoomWinnerSource updates whether the judges, the audience swing or a mix decides a
+// room. A nil swing weight clears the room's weight so the default share applies.
+func SetRoomWinnerSource(ctx context.Context, roomID, source string, swingWeight *float64) error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	if err := ValidateSwingWeight(swingWeight); err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"winnerSource": NormalizeWinnerSource(source)}}
+	if swingWeight != nil {
+		update["$set"].(bson.M)["swingWeight"] = *swingWeight
+	} else {
+		update["$unset"] = bson.M{"swingWeight": ""}
+	}
+	_, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": roomID}, update)
+	return err
+}
+
+// openMotionPoll creates a motion poll unless it already exists, so reconnects and
+// repeated phase changes never reset its votes
+func openMotionPoll(debateID, pollID, question string) error {
+	store := debate.NewPollStore()
+	if _, err := store.GetPoll(debateID, pollID); err == nil {
+		return nil
+	} else if !errors.Is(err, debate.ErrPollNotFound) {
+		return err
+	}
+
+	if _, err := store.CreatePoll(debateID, pollID, question, motionOptions, debate.PollSettings{
+		Status:    debate.PollStatusOpen,
+		CreatedBy: "system",
+	}); errors.Is(err, debate.ErrPollExists) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	publishSpectatorEvent(debateID, "poll_created", debate.PollCreatedPayload{
+		PollID:    pollID,
+		Question:  question,
+		Options:   motionOptions,
+		Status:    debate.PollStatusOpen,
+		Timestamp: time.Now().Unix(),
+	})
+	return nil
+}
+
+// OpenPreDebateMotionPoll opens the audience's pre-debate vote on the motion
+func OpenPreDebateMotionPoll(debateID string) {
+	if err := openMotionPoll(debateID, MotionPrePollID, "Before the debate: where do you stand on the motion?"); err != nil {
+		log.Printf("Failed to open pre-debate motion poll: debate=%s err=%v", debateID, err)
+	}
+}
+
+// ClosePreDebateMotionPoll freezes the pre-debate vote once the opening speeches start
+func ClosePreDebateMotionPoll(debateID string) {
+	if _, err := closeDebatePoll(debateID, MotionPrePollID); err != nil && !errors.Is(err, debate.ErrPollNotFound) {
+		log.Printf("Failed to close pre-debate motion poll: debate=%s err=%v", debateID, err)
+	}
+}
+
+// OpenPostDebateMotionPoll opens the audience's post-debate vote on the motion
+func OpenPostDebateMotionPoll(debateID string) {
+	if err := openMotionPoll(debateID, MotionPostPollID, "After the debate: where do you stand on the motion?"); err != nil {
+		log.Printf("Failed to open post-debate motion poll: debate=%s err=%v", debateID, err)
+	}
+}
+
+// motionTally reads a motion poll's counts
+func motionTally(store debate.PollStore, debateID, pollID string) (models.MotionTally, error) {
+	if _, err := store.GetPoll(debateID, pollID); err != nil {
+		return models.MotionTally{}, err
+	}
+	counts, _, err := store.PollResults(debateID, pollID)
+	if err != nil {
+		return models.MotionTally{}, err
+	}
+	tally := models.MotionTally{
+		For:       counts[motionOptionFor],
+		Against:   counts[motionOptionAgainst],
+		Undecided: counts[motionOptionUndecided],
+	}
+	tally.Total = tally.For + tally.Against + tally.Undecided
+	return tally, nil
+}
+
+// share returns part as a percentage of total
+func share(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// ComputeSwing compares each side's share of the pre-debate and post-debate votes. The
+// side whose share grew more wins the swing.
+func ComputeSwing(pre, post models.MotionTally) models.AudienceSwing {
+	swingFor := math.Round((share(post.For, post.Total)-share(pre.For, pre.Total))*100) / 100
+	swingAgainst := math.Round((share(post.Against, post.Total)-share(pre.Against, pre.Total))*100) / 100
+	return models.AudienceSwing{
+		Pre:    pre,
+		Post:   post,
+		Swing:  map[string]float64{"for": swingFor, "against": swingAgainst},
+		Winner: winnerFromTotals(swingFor, swingAgainst),
+	}
+}
+
+// FinalizeAudienceSwing closes the post-debate vote and computes the swing. It returns
+// nil when the debate had no motion votes.
+func FinalizeAudienceSwing(debateID string) *models.AudienceSwing {
+	ClosePreDebateMotionPoll(debateID)
+	if _, err := closeDebatePoll(debateID, MotionPostPollID); err != nil {
+		return nil
+	}
+
+	store := debate.NewPollStore()
+	pre, err := motionTally(store, debateID, MotionPrePollID)
+	if err != nil {
+		return nil
+	}
+	post, err := motionTally(store, debateID, MotionPostPollID)
+	if err != nil || pre.Total == 0 || post.Total == 0 {
+		return nil
+	}
+	swing := ComputeSwing(pre, post)
+	return &swing
+}
+
+// FinishMotionVoting closes the motion polls when a debate finishes and records the
+// audience swing on the room. The swing is kept with the room because the polls' live
+// state is archived and expired once the debate ends, before the result is decided.
+func FinishMotionVoting(ctx context.Context, debateID string) {
+	swing := FinalizeAudienceSwing(debateID)
+	if swing == nil || db.MongoDatabase == nil {
+		return
+	}
+	update := bson.M{"$set": bson.M{"audienceSwing": swing}}
+	if _, err := db.MongoDatabase.Collection("rooms").UpdateOne(ctx, bson.M{"_id": debateID}, update); err != nil {
+		log.Printf("Failed to record audience swing: debate=%s err=%v", debateID, err)
+	}
+}
+
+// ApplyAudienceSwing attaches the audience swing to a judged result and, depending on the
+// room's winner source, lets it decide or contribute to the winner. The swing recorded when
+// the debate finished is used, or computed now if none was. The result is returned
+// unchanged when there was no motion vote or it is not JSON.
+func ApplyAudienceSwing(ctx context.Context, roomID, result string) string {
+	adjudication, _ := GetRoomAdjudication(ctx, roomID)
+	var swing *models.AudienceSwing
+	if adjudication != nil && adjudication.Swing != nil {
+		swing = adjudication.Swing
+	} else {
+		swing = FinalizeAudienceSwing(roomID)
+	}
+	if swing == nil {
+		return result
+	}
+
+	var judged map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &judged); err != nil {
+		return result
+	}
+
+	source := models.WinnerSourceJudge
+	if adjudication != nil {
+		source = NormalizeWinnerSource(adjudication.WinnerSource)
+	}
+	swingWeight := roomSwingWeight(adjudication)
+
+	judgeFor, judgeAgainst := sideTotals(judged)
+	judgeWinner := winnerFromTotals(judgeFor, judgeAgainst)
+	if verdict, ok := judged["verdict"].(map[string]interface{}); ok {
+		if w, ok := verdict["winner"].(string); ok && w != "" {
+			judgeWinner = w
+		}
+	}
+	winner := judgeWinner
+	switch source {
+	case models.WinnerSourceSwing:
+		winner = swing.Winner
+	case models.WinnerSourceMix:
+		winner = winnerFromTotals(mixMargin(judgeFor, judgeAgainst, *swing, swingWeight), 0)
+	}
+
+	judged["audience_swing"] = map[string]interface{}{
+		"winnerSource": source,
+		"swingWeight":  swingWeight,
+		"judgeWinner":  judgeWinner,
+		"swing":        swing,
+		"winner":       winner,
+	}
+	if source != models.WinnerSourceJudge {
+		verdict, ok := judged["verdict"].(map[string]interface{})
+		if !ok {
+			verdict = map[string]interface{}{}
+		}
+		verdict["winner"] = winner
+		judged["verdict"] = verdict
+	}
+
+	out, err := json.Marshal(judged)
+	if err != nil {
+		return result
+	}
+	return string(out)
+}
+
+// roomSwingWeight returns the share of the swing in mix mode. A weight of 0 is kept, so a
+// room can record the swing without letting it count; only an unset weight uses the default.
+func roomSwingWeight(adjudication *models.RoomAdjudication) float64 {
+	if adjudication == nil || adjudication.SwingWeight == nil {
+		return defaultSwingWeight
+	}
+	return *adjudication.SwingWeight
+}
+
+// mixMargin blends the judges' and the audience's margins for the for side. Both are
+// scaled to -1..1 so a 40 point judge scale and a percentage swing weigh comparably.
+func mixMargin(judgeFor, judgeAgainst float64, swing models.AudienceSwing, swingWeight float64) float64 {
+	judgeMargin := 0.0
+	if total := judgeFor + judgeAgainst; total > 0 {
+		judgeMargin = (judgeFor - judgeAgainst) / total
+	}
+	swingMargin := (swing.Swing["for"] - swing.Swing["against"]) / 200
+	return (1-swingWeight)*judgeMargin + swingWeight*swingMargin
+}
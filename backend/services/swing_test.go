@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+)
+
+func TestComputeSwing(t *testing.T) {
+	pre := models.MotionTally{For: 40, Against: 40, Undecided: 20, Total: 100}
+	post := models.MotionTally{For: 45, Against: 55, Undecided: 0, Total: 100}
+
+	swing := ComputeSwing(pre, post)
+	if swing.Swing["for"] != 5 || swing.Swing["against"] != 15 {
+		t.Fatalf("Expected swings of 5 and 15 points, got %v", swing.Swing)
+	}
+	// For still has fewer votes, but Against moved more of the audience
+	if swing.Winner != "Against" {
+		t.Errorf("Expected Against to win the swing, got %s", swing.Winner)
+	}
+}
+
+func TestMixMargin(t *testing.T) {
+	swing := models.AudienceSwing{Swing: map[string]float64{"for": -10, "against": 30}}
+
+	// The judges favour For narrowly; a heavy swing weight hands it to Against
+	if m := mixMargin(22, 18, swing, 0.2); m <= 0 {
+		t.Errorf("Expected the judges to decide at a low swing weight, got margin %v", m)
+	}
+	if m := mixMargin(22, 18, swing, 0.8); m >= 0 {
+		t.Errorf("Expected the swing to decide at a high swing weight, got margin %v", m)
+	}
+}
+
+func TestSwingWeight(t *testing.T) {
+	zero, tooMuch := 0.0, 1.5
+	if got := roomSwingWeight(&models.RoomAdjudication{SwingWeight: &zero}); got != 0 {
+		t.Errorf("Expected a swing weight of 0 to be kept, got %v", got)
+	}
+	if got := roomSwingWeight(&models.RoomAdjudication{}); got != defaultSwingWeight {
+		t.Errorf("Expected an unset swing weight to use the default, got %v", got)
+	}
+	if err := ValidateSwingWeight(&zero); err != nil {
+		t.Errorf("Expected a swing weight of 0 to be valid, got %v", err)
+	}
+	if err := ValidateSwingWeight(&tooMuch); err == nil {
+		t.Error("Expected a swing weight above 1 to be refused")
+	}
+}
+
+func TestAudienceSwingAttachedToResult(t *testing.T) {
+	debateID := "swing-test"
+	store := debate.NewPollStore()
+	vote := func(pollID string, votes map[string]int) {
+		for option, n := range votes {
+			for i := 0; i < n; i++ {
+				if _, err := store.Vote(debateID, pollID, option, fmt.Sprintf("%s-%s-%d", pollID, option, i)); err != nil {
+					t.Fatalf("Vote failed: %v", err)
+				}
+			}
+		}
+	}
+
+	OpenPreDebateMotionPoll(debateID)
+	vote(MotionPrePollID, map[string]int{"For": 6, "Against": 4})
+	ClosePreDebateMotionPoll(debateID)
+	OpenPostDebateMotionPoll(debateID)
+	vote(MotionPostPollID, map[string]int{"For": 3, "Against": 7})
+
+	result := DecideRoomResult(context.Background(), debateID, `{"verdict":{"winner":"For"},"total":{"for":30,"against":20}}`)
+	var judged map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &judged); err != nil {
+		t.Fatalf("Expected a JSON result, got %v", err)
+	}
+	swing, ok := judged["audience_swing"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected the audience swing in the result, got %s", result)
+	}
+	if swing["winner"] != "For" || swing["judgeWinner"] != "For" {
+		t.Errorf("Expected the judges to decide by default, got %v", swing)
+	}
+	if got := swing["swing"].(map[string]interface{})["winner"]; got != "Against" {
+		t.Errorf("Expected Against to win the swing, got %v", got)
+	}
+}
+
+func TestMotionPollsAreReserved(t *testing.T) {
+	debateID := "reserved-motion-test"
+	OpenPreDebateMotionPoll(debateID)
+	if _, err := debate.NewPollStore().Vote(debateID, MotionPrePollID, "For", "s1"); err != nil {
+		t.Fatalf("Vote failed: %v", err)
+	}
+
+	// Neither a repeated open nor a hand-made request touches the motion poll
+	OpenPreDebateMotionPoll(debateID)
+	if _, err := CloseDebatePoll(context.Background(), debateID, MotionPrePollID, "host"); !errors.Is(err, ErrPollReserved) {
+		t.Errorf("Expected closing a motion poll by hand to be refused, got %v", err)
+	}
+	if _, err := OpenDebatePoll(context.Background(), debateID, MotionPostPollID, "host"); !errors.Is(err, ErrPollReserved) {
+		t.Errorf("Expected opening a motion poll by hand to be refused, got %v", err)
+	}
+	counts, _, _ := debate.NewPollStore().PollResults(debateID, MotionPrePollID)
+	if meta, _ := debate.NewPollStore().GetPoll(debateID, MotionPrePollID); meta.Status != debate.PollStatusOpen || counts["For"] != 1 {
+		t.Errorf("Expected the pre-debate poll to stay open with its vote, got %s with %v", meta.Status, counts)
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TimeBank tracks chess-clock time for the two sides of a live debate. It is not safe
+// for concurrent use; callers guard it with their room lock.
+type TimeBank struct {
+	config    models.TimeBankConfig
+	remaining map[string]time.Duration
+	handicap  map[string]int
+
+	phase        string
+	activeSide   string
+	running      bool
+	cutOff       bool
+	segmentStart time.Time     // When the current running segment began
+	phaseElapsed time.Duration // Time spoken in the phase before the current segment
+}
+
+// NormalizeTimingMode returns a supported timing mode, defaulting to fixed phase timings
+func NormalizeTimingMode(mode string) string {
+	if strings.EqualFold(strings.TrimSpace(mode), models.TimingModeTimeBank) {
+		return models.TimingModeTimeBank
+	}
+	return models.TimingModeFixed
+}
+
+// ValidateTimeBankConfig checks a room's time bank settings
+func ValidateTimeBankConfig(cfg *models.TimeBankConfig) error {
+	if cfg == nil || cfg.TotalSeconds <= 0 {
+		return errors.New("timeBank.totalSeconds must be positive")
+	}
+	if cfg.IncrementSeconds < 0 || cfg.HandicapSecondsPer100 < 0 || cfg.MaxHandicapSeconds < 0 {
+		return errors.New("timeBank values cannot be negative")
+	}
+	for phase, seconds := range cfg.MinPhaseSeconds {
+		if seconds < 0 {
+			return errors.New("timeBank.minPhaseSeconds." + phase + " cannot be negative")
+		}
+	}
+	return nil
+}
+
+// ValidatePhaseSeconds checks a room's custom phase lengths
+func ValidatePhaseSeconds(phaseSeconds map[string]int) error {
+	for phase, seconds := range phaseSeconds {
+		if seconds <= 0 {
+			return errors.New("phaseSeconds." + phase + " must be positive")
+		}
+	}
+	return nil
+}
+
+// RatingHandicap returns the extra seconds granted to each side for a rating gap
+func RatingHandicap(cfg models.TimeBankConfig, forRating, againstRating int) map[string]int {
+	handicap := map[string]int{"for": 0, "against": 0}
+	if cfg.HandicapSecondsPer100 <= 0 || forRating == againstRating {
+		return handicap
+	}
+	gap := forRating - againstRating
+	lower := "for"
+	if gap > 0 {
+		lower = "against"
+	} else {
+		gap = -gap
+	}
+	extra := gap * cfg.HandicapSecondsPer100 / 100
+	if cfg.MaxHandicapSeconds > 0 && extra > cfg.MaxHandicapSeconds {
+		extra = cfg.MaxHandicapSeconds
+	}
+	handicap[lower] = extra
+	return handicap
+}
+
+// NewTimeBank creates banks for both sides, adding any rating handicap
+func NewTimeBank(cfg models.TimeBankConfig, forRating, againstRating int) *TimeBank {
+	handicap := RatingHandicap(cfg, forRating, againstRating)
+	total := time.Duration(cfg.TotalSeconds) * time.Second
+	return &TimeBank{
+		config: cfg,
+		remaining: map[string]time.Duration{
+			"for":     total + time.Duration(handicap["for"])*time.Second,
+			"against": total + time.Duration(handicap["against"])*time.Second,
+		},
+		handicap: handicap,
+	}
+}
+
+// settle charges the running segment to the active side
+func (b *TimeBank) settle(now time.Time) {
+	if !b.running || b.activeSide == "" {
+		return
+	}
+	spent := now.Sub(b.segmentStart)
+	b.remaining[b.activeSide] -= spent
+	if b.remaining[b.activeSide] < 0 {
+		b.remaining[b.activeSide] = 0
+	}
+	b.phaseElapsed += spent
+	b.segmentStart = now
+}
+
+// StartPhase settles the previous phase and starts the clock for side. Phases without a
+// speaking side stop the clock. Starting the phase already under way does nothing, so a
+// repeated phase change neither resets the phase nor adds another increment.
+func (b *TimeBank) StartPhase(phase, side string, now time.Time) {
+	if phase != "" && phase == b.phase {
+		return
+	}
+	b.settle(now)
+	b.phase = phase
+	b.activeSide = side
+	b.phaseElapsed = 0
+	b.cutOff = false
+	b.running = side != ""
+	b.segmentStart = now
+	if b.running && b.config.IncrementSeconds > 0 {
+		b.remaining[side] += time.Duration(b.config.IncrementSeconds) * time.Second
+	}
+}
+
+// Pause stops the clock without ending the phase
+func (b *TimeBank) Pause(now time.Time) {
+	b.settle(now)
+	b.running = false
+}
+
+// Resume restarts the clock for the current phase
+func (b *TimeBank) Resume(now time.Time) {
+	if b.activeSide == "" || b.cutOff {
+		return
+	}
+	b.running = true
+	b.segmentStart = now
+}
+
+// Stop settles the current phase and stops the clock
+func (b *TimeBank) Stop(now time.Time) {
+	b.settle(now)
+	b.running = false
+	b.activeSide = ""
+}
+
+// Running reports whether a side's clock is counting down
+func (b *TimeBank) Running() bool {
+	return b.running
+}
+
+// IsCutOff reports whether side ran out of time in the current phase
+func (b *TimeBank) IsCutOff(side string) bool {
+	return b.cutOff && b.activeSide == side
+}
+
+// ActiveSide returns the side whose clock is running
+func (b *TimeBank) ActiveSide() string {
+	return b.activeSide
+}
+
+// phaseMinimum is the speaking time guaranteed in the current phase
+func (b *TimeBank) phaseMinimum() time.Duration {
+	return time.Duration(b.config.MinPhaseSeconds[b.phase]) * time.Second
+}
+
+// UntilCutoff returns how long the active side can keep speaking. A side is only cut off
+// once its bank is empty and it has spoken for the phase minimum.
+func (b *TimeBank) UntilCutoff(now time.Time) time.Duration {
+	if !b.running {
+		return 0
+	}
+	spent := now.Sub(b.segmentStart)
+	left := b.remaining[b.activeSide] - spent
+	if untilMinimum := b.phaseMinimum() - (b.phaseElapsed + spent); untilMinimum > left {
+		left = untilMinimum
+	}
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// CanYield reports whether the active side has spoken for the phase minimum, after which
+// it may hand the floor on before its bank runs out
+func (b *TimeBank) CanYield(now time.Time) bool {
+	if b.activeSide == "" {
+		return false
+	}
+	elapsed := b.phaseElapsed
+	if b.running {
+		elapsed += now.Sub(b.segmentStart)
+	}
+	return elapsed >= b.phaseMinimum()
+}
+
+// CutOff stops the clock for a side that has run out of time
+func (b *TimeBank) CutOff(now time.Time) {
+	b.settle(now)
+	b.running = false
+	b.cutOff = true
+}
+
+// State returns the time banks as seen at now
+func (b *TimeBank) State(now time.Time) models.TimeBankState {
+	remaining := map[string]float64{}
+	for side, d := range b.remaining {
+		remaining[side] = d.Seconds()
+	}
+	elapsed := b.phaseElapsed
+	if b.running && b.activeSide != "" {
+		spent := now.Sub(b.segmentStart)
+		elapsed += spent
+		left := b.remaining[b.activeSide] - spent
+		if left < 0 {
+			left = 0
+		}
+		remaining[b.activeSide] = left.Seconds()
+	}
+	return models.TimeBankState{
+		Remaining:    remaining,
+		Handicap:     b.handicap,
+		ActiveSide:   b.activeSide,
+		Phase:        b.phase,
+		PhaseElapsed: elapsed.Seconds(),
+		PhaseMinimum: b.config.MinPhaseSeconds[b.phase],
+		Running:      b.running,
+		CutOff:       b.cutOff,
+	}
+}
+
+// GetRoomTiming loads a room's timing mode, time bank settings and phase lengths
+func GetRoomTiming(ctx context.Context, roomID string) (*models.RoomTiming, error) {
+	if db.MongoDatabase == nil {
+		return nil, errors.New("database not initialized")
+	}
+	var timing models.RoomTiming
+	if err := db.MongoDatabase.Collection("rooms").FindOne(ctx, bson.M{"_id": roomID}).Decode(&timing); err != nil {
+		return nil, err
+	}
+	timing.Mode = NormalizeTimingMode(timing.Mode)
+	return &timing, nil
+}
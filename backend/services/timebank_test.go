@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+)
+
+func TestRatingHandicap(t *testing.T) {
+	cfg := models.TimeBankConfig{TotalSeconds: 300, HandicapSecondsPer100: 10, MaxHandicapSeconds: 25}
+
+	handicap := RatingHandicap(cfg, 1200, 1350)
+	if handicap["for"] != 15 || handicap["against"] != 0 {
+		t.Errorf("Expected 15s for the lower-rated for side, got %v", handicap)
+	}
+	handicap = RatingHandicap(cfg, 1800, 1200)
+	if handicap["against"] != 25 {
+		t.Errorf("Expected handicap capped at 25s, got %v", handicap)
+	}
+}
+
+func TestTimeBankCutoff(t *testing.T) {
+	cfg := models.TimeBankConfig{
+		TotalSeconds:     60,
+		IncrementSeconds: 5,
+		MinPhaseSeconds:  map[string]int{"closingFor": 30},
+	}
+	bank := NewTimeBank(cfg, 1500, 1500)
+	start := time.Unix(0, 0)
+
+	bank.StartPhase("openingFor", "for", start)
+	if got := bank.UntilCutoff(start); got != 65*time.Second {
+		t.Fatalf("Expected 65s including increment, got %v", got)
+	}
+
+	// Pausing does not charge the speaker
+	bank.Pause(start.Add(20 * time.Second))
+	bank.Resume(start.Add(50 * time.Second))
+	if got := bank.UntilCutoff(start.Add(50 * time.Second)); got != 45*time.Second {
+		t.Errorf("Expected 45s left after a pause, got %v", got)
+	}
+
+	bank.StartPhase("openingAgainst", "against", start.Add(110*time.Second))
+	if got := bank.State(start.Add(110 * time.Second)).Remaining["for"]; got != 0 {
+		t.Errorf("Expected the for bank to be empty, got %v", got)
+	}
+
+	// An empty bank still gets the phase minimum
+	bank.StartPhase("closingFor", "for", start.Add(120*time.Second))
+	if got := bank.UntilCutoff(start.Add(120 * time.Second)); got != 30*time.Second {
+		t.Errorf("Expected the 30s phase minimum, got %v", got)
+	}
+	if bank.CanYield(start.Add(149 * time.Second)) {
+		t.Error("Expected the speaker not to yield before the phase minimum")
+	}
+	if !bank.CanYield(start.Add(150 * time.Second)) {
+		t.Error("Expected the speaker to yield once the phase minimum has passed")
+	}
+	bank.CutOff(start.Add(150 * time.Second))
+	if !bank.IsCutOff("for") || bank.Running() {
+		t.Error("Expected the for side to be cut off")
+	}
+}
+
+func TestTimeBankRepeatedPhaseStartIsIgnored(t *testing.T) {
+	cfg := models.TimeBankConfig{TotalSeconds: 60, IncrementSeconds: 5}
+	bank := NewTimeBank(cfg, 1500, 1500)
+	start := time.Unix(0, 0)
+
+	bank.StartPhase("openingFor", "for", start)
+	bank.StartPhase("openingFor", "for", start.Add(10*time.Second))
+	if got := bank.UntilCutoff(start.Add(10 * time.Second)); got != 55*time.Second {
+		t.Errorf("Expected 55s left with a single increment, got %v", got)
+	}
+
+	bank.CutOff(start.Add(70 * time.Second))
+	bank.StartPhase("openingFor", "for", start.Add(71*time.Second))
+	if !bank.IsCutOff("for") {
+		t.Error("Expected a repeated phase start to keep the side cut off")
+	}
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Limits on a bot debate turn
+const (
+	vsBotReplyWords          = 150  // Bound on a bot reply
+	maxVsBotTurnLength       = 5000 // Characters in a user's turn
+	maxVsBotContextLength    = 1000 // Characters of instructions for the bot's next reply
+	maxVsBotTurnsPerPhase    = 2    // Turns each side takes in a phase; cross-examination has two
+	vsBotUnableToJudgeResult = "Unable to judge."
+)
+
+// defaultVsBotPhases are the phases of a bot debate created without custom timings
+var defaultVsBotPhases = []string{"Opening Statements", "Cross-Examination", "Closing Statements"}
+
+var (
+	ErrVsBotDebateNotFound = errors.New("debate not found")
+	ErrVsBotDebateJudged   = errors.New("debate has already been judged")
+	ErrVsBotHistoryChanged = errors.New("another turn was saved first, reload the debate")
+)
+
+// VsBotTurnError explains why a turn does not fit the debate
+type VsBotTurnError struct {
+	Reason string
+}
+
+func (e *VsBotTurnError) Error() string {
+	return e.Reason
+}
+
+// VsBotTurn is what the user sends for a turn of a bot debate. Text is the user's turn and
+// may be empty when the bot speaks first; Context tells the bot what kind of reply is due.
+type VsBotTurn struct {
+	Phase   string
+	Text    string
+	Context string
+}
+
+// vsBotPhases returns the phase names of a debate in order
+func vsBotPhases(d *models.DebateVsBot) []string {
+	if len(d.PhaseTimings) == 0 {
+		return defaultVsBotPhases
+	}
+	phases := make([]string, len(d.PhaseTimings))
+	for i, pt := range d.PhaseTimings {
+		phases[i] = pt.Name
+	}
+	return phases
+}
+
+// vsBotPhaseIndex returns the position of phase in the debate, or -1
+func vsBotPhaseIndex(phases []string, phase string) int {
+	for i, name := range phases {
+		if name == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkVsBotTurn reports whether sender may take a turn in phase after history: the phase
+// must belong to the debate, the debate cannot go back to an earlier phase, and each side
+// takes a bounded number of turns per phase
+func checkVsBotTurn(phases []string, history []models.Message, sender, phase string) error {
+	index := vsBotPhaseIndex(phases, phase)
+	if index < 0 {
+		return &VsBotTurnError{Reason: fmt.Sprintf("%q is not a phase of this debate", phase)}
+	}
+	turns := 0
+	for _, msg := range history {
+		if vsBotPhaseIndex(phases, msg.Phase) > index {
+			return &VsBotTurnError{Reason: fmt.Sprintf("The debate has already moved past %s", phase)}
+		}
+		if msg.Phase == phase && msg.Sender == sender {
+			turns++
+		}
+	}
+	if turns >= maxVsBotTurnsPerPhase {
+		return &VsBotTurnError{Reason: fmt.Sprintf("%s has no turns left in %s", sender, phase)}
+	}
+	return nil
+}
+
+// checkVsBotJudgeable reports whether history gives the judge a debate to decide: both the
+// user and the bot must have taken a turn. A phase the user let run out without speaking
+// does not block judging, since empty turns are never stored.
+func checkVsBotJudgeable(history []models.Message) error {
+	userSpoke, botSpoke := false, false
+	for _, msg := range history {
+		switch msg.Sender {
+		case "User":
+			userSpoke = true
+		case "Bot":
+			botSpoke = true
+		}
+	}
+	if !userSpoke || !botSpoke {
+		return &VsBotTurnError{Reason: "The debate cannot be judged until both sides have spoken"}
+	}
+	return nil
+}
+
+// vsBotUserMessage validates the user's turn and returns it as a message
+func vsBotUserMessage(phases []string, history []models.Message, turn VsBotTurn) (models.Message, error) {
+	text := strings.TrimSpace(turn.Text)
+	if text == "" {
+		return models.Message{}, &VsBotTurnError{Reason: "Text is required"}
+	}
+	if utf8.RuneCountInString(text) > maxVsBotTurnLength {
+		return models.Message{}, &VsBotTurnError{Reason: fmt.Sprintf("Turns are limited to %d characters", maxVsBotTurnLength)}
+	}
+	if err := checkVsBotTurn(phases, history, "User", turn.Phase); err != nil {
+		return models.Message{}, err
+	}
+	return models.Message{Sender: "User", Text: text, Phase: turn.Phase}, nil
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// vsBotStance returns the stance the bot argues: the opposite of the user's
+func vsBotStance(userStance string) string {
+	if strings.EqualFold(userStance, "against") {
+		return "For"
+	}
+	return "Against"
+}
+
+// GetVsBotDebate returns one of the user's bot debates
+func GetVsBotDebate(ctx context.Context, debateID, email string) (*models.DebateVsBot, error) {
+	if db.DebateVsBotCollection == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	id, err := primitive.ObjectIDFromHex(debateID)
+	if err != nil {
+		return nil, ErrVsBotDebateNotFound
+	}
+	d, err := db.GetDebateVsBot(ctx, id, email)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrVsBotDebateNotFound
+	}
+	return d, err
+}
+
+// saveVsBotHistory stores the debate's history with messages added, failing with
+// ErrVsBotHistoryChanged when another turn or the verdict was saved since d was read
+func saveVsBotHistory(d *models.DebateVsBot, messages ...models.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	history := append(append([]models.Message{}, d.History...), messages...)
+	err := db.SetDebateVsBotHistory(ctx, d.ID, d.Email, len(d.History), history)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrVsBotHistoryChanged
+	}
+	return err
+}
+
+// AddVsBotUserTurn records a user's turn that the bot does not reply to, such as the
+// answer to the bot's question
+func AddVsBotUserTurn(ctx context.Context, debateID, email string, turn VsBotTurn) error {
+	d, err := GetVsBotDebate(ctx, debateID, email)
+	if err != nil {
+		return err
+	}
+	if d.Outcome != "" {
+		return ErrVsBotDebateJudged
+	}
+	msg, err := vsBotUserMessage(vsBotPhases(d), d.History, turn)
+	if err != nil {
+		return err
+	}
+	return saveVsBotHistory(d, msg)
+}
+
+// TakeVsBotTurn records the user's turn, if any, then generates the bot's reply from the
+// stored debate and records it too. Each piece of the reply is passed to onChunk as it is
+// written. Nothing is saved when ctx is cancelled before the reply is complete.
+func TakeVsBotTurn(ctx context.Context, debateID, email string, turn VsBotTurn, onChunk func(string)) (*models.DebateVsBot, string, error) {
+	d, err := GetVsBotDebate(ctx, debateID, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if d.Outcome != "" {
+		return nil, "", ErrVsBotDebateJudged
+	}
+
+	phases := vsBotPhases(d)
+	history := d.History
+	var added []models.Message
+	if turn.Text != "" {
+		msg, err := vsBotUserMessage(phases, history, turn)
+		if err != nil {
+			return nil, "", err
+		}
+		added = append(added, msg)
+		history = append(append([]models.Message{}, history...), msg)
+	}
+	if err := checkVsBotTurn(phases, history, "Bot", turn.Phase); err != nil {
+		return nil, "", err
+	}
+
+	extraContext := truncateRunes(strings.TrimSpace(turn.Context), maxVsBotContextLength)
+	reply := StreamBotResponse(ctx, d.BotName, d.BotLevel, d.Topic, history, vsBotStance(d.Stance), extraContext, vsBotReplyWords, onChunk)
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	added = append(added, models.Message{Sender: "Bot", Text: reply, Phase: turn.Phase})
+	if err := saveVsBotHistory(d, added...); err != nil {
+		return nil, "", err
+	}
+	d.History = append(d.History, added...)
+	return d, reply, nil
+}
+
+// JudgeVsBotDebate judges the stored history of one of the user's bot debates and records
+// the verdict. A debate is judged once, and only after both sides have spoken: later
+// calls return the recorded verdict, and judged reports whether this call recorded it.
+func JudgeVsBotDebate(ctx context.Context, debateID, email string) (d *models.DebateVsBot, result string, judged bool, err error) {
+	d, err = GetVsBotDebate(ctx, debateID, email)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if d.Outcome != "" {
+		return d, d.Outcome, false, nil
+	}
+	if err := checkVsBotJudgeable(d.History); err != nil {
+		return nil, "", false, err
+	}
+
+	result = JudgeDebate(ctx, d.History)
+	if result == vsBotUnableToJudgeResult {
+		// Left unrecorded so the debate can be judged again
+		return d, result, false, nil
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	recorded, err := db.SetDebateVsBotOutcome(saveCtx, d.ID, email, result)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if !recorded {
+		// Judged at the same time by another request, whose verdict stands
+		if stored, err := GetVsBotDebate(saveCtx, debateID, email); err == nil && stored.Outcome != "" {
+			return stored, stored.Outcome, false, nil
+		}
+	}
+	d.Outcome = result
+	return d, result, recorded, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestCheckVsBotTurn(t *testing.T) {
+	phases := vsBotPhases(&models.DebateVsBot{})
+	history := []models.Message{
+		{Sender: "User", Text: "Tea is better.", Phase: "Opening Statements"},
+		{Sender: "Bot", Text: "Coffee is better.", Phase: "Opening Statements"},
+		{Sender: "User", Text: "Why coffee?", Phase: "Cross-Examination"},
+	}
+
+	if err := checkVsBotTurn(phases, history, "Bot", "Cross-Examination"); err != nil {
+		t.Errorf("Expected the bot to answer in cross-examination, got %v", err)
+	}
+	if err := checkVsBotTurn(phases, history, "User", "Closing Statements"); err != nil {
+		t.Errorf("Expected the debate to move on to closing statements, got %v", err)
+	}
+
+	cases := []struct {
+		sender, phase, want string
+	}{
+		{"User", "Rebuttal", "not a phase"},
+		{"Bot", "Opening Statements", "moved past"},
+	}
+	for _, tc := range cases {
+		var turnErr *VsBotTurnError
+		err := checkVsBotTurn(phases, history, tc.sender, tc.phase)
+		if !errors.As(err, &turnErr) || !strings.Contains(turnErr.Reason, tc.want) {
+			t.Errorf("Expected %s in %s to be rejected with %q, got %v", tc.sender, tc.phase, tc.want, err)
+		}
+	}
+
+	history = append(history, models.Message{Sender: "User", Text: "And why not tea?", Phase: "Cross-Examination"})
+	if err := checkVsBotTurn(phases, history, "User", "Cross-Examination"); err == nil {
+		t.Error("Expected a third user turn in one phase to be rejected")
+	}
+}
+
+func TestCheckVsBotJudgeable(t *testing.T) {
+	history := []models.Message{{Sender: "User", Text: "Tea is better.", Phase: "Opening Statements"}}
+
+	var turnErr *VsBotTurnError
+	if err := checkVsBotJudgeable(nil); !errors.As(err, &turnErr) {
+		t.Errorf("Expected an empty debate to be refused, got %v", err)
+	}
+	if err := checkVsBotJudgeable(history); !errors.As(err, &turnErr) {
+		t.Errorf("Expected a single turn to be refused, got %v", err)
+	}
+	history = append(history, models.Message{Sender: "Bot", Text: "Coffee is better.", Phase: "Opening Statements"})
+	if err := checkVsBotJudgeable(history); err != nil {
+		t.Errorf("Expected a debate both sides spoke in to be judged, got %v", err)
+	}
+}
+
+func TestVsBotUserMessage(t *testing.T) {
+	d := &models.DebateVsBot{PhaseTimings: []models.PhaseTiming{{Name: "Opening"}, {Name: "Closing"}}}
+	phases := vsBotPhases(d)
+
+	msg, err := vsBotUserMessage(phases, nil, VsBotTurn{Phase: "Opening", Text: "  Tea is better.  "})
+	if err != nil {
+		t.Fatalf("Expected a valid turn, got %v", err)
+	}
+	if msg.Sender != "User" || msg.Text != "Tea is better." || msg.Phase != "Opening" {
+		t.Errorf("Unexpected message %+v", msg)
+	}
+
+	if _, err := vsBotUserMessage(phases, nil, VsBotTurn{Phase: "Opening Statements", Text: "Tea"}); err == nil {
+		t.Error("Expected a default phase to be rejected in a debate with custom phases")
+	}
+	if _, err := vsBotUserMessage(phases, nil, VsBotTurn{Phase: "Opening", Text: "   "}); err == nil {
+		t.Error("Expected an empty turn to be rejected")
+	}
+	long := strings.Repeat("é", maxVsBotTurnLength+1)
+	if _, err := vsBotUserMessage(phases, nil, VsBotTurn{Phase: "Opening", Text: long}); err == nil {
+		t.Error("Expected an overlong turn to be rejected")
+	}
+}
+
+func TestVsBotStance(t *testing.T) {
+	if got := vsBotStance("against"); got != "For" {
+		t.Errorf("Expected the bot to argue for, got %s", got)
+	}
+	if got := vsBotStance("for"); got != "Against" {
+		t.Errorf("Expected the bot to argue against, got %s", got)
+	}
+}
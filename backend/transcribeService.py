@@ -1,7 +1,10 @@
 # Python server code (FastAPI)
-from fastapi import FastAPI
+# Whisper-compatible transcription service used by the Go server when
+# transcription.provider is "whisper" (see config.prod.yml).
+from fastapi import FastAPI, File, Form, UploadFile
+import tempfile
 import whisper
-import json
+import os
 
 # Initialize the FastAPI app
 app = FastAPI()
@@ -20,3 +23,31 @@ async def batch_transcription(file_path: str):
         return {"transcription": transcription}
     except Exception as e:
         return {"error": str(e)}
+
+@app.post("/v1/audio/transcriptions")
+async def transcriptions(
+    file: UploadFile = File(...),
+    model_name: str = Form("whisper-1", alias="model"),
+    language: str = Form("en"),
+    response_format: str = Form("verbose_json"),
+):
+    """
+    OpenAI-compatible endpoint returning the text and timed segments of an uploaded chunk.
+    """
+    suffix = os.path.splitext(file.filename or "")[1] or ".webm"
+    with tempfile.NamedTemporaryFile(suffix=suffix, delete=False) as tmp:
+        tmp.write(await file.read())
+        path = tmp.name
+    try:
+        result = model.transcribe(path, language=language or None)
+        return {
+            "text": result["text"],
+            "segments": [
+                {"start": s["start"], "end": s["end"], "text": s["text"]}
+                for s in result.get("segments", [])
+            ],
+        }
+    except Exception as e:
+        return {"error": str(e)}
+    finally:
+        os.remove(path)
@@ -0,0 +1,287 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+)
+
+// Official seats a client can take in a debate room
+const (
+	seatJudge     = "judge"
+	seatModerator = "moderator"
+)
+
+// BallotPayload is the structured ballot a judge submits for one phase
+type BallotPayload struct {
+	Phase  string `json:"phase"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// ModeratorPayload carries the arguments of a moderator action
+type ModeratorPayload struct {
+	TargetUserID string   `json:"targetUserId,omitempty"`
+	Reason       string   `json:"reason,omitempty"`
+	DecisionMode string   `json:"decisionMode,omitempty"`
+	HumanWeight  *float64 `json:"humanWeight,omitempty"`
+	WinnerSource string   `json:"winnerSource,omitempty"`
+	SwingWeight  *float64 `json:"swingWeight,omitempty"`
+	// Only votes from logged-in spectators count
+	RequireLoggedInSpectators bool `json:"requireLoggedInSpectators,omitempty"`
+	// AI commentary for spectators
+	Commentary bool `json:"commentary,omitempty"`
+}
+
+// isDebater reports whether the client is one of the two debaters
+func (c *Client) isDebater() bool {
+	return !c.IsSpectator && c.Seat == ""
+}
+
+// officialRecipients returns the judges and moderators in the room
+func officialRecipients(room *Room) []*Client {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	out := make([]*Client, 0)
+	for _, cl := range room.Clients {
+		if cl.Seat != "" {
+			out = append(out, cl)
+		}
+	}
+	return out
+}
+
+// debateStarted reports whether the room has left setup
+func debateStarted(room *Room) bool {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	return room.CurrentPhase != "" || room.Ended
+}
+
+// sendSeatError reports a rejected judge or moderator action to its sender
+func sendSeatError(client *Client, action, reason string) {
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":   "seatError",
+		"action": action,
+		"error":  reason,
+	})
+}
+
+// handleJudgeBallot stores a judge's ballot for a phase. Ballots are only shared with
+// other officials so debaters cannot see scores while the debate is running.
+func handleJudgeBallot(room *Room, message Message, client *Client, roomID string) {
+	if client.Seat != seatJudge {
+		sendSeatError(client, message.Type, "Only judges can submit ballots")
+		return
+	}
+
+	var payload BallotPayload
+	if len(message.Extra) > 0 {
+		if err := json.Unmarshal(message.Extra, &payload); err != nil {
+			sendSeatError(client, message.Type, "Invalid ballot")
+			return
+		}
+	}
+	if payload.Phase == "" {
+		payload.Phase = message.Phase
+	}
+
+	ballot := models.JudgeBallot{
+		RoomID:    roomID,
+		JudgeID:   client.UserID,
+		JudgeName: client.Username,
+		Phase:     payload.Phase,
+		Score:     payload.Score,
+		Reason:    strings.TrimSpace(payload.Reason),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.SaveJudgeBallot(ctx, &ballot); err != nil {
+		sendSeatError(client, message.Type, err.Error())
+		return
+	}
+
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":   "ballotAccepted",
+		"ballot": ballot,
+	})
+
+	notice := map[string]interface{}{
+		"type":      "ballotSubmitted",
+		"judgeId":   client.UserID,
+		"judgeName": client.Username,
+		"phase":     ballot.Phase,
+	}
+	for _, r := range officialRecipients(room) {
+		if r == client {
+			continue
+		}
+		r.SafeWriteJSON(notice)
+	}
+}
+
+// handleModeratorAction applies clock, warning, end and decision-mode controls
+func handleModeratorAction(room *Room, message Message, client *Client, roomID string) {
+	if client.Seat != seatModerator {
+		sendSeatError(client, message.Type, "Only moderators can do this")
+		return
+	}
+
+	var payload ModeratorPayload
+	if len(message.Extra) > 0 {
+		if err := json.Unmarshal(message.Extra, &payload); err != nil {
+			sendSeatError(client, message.Type, "Invalid moderator payload")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var broadcast map[string]interface{}
+	switch message.Type {
+	case "pauseClock", "resumeClock":
+		paused := message.Type == "pauseClock"
+		room.Mutex.Lock()
+		if room.Ended {
+			room.Mutex.Unlock()
+			sendSeatError(client, message.Type, "Debate has ended")
+			return
+		}
+		room.Paused = paused
+		phase := room.CurrentPhase
+		pausePhaseTimerLocked(room, roomID, paused)
+		bankState := pauseTimeBankLocked(room, roomID, paused)
+		room.Mutex.Unlock()
+		broadcast = map[string]interface{}{
+			"type":      "clockStatus",
+			"paused":    paused,
+			"phase":     phase,
+			"by":        client.Username,
+			"timestamp": time.Now().Unix(),
+		}
+		if bankState != nil {
+			broadcast["timeBank"] = bankState
+		}
+
+	case "moderatorWarning":
+		reason := strings.TrimSpace(payload.Reason)
+		if reason == "" {
+			reason = strings.TrimSpace(message.Content)
+		}
+		if payload.TargetUserID == "" || reason == "" {
+			sendSeatError(client, message.Type, "targetUserId and reason are required")
+			return
+		}
+		room.Mutex.Lock()
+		phase := room.CurrentPhase
+		room.Mutex.Unlock()
+		warning := models.ModeratorWarning{
+			ModeratorID:   client.UserID,
+			ModeratorName: client.Username,
+			TargetUserID:  payload.TargetUserID,
+			Reason:        reason,
+			Phase:         phase,
+			IssuedAt:      time.Now(),
+		}
+		if err := services.RecordModeratorWarning(ctx, roomID, warning); err != nil {
+			log.Printf("[ws] failed to record moderator warning: room=%s err=%v", roomID, err)
+		}
+		broadcast = map[string]interface{}{
+			"type":    "moderatorWarning",
+			"warning": warning,
+		}
+
+	case "endDebate":
+		room.Mutex.Lock()
+		if room.Ended {
+			room.Mutex.Unlock()
+			return
+		}
+		room.Ended = true
+		room.Paused = false
+		stopTimeBankLocked(room)
+		stopPhaseTimerLocked(room)
+		phase := room.CurrentPhase
+		for _, cl := range room.Clients {
+			if cl.isDebater() {
+				cl.IsMuted = true
+			}
+		}
+		room.Mutex.Unlock()
+		if err := services.MarkRoomEnded(ctx, roomID, client.UserID); err != nil {
+			log.Printf("[ws] failed to mark room ended: room=%s err=%v", roomID, err)
+		}
+		services.FinishMotionVoting(ctx, roomID)
+		services.ArchiveEndedDebate(ctx, roomID)
+		broadcast = map[string]interface{}{
+			"type":      "debateEnded",
+			"phase":     phase,
+			"endedBy":   client.Username,
+			"reason":    strings.TrimSpace(payload.Reason),
+			"timestamp": time.Now().Unix(),
+		}
+
+	case "setDecisionMode":
+		if debateStarted(room) {
+			sendSeatError(client, message.Type, "The decision rules cannot change once the debate has started")
+			return
+		}
+		mode := services.NormalizeDecisionMode(payload.DecisionMode)
+		if err := services.SetRoomDecisionMode(ctx, roomID, mode, payload.HumanWeight); err != nil {
+			sendSeatError(client, message.Type, err.Error())
+			return
+		}
+		broadcast = map[string]interface{}{
+			"type":         "decisionMode",
+			"decisionMode": mode,
+			"humanWeight":  payload.HumanWeight,
+		}
+
+	case "setWinnerSource":
+		if debateStarted(room) {
+			sendSeatError(client, message.Type, "The decision rules cannot change once the debate has started")
+			return
+		}
+		source := services.NormalizeWinnerSource(payload.WinnerSource)
+		if err := services.SetRoomWinnerSource(ctx, roomID, source, payload.SwingWeight); err != nil {
+			sendSeatError(client, message.Type, err.Error())
+			return
+		}
+		broadcast = map[string]interface{}{
+			"type":         "winnerSource",
+			"winnerSource": source,
+			"swingWeight":  payload.SwingWeight,
+		}
+
+	case "setSpectatorPolicy":
+		if err := services.SetRequireLoggedInSpectators(ctx, roomID, payload.RequireLoggedInSpectators); err != nil {
+			sendSeatError(client, message.Type, err.Error())
+			return
+		}
+		broadcast = map[string]interface{}{
+			"type":                      "spectatorPolicy",
+			"requireLoggedInSpectators": payload.RequireLoggedInSpectators,
+		}
+
+	case "setCommentary":
+		if err := services.SetCommentaryEnabled(ctx, roomID, payload.Commentary); err != nil {
+			sendSeatError(client, message.Type, err.Error())
+			return
+		}
+		broadcast = map[string]interface{}{
+			"type":       "commentaryPolicy",
+			"commentary": payload.Commentary,
+		}
+	}
+
+	for _, r := range snapshotRecipients(room, nil) {
+		r.SafeWriteJSON(broadcast)
+	}
+}
@@ -0,0 +1,16 @@
+package websocket
+
+import "testing"
+
+func TestDecisionRulesAreFixedOnceTheDebateStarts(t *testing.T) {
+	room := &Room{timingLoaded: true, CurrentPhase: "openingFor"}
+	moderator := &Client{UserID: "u3", Role: seatModerator, Seat: seatModerator}
+	peer := joinTestRoom(t, room, moderator)
+
+	for _, action := range []string{"setDecisionMode", "setWinnerSource"} {
+		handleModeratorAction(room, Message{Type: action}, moderator, "room1")
+		if msg := readMessageOfType(t, peer, "seatError"); msg["action"] != action {
+			t.Errorf("Expected %s to be refused once the debate started, got %v", action, msg)
+		}
+	}
+}
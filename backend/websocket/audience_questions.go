@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+)
+
+const (
+	// phaseAudienceQuestions is the phase in which approved spectator questions are put to the debaters
+	phaseAudienceQuestions = "audienceQuestions"
+	// How many of the top approved questions are pushed when the phase starts
+	audienceQuestionsPerPhase = 3
+)
+
+// AudienceQuestionPayload identifies a spectator question and, for moderation, the decision
+type AudienceQuestionPayload struct {
+	QID    string `json:"qId"`
+	Action string `json:"action,omitempty"` // "approve" or "hide"
+}
+
+// canModerateQuestions reports whether a client may approve or hide spectator questions:
+// a seated moderator or the room host
+func canModerateQuestions(client *Client, roomID string) bool {
+	if client.Seat == seatModerator {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return services.CanTakeSeat(ctx, roomID, client.UserID, seatModerator)
+}
+
+// publishQuestionUpdate tells spectators about a change to a question in the Q&A queue
+func publishQuestionUpdate(roomID string, question models.AudienceQuestion) {
+	event, err := debate.NewEvent("question_updated", debate.QuestionUpdatedPayload{
+		QID:       question.ID,
+		Status:    question.Status,
+		Upvotes:   question.Upvotes,
+		Asked:     question.AskedAt != nil,
+		Timestamp: time.Now().Unix(),
+	})
+	if err != nil {
+		return
+	}
+	if err := debate.PublishEvent(roomID, event); err != nil {
+		log.Printf("[ws] failed to publish question update: room=%s question=%s err=%v", roomID, question.ID, err)
+	}
+}
+
+// publishApprovedQuestion puts a newly approved question in front of the audience. Until
+// then only its asker has seen it.
+func publishApprovedQuestion(roomID string, question models.AudienceQuestion) {
+	event, err := debate.NewEvent("question", debate.QuestionPayload{
+		QID:       question.ID,
+		Text:      question.Text,
+		Status:    question.Status,
+		Upvotes:   question.Upvotes,
+		Timestamp: question.CreatedAt.Unix(),
+	})
+	if err != nil {
+		return
+	}
+	if err := debate.PublishEvent(roomID, event); err != nil {
+		log.Printf("[ws] failed to publish approved question: room=%s question=%s err=%v", roomID, question.ID, err)
+	}
+}
+
+// parseAudienceQuestionPayload reads the question reference carried in a message
+func parseAudienceQuestionPayload(client *Client, message Message) (AudienceQuestionPayload, bool) {
+	var payload AudienceQuestionPayload
+	if len(message.Extra) > 0 {
+		_ = json.Unmarshal(message.Extra, &payload)
+	}
+	if payload.QID == "" {
+		sendSeatError(client, message.Type, "A question ID is required")
+		return payload, false
+	}
+	return payload, true
+}
+
+// handleModerateQuestion lets a moderator or the host approve or hide a spectator question.
+// Officials see the decision in the room; spectators see it through the debate stream.
+func handleModerateQuestion(room *Room, message Message, client *Client, roomID string) {
+	payload, ok := parseAudienceQuestionPayload(client, message)
+	if !ok {
+		return
+	}
+	if !canModerateQuestions(client, roomID) {
+		sendSeatError(client, message.Type, "Only a moderator or the host can moderate questions")
+		return
+	}
+
+	status := models.AudienceQuestionApproved
+	switch payload.Action {
+	case "approve":
+	case "hide":
+		status = models.AudienceQuestionHidden
+	default:
+		sendSeatError(client, message.Type, "Action must be approve or hide")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	question, err := services.ModerateAudienceQuestion(ctx, roomID, payload.QID, status, client.UserID)
+	if err != nil {
+		sendSeatError(client, message.Type, err.Error())
+		return
+	}
+
+	update := map[string]interface{}{
+		"type":      "audienceQuestionUpdated",
+		"question":  question,
+		"timestamp": time.Now().Unix(),
+	}
+	client.SafeWriteJSON(update)
+	for _, r := range officialRecipients(room) {
+		if r != client {
+			r.SafeWriteJSON(update)
+		}
+	}
+	if question.Status == models.AudienceQuestionApproved {
+		publishApprovedQuestion(roomID, *question)
+	} else {
+		publishQuestionUpdate(roomID, *question)
+	}
+}
+
+// handleQuestionAnswered marks a spectator question as answered so it is kept with the
+// debate record
+func handleQuestionAnswered(room *Room, message Message, client *Client, roomID string) {
+	payload, ok := parseAudienceQuestionPayload(client, message)
+	if !ok {
+		return
+	}
+	if !client.isDebater() && !canModerateQuestions(client, roomID) {
+		sendSeatError(client, message.Type, "Only debaters, moderators or the host can mark questions answered")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	question, err := services.MarkAudienceQuestionAnswered(ctx, roomID, payload.QID)
+	if err != nil {
+		sendSeatError(client, message.Type, err.Error())
+		return
+	}
+
+	update := map[string]interface{}{
+		"type":      "audienceQuestionUpdated",
+		"question":  question,
+		"timestamp": time.Now().Unix(),
+	}
+	for _, r := range snapshotRecipients(room, nil) {
+		r.SafeWriteJSON(update)
+	}
+	publishQuestionUpdate(roomID, *question)
+}
+
+// pushAudienceQuestions puts the top approved spectator questions to the debaters when the
+// audience question phase starts
+func pushAudienceQuestions(room *Room, roomID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	questions, err := services.TakeTopAudienceQuestions(ctx, roomID, audienceQuestionsPerPhase)
+	if err != nil {
+		log.Printf("[ws] failed to load audience questions: room=%s err=%v", roomID, err)
+		return
+	}
+
+	payload := map[string]interface{}{
+		"type":      "audienceQuestions",
+		"questions": questions,
+		"timestamp": time.Now().Unix(),
+	}
+	for _, r := range snapshotRecipients(room, nil) {
+		r.SafeWriteJSON(payload)
+	}
+	for _, question := range questions {
+		publishQuestionUpdate(roomID, question)
+	}
+}
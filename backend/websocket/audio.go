@@ -0,0 +1,165 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"arguehub/models"
+	"arguehub/services"
+)
+
+// AudioChunkPayload is a recorded audio chunk sent over the debate websocket.
+// Data is base64 encoded in JSON. The chunk is filed under the room's current phase;
+// Phase, when set, must name it.
+type AudioChunkPayload struct {
+	Phase    string `json:"phase"`
+	Seq      int    `json:"seq"`
+	OffsetMs int64  `json:"offsetMs"`
+	MimeType string `json:"mimeType"`
+	Data     []byte `json:"data"`
+}
+
+// handleAudioChunk stores a debater's audio chunk and shares the server transcript of it
+// with the room once the transcription backend returns
+func handleAudioChunk(room *Room, message Message, client *Client, roomID string) {
+	if !client.isDebater() {
+		return
+	}
+	if client.Role != "for" && client.Role != "against" {
+		client.SafeWriteJSON(map[string]interface{}{"type": "audioChunkError", "error": "Choose a side before recording"})
+		return
+	}
+
+	var payload AudioChunkPayload
+	if err := json.Unmarshal(message.Extra, &payload); err != nil {
+		client.SafeWriteJSON(map[string]interface{}{"type": "audioChunkError", "error": "Invalid audio chunk"})
+		return
+	}
+	if payload.Phase == "" {
+		payload.Phase = message.Phase
+	}
+
+	room.Mutex.Lock()
+	phase := floorPhaseLocked(room, client)
+	room.Mutex.Unlock()
+	if phase == "" {
+		client.SafeWriteJSON(map[string]interface{}{"type": "audioChunkError", "error": "You do not have the floor"})
+		return
+	}
+	if payload.Phase != "" && payload.Phase != phase {
+		client.SafeWriteJSON(map[string]interface{}{"type": "audioChunkError", "phase": payload.Phase, "seq": payload.Seq, "error": "The debate is no longer in this phase"})
+		return
+	}
+
+	chunk := &models.AudioChunk{
+		RoomID:   roomID,
+		UserID:   client.UserID,
+		Role:     client.Role,
+		Phase:    phase,
+		Seq:      payload.Seq,
+		OffsetMs: payload.OffsetMs,
+		MimeType: payload.MimeType,
+	}
+
+	// Transcription can take several seconds, so it runs on the transcription workers
+	err := services.QueueAudioChunk(chunk, payload.Data, func(err error) {
+		if err != nil {
+			sendAudioChunkError(client, chunk, err)
+			return
+		}
+		if chunk.Status != models.AudioStatusTranscribed {
+			return
+		}
+
+		response := map[string]interface{}{
+			"type":     "audioTranscript",
+			"userId":   client.UserID,
+			"username": client.Username,
+			"role":     client.Role,
+			"phase":    chunk.Phase,
+			"seq":      chunk.Seq,
+			"text":     chunk.Text,
+			"segments": chunk.Segments,
+		}
+		for _, r := range snapshotRecipients(room, nil) {
+			r.SafeWriteJSON(response)
+		}
+	})
+	if err != nil {
+		sendAudioChunkError(client, chunk, err)
+	}
+}
+
+// sendAudioChunkError tells a debater that one of their chunks was not transcribed
+func sendAudioChunkError(client *Client, chunk *models.AudioChunk, err error) {
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":  "audioChunkError",
+		"phase": chunk.Phase,
+		"seq":   chunk.Seq,
+		"error": err.Error(),
+	})
+}
+
+// floorPhaseLocked returns the current phase when client holds the floor in it, or "" when
+// it is not their turn to speak. The speaking side holds the floor, both sides do during
+// audience questions, and an accepted point of information hands it to its offerer. The
+// caller must hold room.Mutex.
+func floorPhaseLocked(room *Room, client *Client) string {
+	phase := room.CurrentPhase
+	if !client.isDebater() || room.Paused || room.Ended || phaseIndex(phase) < 0 || phase == phaseFinished {
+		return ""
+	}
+	if room.POI != nil && room.POI.Status == models.POIStatusAccepted {
+		if room.POI.OfferedBy == client.UserID {
+			return phase
+		}
+		return ""
+	}
+	if room.TimeBank != nil && room.TimeBank.IsCutOff(client.Role) {
+		return ""
+	}
+	if phase == phaseAudienceQuestions || turnForPhase(phase) == client.Role {
+		return phase
+	}
+	return ""
+}
+
+// DebaterFloor returns the side a user has taken in a live room and the phase they hold
+// the floor in. The phase is "" when it is not their turn to speak, and both are "" when
+// the user is not debating there.
+func DebaterFloor(roomID, userID string) (string, string) {
+	roomsMutex.Lock()
+	room := rooms[roomID]
+	roomsMutex.Unlock()
+	if room == nil || userID == "" {
+		return "", ""
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	for _, c := range room.Clients {
+		if c.UserID == userID && c.isDebater() && (c.Role == "for" || c.Role == "against") {
+			return c.Role, floorPhaseLocked(room, c)
+		}
+	}
+	return "", ""
+}
+
+// DebaterRole returns the side a user has taken in a live room, or "" when the user is not
+// debating there
+func DebaterRole(roomID, userID string) string {
+	roomsMutex.Lock()
+	room := rooms[roomID]
+	roomsMutex.Unlock()
+	if room == nil || userID == "" {
+		return ""
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	for _, c := range room.Clients {
+		if c.UserID == userID && c.isDebater() && (c.Role == "for" || c.Role == "against") {
+			return c.Role
+		}
+	}
+	return ""
+}
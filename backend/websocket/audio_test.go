@@ -0,0 +1,77 @@
+package websocket
+
+import (
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestRoleSelectionSetsDebaterRole(t *testing.T) {
+	room := &Room{}
+	debater := &Client{UserID: "u1", Username: "Ada"}
+	spectator := &Client{UserID: "u2", IsSpectator: true}
+	joinTestRoom(t, room, debater)
+	spectatorPeer := joinTestRoom(t, room, spectator)
+
+	roomsMutex.Lock()
+	rooms["room1"] = room
+	roomsMutex.Unlock()
+	t.Cleanup(func() {
+		roomsMutex.Lock()
+		delete(rooms, "room1")
+		roomsMutex.Unlock()
+	})
+
+	handleRoleSelection(room, debater.Conn, Message{Type: "roleSelection", Role: "against"}, "room1")
+	if msg := readMessageOfType(t, spectatorPeer, "roleSelection"); msg["role"] != "against" {
+		t.Errorf("Expected the role selection to be shared, got %v", msg["role"])
+	}
+	if role := DebaterRole("room1", "u1"); role != "against" {
+		t.Errorf("Expected against, got %q", role)
+	}
+
+	handleRoleSelection(room, debater.Conn, Message{Type: "roleSelection", Role: "judge"}, "room1")
+	handleRoleSelection(room, spectator.Conn, Message{Type: "roleSelection", Role: "for"}, "room1")
+	if role := DebaterRole("room1", "u1"); role != "against" {
+		t.Errorf("Expected an unknown role to be ignored, got %q", role)
+	}
+	if role := DebaterRole("room1", "u2"); role != "" {
+		t.Errorf("Expected a spectator to have no side, got %q", role)
+	}
+}
+
+func TestAudioFloorFollowsTheRoom(t *testing.T) {
+	forSide := &Client{UserID: "u1", Role: "for"}
+	againstSide := &Client{UserID: "u2", Role: "against"}
+	room := &Room{CurrentPhase: "openingFor"}
+
+	if phase := floorPhaseLocked(room, forSide); phase != "openingFor" {
+		t.Errorf("Expected the speaking side to hold the floor, got %q", phase)
+	}
+	if phase := floorPhaseLocked(room, againstSide); phase != "" {
+		t.Errorf("Expected the other side not to hold the floor, got %q", phase)
+	}
+
+	room.POI = &models.PointOfInformation{OfferedBy: "u2", Status: models.POIStatusAccepted}
+	if floorPhaseLocked(room, againstSide) != "openingFor" || floorPhaseLocked(room, forSide) != "" {
+		t.Error("Expected an accepted point of information to hand the floor to its offerer")
+	}
+	room.POI = nil
+
+	room.CurrentPhase = phaseAudienceQuestions
+	if floorPhaseLocked(room, forSide) == "" || floorPhaseLocked(room, againstSide) == "" {
+		t.Error("Expected both sides to hold the floor during audience questions")
+	}
+
+	room.CurrentPhase, room.Paused = "openingAgainst", true
+	if phase := floorPhaseLocked(room, againstSide); phase != "" {
+		t.Errorf("Expected nobody to hold the floor while paused, got %q", phase)
+	}
+	room.Paused = false
+	for _, phase := range []string{"", phaseFinished} {
+		room.CurrentPhase = phase
+		if got := floorPhaseLocked(room, forSide); got != "" {
+			t.Errorf("Expected no floor in phase %q, got %q", phase, got)
+		}
+	}
+}
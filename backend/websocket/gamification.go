@@ -4,23 +4,25 @@ import (
 	"log"
 	"sync"
 
+	"arguehub/internal/wsconn"
 	"arguehub/models"
 
 	"github.com/gorilla/websocket"
 )
 
+// gamificationHubMetrics tracks connections to the /ws/gamification hub
+var gamificationHubMetrics = wsconn.HubMetrics("/ws/gamification")
+
 // GamificationClient represents a client connected for gamification updates
 type GamificationClient struct {
 	Conn   *websocket.Conn
 	UserID string
-	writeMu sync.Mutex
+	ws     *wsconn.Conn
 }
 
-// SafeWriteJSON safely writes JSON data to the gamification client's WebSocket connection
+// SafeWriteJSON queues JSON data for the gamification client's WebSocket connection
 func (gc *GamificationClient) SafeWriteJSON(v interface{}) error {
-	gc.writeMu.Lock()
-	defer gc.writeMu.Unlock()
-	return gc.Conn.WriteJSON(v)
+	return gc.ws.WriteJSON(v)
 }
 
 // Global gamification hub for broadcasting events to all connected clients
@@ -42,7 +44,7 @@ func UnregisterGamificationClient(client *GamificationClient) {
 	gamificationMutex.Lock()
 	defer gamificationMutex.Unlock()
 	delete(gamificationClients, client)
-	client.Conn.Close()
+	client.ws.Close()
 	log.Printf("Gamification client unregistered. Total clients: %d", len(gamificationClients))
 }
 
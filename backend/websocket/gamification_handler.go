@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/wsconn"
 	"arguehub/models"
 	"arguehub/utils"
 
@@ -92,6 +93,7 @@ func GamificationWebSocketHandler(c *gin.Context) {
 	client := &GamificationClient{
 		Conn:   conn,
 		UserID: user.ID.Hex(),
+		ws:     wsconn.New(conn, gamificationHubMetrics, wsconn.DefaultConfig()),
 	}
 
 	// Register client
@@ -110,23 +112,15 @@ func GamificationWebSocketHandler(c *gin.Context) {
 		UnregisterGamificationClient(client)
 	}()
 
-	// Keep connection alive and handle incoming messages (ping/pong)
+	// Keep reading so heartbeats and close frames are processed; the connection
+	// answers pings and pings the client itself
 	for {
-		messageType, _, err := conn.ReadMessage()
-		if err != nil {
+		if _, _, err := client.ws.ReadMessage(); err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("Gamification WebSocket error: %v", err)
 			}
 			break
 		}
-
-		// Handle ping/pong for keepalive
-		if messageType == websocket.PingMessage {
-			if err := conn.WriteMessage(websocket.PongMessage, nil); err != nil {
-				log.Printf("Error writing pong: %v", err)
-				break
-			}
-		}
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/wsconn"
 	"arguehub/services"
 	"arguehub/utils"
 
@@ -25,13 +26,15 @@ var matchmakingUpgrader = websocket.Upgrader{
 	},
 }
 
+// matchmakingHubMetrics tracks connections to the /ws/matchmaking hub
+var matchmakingHubMetrics = wsconn.HubMetrics("/ws/matchmaking")
+
 // MatchmakingClient represents a client connected to the matchmaking WebSocket
 type MatchmakingClient struct {
-	conn     *websocket.Conn
+	conn     *wsconn.Conn
 	userID   string
 	username string
 	elo      int
-	send     chan []byte
 }
 
 // MatchmakingRoom manages matchmaking WebSocket connections
@@ -102,13 +105,16 @@ func MatchmakingHandler(c *gin.Context) {
 		return
 	}
 
+	// Matchmaking clients only send small control messages
+	cfg := wsconn.DefaultConfig()
+	cfg.MaxMessageSize = 512
+
 	// Create client
 	client := &MatchmakingClient{
-		conn:     conn,
+		conn:     wsconn.New(conn, matchmakingHubMetrics, cfg),
 		userID:   user.ID.Hex(),
 		username: user.DisplayName,
 		elo:      userRating,
-		send:     make(chan []byte, 256),
 	}
 
 	// Add client to room
@@ -120,7 +126,11 @@ func MatchmakingHandler(c *gin.Context) {
 	matchmakingService := services.GetMatchmakingService()
 	err = matchmakingService.AddToPool(user.ID.Hex(), user.DisplayName, userRating)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Failed to join matchmaking")
+		client.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"error","error":"Failed to join matchmaking"}`))
+		client.conn.Close()
+		matchmakingRoom.mutex.Lock()
+		delete(matchmakingRoom.clients, client)
+		matchmakingRoom.mutex.Unlock()
 		return
 	}
 
@@ -130,9 +140,8 @@ func MatchmakingHandler(c *gin.Context) {
 	// Set up room creation callback if not already set
 	services.SetRoomCreatedCallback(BroadcastRoomCreated)
 
-	// Start goroutines for reading and writing
-	go client.writePump()
-	go client.readPump()
+	// Read until the client disconnects; writes are handled by the connection's writer
+	client.readPump()
 }
 
 // readPump handles incoming messages from the client
@@ -149,13 +158,6 @@ func (c *MatchmakingClient) readPump() {
 		sendPoolStatus()
 	}()
 
-	c.conn.SetReadLimit(512)
-	c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	c.conn.SetPongHandler(func(string) error {
-		c.conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		return nil
-	})
-
 	for {
 		_, message, err := c.conn.ReadMessage()
 		if err != nil {
@@ -177,10 +179,10 @@ func (c *MatchmakingClient) readPump() {
 			matchmakingService := services.GetMatchmakingService()
 			err := matchmakingService.StartMatchmaking(c.userID)
 			if err != nil {
-				c.send <- []byte(fmt.Sprintf(`{"type":"error","error":"Failed to start matchmaking: %v"}`, err))
+				c.conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"type":"error","error":"Failed to start matchmaking: %v"}`, err)))
 			} else {
 				// Send confirmation to user
-				c.send <- []byte(`{"type":"matchmaking_started"}`)
+				c.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"matchmaking_started"}`))
 				sendPoolStatus()
 			}
 
@@ -189,7 +191,7 @@ func (c *MatchmakingClient) readPump() {
 			matchmakingService := services.GetMatchmakingService()
 			matchmakingService.RemoveFromPool(c.userID)
 			// Send confirmation to user
-			c.send <- []byte(`{"type":"matchmaking_stopped"}`)
+			c.conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"matchmaking_stopped"}`))
 			sendPoolStatus()
 
 		case "update_activity":
@@ -204,41 +206,6 @@ func (c *MatchmakingClient) readPump() {
 	}
 }
 
-// writePump handles outgoing messages to the client
-func (c *MatchmakingClient) writePump() {
-	ticker := time.NewTicker(54 * time.Second)
-	defer func() {
-		ticker.Stop()
-		c.conn.Close()
-	}()
-
-	for {
-		select {
-		case message, ok := <-c.send:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
-			}
-
-			w, err := c.conn.NextWriter(websocket.TextMessage)
-			if err != nil {
-				return
-			}
-			w.Write(message)
-
-			if err := w.Close(); err != nil {
-				return
-			}
-		case <-ticker.C:
-			c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				return
-			}
-		}
-	}
-}
-
 // sendPoolStatus broadcasts the current matchmaking pool status to all clients
 func sendPoolStatus() {
 	matchmakingService := services.GetMatchmakingService()
@@ -261,10 +228,7 @@ func sendPoolStatus() {
 
 	matchmakingRoom.mutex.Lock()
 	for client := range matchmakingRoom.clients {
-		select {
-		case client.send <- messageData:
-		default:
-			close(client.send)
+		if err := client.conn.WriteMessage(websocket.TextMessage, messageData); err != nil {
 			delete(matchmakingRoom.clients, client)
 		}
 	}
@@ -288,10 +252,7 @@ func BroadcastRoomCreated(roomID string, participantUserIDs []string) {
 		// Only send to participants of the room
 		for _, userID := range participantUserIDs {
 			if client.userID == userID {
-				select {
-				case client.send <- messageData:
-				default:
-					close(client.send)
+				if err := client.conn.WriteMessage(websocket.TextMessage, messageData); err != nil {
 					delete(matchmakingRoom.clients, client)
 				}
 				break
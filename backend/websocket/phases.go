@@ -0,0 +1,115 @@
+package websocket
+
+import (
+	"time"
+)
+
+// phaseFinished is the phase a debate is in once every speaking phase has run
+const phaseFinished = "finished"
+
+// debatePhaseOrder is the order the server runs a debate's phases in
+var debatePhaseOrder = []string{
+	"openingFor",
+	"openingAgainst",
+	"crossForQuestion",
+	"crossAgainstAnswer",
+	"crossAgainstQuestion",
+	"crossForAnswer",
+	phaseAudienceQuestions,
+	"closingFor",
+	"closingAgainst",
+	phaseFinished,
+}
+
+// phaseDurations is how long each phase runs before the server moves the debate on, unless
+// the room sets its own phaseSeconds. Phases missing from the map, such as phaseFinished,
+// do not end on their own.
+var phaseDurations = map[string]time.Duration{
+	"openingFor":           30 * time.Second,
+	"openingAgainst":       30 * time.Second,
+	"crossForQuestion":     30 * time.Second,
+	"crossAgainstAnswer":   30 * time.Second,
+	"crossAgainstQuestion": 30 * time.Second,
+	"crossForAnswer":       30 * time.Second,
+	phaseAudienceQuestions: 2 * time.Minute,
+	"closingFor":           30 * time.Second,
+	"closingAgainst":       30 * time.Second,
+}
+
+// phaseDurationLocked returns how long phase runs in room, falling back to phaseDurations
+// for phases the room does not time itself. The caller must hold room.Mutex.
+func phaseDurationLocked(room *Room, phase string) time.Duration {
+	if d, ok := room.phaseLengths[phase]; ok {
+		return d
+	}
+	return phaseDurations[phase]
+}
+
+// phaseIndex returns the position of phase in the debate, or -1 for setup and unknown phases
+func phaseIndex(phase string) int {
+	for i, name := range debatePhaseOrder {
+		if name == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextPhase returns the phase after phase, or "" once the debate has finished
+func nextPhase(phase string) string {
+	i := phaseIndex(phase)
+	if i+1 >= len(debatePhaseOrder) {
+		return ""
+	}
+	return debatePhaseOrder[i+1]
+}
+
+// schedulePhaseLocked arms the timer that ends phase after d. The caller must hold
+// room.Mutex.
+func schedulePhaseLocked(room *Room, roomID, phase string, d time.Duration) {
+	stopPhaseTimerLocked(room)
+	if d <= 0 {
+		return
+	}
+	room.phaseDeadline = time.Now().Add(d)
+	room.phaseTimer = time.AfterFunc(d, func() {
+		room.Mutex.Lock()
+		current := room.CurrentPhase == phase && !room.Paused && !room.Ended
+		if current {
+			room.phaseTimer = nil
+		}
+		room.Mutex.Unlock()
+		if current {
+			advancePhase(room, roomID, nextPhase(phase))
+		}
+	})
+}
+
+// stopPhaseTimerLocked stops the phase timer. The caller must hold room.Mutex.
+func stopPhaseTimerLocked(room *Room) {
+	if room.phaseTimer != nil {
+		room.phaseTimer.Stop()
+		room.phaseTimer = nil
+	}
+	room.phaseRemaining = 0
+}
+
+// pausePhaseTimerLocked holds the phase timer while a moderator has the clock paused and
+// rearms it with the time that was left on resume. The caller must hold room.Mutex.
+func pausePhaseTimerLocked(room *Room, roomID string, paused bool) {
+	if paused {
+		if room.phaseTimer == nil {
+			return
+		}
+		remaining := time.Until(room.phaseDeadline)
+		stopPhaseTimerLocked(room)
+		if remaining <= 0 {
+			remaining = time.Millisecond
+		}
+		room.phaseRemaining = remaining
+		return
+	}
+	if room.phaseRemaining > 0 {
+		schedulePhaseLocked(room, roomID, room.CurrentPhase, room.phaseRemaining)
+	}
+}
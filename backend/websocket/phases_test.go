@@ -0,0 +1,151 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+)
+
+func TestOnlyTheHostChangesPhase(t *testing.T) {
+	room := &Room{timingLoaded: true}
+	host := &Client{UserID: "u1", Role: "for", IsHost: true}
+	guest := &Client{UserID: "u2", Role: "against"}
+	moderator := &Client{UserID: "u3", Role: seatModerator, Seat: seatModerator}
+	hostPeer := joinTestRoom(t, room, host)
+	guestPeer := joinTestRoom(t, room, guest)
+	joinTestRoom(t, room, moderator)
+	t.Cleanup(func() {
+		room.Mutex.Lock()
+		stopPhaseTimerLocked(room)
+		room.Mutex.Unlock()
+	})
+
+	handlePhaseChange(room, Message{Type: "phaseChange", Phase: "openingFor"}, guest, "room1")
+	if msg := readMessageOfType(t, guestPeer, "phaseError"); msg["error"] != "Only the host or a moderator can change the phase" {
+		t.Errorf("Expected the guest to be refused, got %v", msg["error"])
+	}
+	if room.CurrentPhase != "" {
+		t.Errorf("Expected the debate not to start, got %s", room.CurrentPhase)
+	}
+
+	handlePhaseChange(room, Message{Type: "phaseChange", Phase: "openingFor"}, host, "room1")
+	if msg := readMessageOfType(t, hostPeer, "phaseChange"); msg["phase"] != "openingFor" {
+		t.Errorf("Expected the host to hear the phase change, got %v", msg["phase"])
+	}
+	if msg := readMessageOfType(t, guestPeer, "phaseChange"); msg["phase"] != "openingFor" {
+		t.Errorf("Expected the guest to follow the host, got %v", msg["phase"])
+	}
+
+	// Phases are taken one at a time, never skipped or repeated
+	handlePhaseChange(room, Message{Type: "phaseChange", Phase: "closingAgainst"}, host, "room1")
+	if msg := readMessageOfType(t, hostPeer, "phaseError"); msg["nextPhase"] != "openingAgainst" {
+		t.Errorf("Expected the host to be pointed at openingAgainst, got %v", msg["nextPhase"])
+	}
+	handlePhaseChange(room, Message{Type: "phaseChange", Phase: "openingFor"}, host, "room1")
+	if room.CurrentPhase != "openingFor" {
+		t.Errorf("Expected the debate to stay in openingFor, got %s", room.CurrentPhase)
+	}
+
+	handlePhaseChange(room, Message{Type: "phaseChange", Phase: "openingAgainst"}, moderator, "room1")
+	if room.CurrentPhase != "openingAgainst" {
+		t.Errorf("Expected a moderator to move the debate on, got %s", room.CurrentPhase)
+	}
+}
+
+func TestTimeBankPhasesEndOnTheClock(t *testing.T) {
+	cfg := models.TimeBankConfig{TotalSeconds: 60, MinPhaseSeconds: map[string]int{"openingAgainst": 3600}, HandicapSecondsPer100: 10}
+	room := &Room{timingLoaded: true, timeBankConfig: &cfg}
+	forSide := &Client{UserID: "u1", Role: "against", Elo: 1500}
+	againstSide := &Client{UserID: "u2", Elo: 1300}
+	peer := joinTestRoom(t, room, forSide)
+	joinTestRoom(t, room, againstSide)
+	t.Cleanup(func() {
+		room.Mutex.Lock()
+		stopTimeBankLocked(room)
+		stopPhaseTimerLocked(room)
+		room.Mutex.Unlock()
+	})
+
+	// Sides chosen before the debate starts decide the handicap
+	handleRoleSelection(room, forSide.Conn, Message{Type: "roleSelection", Role: "for"}, "room1")
+	handleRoleSelection(room, againstSide.Conn, Message{Type: "roleSelection", Role: "against"}, "room1")
+
+	advancePhase(room, "room1", "openingFor")
+	room.Mutex.Lock()
+	armed := room.phaseTimer != nil
+	handicap := room.TimeBank.State(time.Now()).Handicap
+	room.Mutex.Unlock()
+	if armed {
+		t.Error("Expected a time bank speech not to run on the fixed phase timer")
+	}
+	if handicap["against"] != 20 || handicap["for"] != 0 {
+		t.Errorf("Expected the lower-rated against side to get 20 seconds, got %v", handicap)
+	}
+
+	handleRoleSelection(room, forSide.Conn, Message{Type: "roleSelection", Role: "against"}, "room1")
+	if forSide.Role != "for" {
+		t.Errorf("Expected sides to be fixed once the debate starts, got %s", forSide.Role)
+	}
+
+	handleYieldFloor(room, againstSide, "room1")
+	if room.CurrentPhase != "openingFor" {
+		t.Errorf("Expected only the speaking side to yield, got %s", room.CurrentPhase)
+	}
+	handleYieldFloor(room, forSide, "room1")
+	if msg := readMessageOfType(t, peer, "phaseChange"); msg["phase"] != "openingFor" {
+		t.Errorf("Expected openingFor first, got %v", msg["phase"])
+	}
+	if msg := readMessageOfType(t, peer, "phaseChange"); msg["phase"] != "openingAgainst" {
+		t.Errorf("Expected the yield to move the debate on, got %v", msg["phase"])
+	}
+
+	// A side that has not spoken for the phase minimum keeps the floor
+	handleYieldFloor(room, againstSide, "room1")
+	if room.CurrentPhase != "openingAgainst" {
+		t.Errorf("Expected the yield to wait for the phase minimum, got %s", room.CurrentPhase)
+	}
+}
+
+func TestPhaseTimerAdvancesTheDebate(t *testing.T) {
+	// The room's own phase lengths replace the defaults
+	room := &Room{timingLoaded: true, phaseLengths: map[string]time.Duration{"openingFor": 20 * time.Millisecond, "openingAgainst": time.Hour}}
+	peer := joinTestRoom(t, room, &Client{UserID: "u1", Role: "for"})
+	t.Cleanup(func() {
+		room.Mutex.Lock()
+		stopPhaseTimerLocked(room)
+		room.Mutex.Unlock()
+	})
+
+	advancePhase(room, "room1", "openingFor")
+	readMessageOfType(t, peer, "phaseChange")
+	if msg := readMessageOfType(t, peer, "phaseChange"); msg["phase"] != "openingAgainst" {
+		t.Errorf("Expected the timer to move on to openingAgainst, got %v", msg["phase"])
+	}
+
+	// A paused clock holds the phase until it is resumed
+	room.Mutex.Lock()
+	room.Paused = true
+	pausePhaseTimerLocked(room, "room1", true)
+	if room.phaseTimer != nil || room.phaseRemaining <= 0 {
+		t.Errorf("Expected the phase timer to be held, got remaining %v", room.phaseRemaining)
+	}
+	room.Paused = false
+	pausePhaseTimerLocked(room, "room1", false)
+	if room.phaseTimer == nil {
+		t.Error("Expected the phase timer to be rearmed on resume")
+	}
+	room.Mutex.Unlock()
+}
+
+func TestNextPhase(t *testing.T) {
+	if got := nextPhase(""); got != "openingFor" {
+		t.Errorf("Expected the debate to start with openingFor, got %s", got)
+	}
+	if got := nextPhase("crossForAnswer"); got != phaseAudienceQuestions {
+		t.Errorf("Expected audience questions after cross-examination, got %s", got)
+	}
+	if got := nextPhase(phaseFinished); got != "" {
+		t.Errorf("Expected nothing after the debate finishes, got %s", got)
+	}
+}
@@ -0,0 +1,306 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// How long the speaker has to accept or decline a point of information
+	poiResponseWindow = 10 * time.Second
+	// How long an accepted point of information holds the floor
+	poiFloorDuration = 15 * time.Second
+)
+
+// poiPhases are the speeches in which the non-speaking side may offer a point of information.
+// Cross examination is already a question and answer exchange, so it is excluded.
+var poiPhases = map[string]bool{
+	"openingFor":     true,
+	"openingAgainst": true,
+	"closingFor":     true,
+	"closingAgainst": true,
+}
+
+// POIResponsePayload is the speaker's answer to a point of information
+type POIResponsePayload struct {
+	POIID  string `json:"poiId"`
+	Accept bool   `json:"accept"`
+}
+
+// muteNotice is an autoMuteStatus message queued for one debater
+type muteNotice struct {
+	client  *Client
+	payload map[string]interface{}
+}
+
+// sendPOIError reports a rejected point of information action to its sender
+func sendPOIError(client *Client, action, reason string) {
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":   "poiError",
+		"action": action,
+		"error":  reason,
+	})
+}
+
+// broadcastPOI sends a point of information update to everyone in the room
+func broadcastPOI(room *Room, eventType string, poi models.PointOfInformation, extra map[string]interface{}) {
+	payload := map[string]interface{}{
+		"type":      eventType,
+		"poi":       poi,
+		"timestamp": time.Now().Unix(),
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	for _, r := range snapshotRecipients(room, nil) {
+		r.SafeWriteJSON(payload)
+	}
+}
+
+// recordPointOfInformation persists a point of information so it reaches the transcript and judge
+func recordPointOfInformation(roomID string, poi models.PointOfInformation) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := services.RecordPointOfInformation(ctx, poi); err != nil {
+		log.Printf("[ws] failed to record point of information: room=%s poi=%s err=%v", roomID, poi.ID, err)
+	}
+}
+
+// applyFloorLocked mutes every debater except the floor holder. With no holder the floor
+// follows the current turn. The caller must hold room.Mutex.
+func applyFloorLocked(room *Room, turn, holderID string) []muteNotice {
+	notices := make([]muteNotice, 0, 2)
+	for _, cl := range room.Clients {
+		if cl.Role == "" || !cl.isDebater() {
+			continue
+		}
+		muted := cl.Role != turn
+		if holderID != "" {
+			muted = cl.UserID != holderID
+		} else if room.TimeBank != nil && room.TimeBank.IsCutOff(cl.Role) {
+			// A side that ran out of time stays muted when a point of information ends
+			muted = true
+		}
+		cl.IsMuted = muted
+		notices = append(notices, muteNotice{client: cl, payload: map[string]interface{}{
+			"type":        "autoMuteStatus",
+			"userId":      cl.UserID,
+			"username":    cl.Username,
+			"isMuted":     muted,
+			"currentTurn": turn,
+			"phase":       room.CurrentPhase,
+			"floorHolder": holderID,
+		}})
+	}
+	return notices
+}
+
+// sendMuteNotices delivers queued autoMuteStatus messages
+func sendMuteNotices(notices []muteNotice) {
+	for _, n := range notices {
+		n.client.SafeWriteJSON(n.payload)
+	}
+}
+
+// handlePOIRequest lets a debater on the non-speaking side offer a point of information
+func handlePOIRequest(room *Room, message Message, client *Client, roomID string) {
+	if !client.isDebater() || client.Role == "" {
+		sendPOIError(client, message.Type, "Only debaters can offer points of information")
+		return
+	}
+
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
+	turn := turnForPhase(phase)
+	var reason string
+	switch {
+	case room.Paused || room.Ended:
+		reason = "The debate clock is not running"
+	case !poiPhases[phase]:
+		reason = "Points of information are not allowed in this phase"
+	case client.Role == turn:
+		reason = "The speaker cannot offer a point of information"
+	case room.POI != nil:
+		reason = "Another point of information is in progress"
+	}
+	if reason != "" {
+		room.Mutex.Unlock()
+		sendPOIError(client, message.Type, reason)
+		return
+	}
+
+	poi := &models.PointOfInformation{
+		ID:            uuid.New().String(),
+		RoomID:        roomID,
+		Phase:         phase,
+		OfferedBy:     client.UserID,
+		OfferedByName: client.Username,
+		OffererRole:   client.Role,
+		Content:       strings.TrimSpace(message.Content),
+		Status:        models.POIStatusPending,
+		RequestedAt:   time.Now(),
+	}
+	for _, cl := range room.Clients {
+		if cl.isDebater() && cl.Role == turn {
+			poi.SpeakerID = cl.UserID
+			poi.SpeakerName = cl.Username
+			break
+		}
+	}
+	room.POI = poi
+	poiID := poi.ID
+	room.poiTimer = time.AfterFunc(poiResponseWindow, func() {
+		resolvePointOfInformation(room, roomID, poiID, models.POIStatusExpired)
+	})
+	snapshot := *poi
+	room.Mutex.Unlock()
+
+	recordPointOfInformation(roomID, snapshot)
+	broadcastPOI(room, "poiRequest", snapshot, map[string]interface{}{
+		"responseSeconds": int(poiResponseWindow.Seconds()),
+	})
+}
+
+// handlePOIResponse lets the speaker accept or decline a pending point of information.
+// Accepting hands the floor to the offerer until the floor timer runs out.
+func handlePOIResponse(room *Room, message Message, client *Client, roomID string) {
+	var payload POIResponsePayload
+	if len(message.Extra) > 0 {
+		if err := json.Unmarshal(message.Extra, &payload); err != nil {
+			sendPOIError(client, message.Type, "Invalid point of information response")
+			return
+		}
+	}
+
+	room.Mutex.Lock()
+	poi := room.POI
+	if poi == nil || poi.Status != models.POIStatusPending || (payload.POIID != "" && payload.POIID != poi.ID) {
+		room.Mutex.Unlock()
+		sendPOIError(client, message.Type, "No pending point of information")
+		return
+	}
+	if !client.isDebater() || client.Role != turnForPhase(room.CurrentPhase) {
+		room.Mutex.Unlock()
+		sendPOIError(client, message.Type, "Only the speaker can respond to a point of information")
+		return
+	}
+
+	if room.poiTimer != nil {
+		room.poiTimer.Stop()
+		room.poiTimer = nil
+	}
+	now := time.Now()
+	poi.RespondedAt = &now
+	poi.SpeakerID = client.UserID
+	poi.SpeakerName = client.Username
+
+	if !payload.Accept {
+		poi.Status = models.POIStatusDeclined
+		room.POI = nil
+		snapshot := *poi
+		room.Mutex.Unlock()
+
+		recordPointOfInformation(roomID, snapshot)
+		broadcastPOI(room, "poiDeclined", snapshot, nil)
+		return
+	}
+
+	poi.Status = models.POIStatusAccepted
+	poiID := poi.ID
+	room.poiTimer = time.AfterFunc(poiFloorDuration, func() {
+		resolvePointOfInformation(room, roomID, poiID, models.POIStatusEnded)
+	})
+	notices := applyFloorLocked(room, turnForPhase(room.CurrentPhase), poi.OfferedBy)
+	snapshot := *poi
+	room.Mutex.Unlock()
+
+	recordPointOfInformation(roomID, snapshot)
+	sendMuteNotices(notices)
+	broadcastPOI(room, "poiAccepted", snapshot, map[string]interface{}{
+		"durationSeconds": int(poiFloorDuration.Seconds()),
+	})
+}
+
+// handlePOIEnd returns the floor to the speaker before the point of information timer runs out
+func handlePOIEnd(room *Room, message Message, client *Client, roomID string) {
+	room.Mutex.Lock()
+	poi := room.POI
+	if poi == nil || poi.Status != models.POIStatusAccepted {
+		room.Mutex.Unlock()
+		sendPOIError(client, message.Type, "No point of information holds the floor")
+		return
+	}
+	if client.UserID != poi.OfferedBy && client.UserID != poi.SpeakerID && client.Seat != seatModerator {
+		room.Mutex.Unlock()
+		sendPOIError(client, message.Type, "Only the speaker, the offerer or a moderator can end a point of information")
+		return
+	}
+	poiID := poi.ID
+	room.Mutex.Unlock()
+
+	resolvePointOfInformation(room, roomID, poiID, models.POIStatusEnded)
+}
+
+// cancelPointOfInformation drops any open point of information, e.g. when the phase changes
+func cancelPointOfInformation(room *Room, roomID string) {
+	room.Mutex.Lock()
+	if room.POI == nil {
+		room.Mutex.Unlock()
+		return
+	}
+	poiID := room.POI.ID
+	room.Mutex.Unlock()
+
+	resolvePointOfInformation(room, roomID, poiID, models.POIStatusCanceled)
+}
+
+// resolvePointOfInformation closes a point of information with the given status. When the
+// point held the floor, the floor returns to the side whose turn it is.
+func resolvePointOfInformation(room *Room, roomID, poiID, status string) {
+	room.Mutex.Lock()
+	poi := room.POI
+	if poi == nil || poi.ID != poiID {
+		room.Mutex.Unlock()
+		return
+	}
+	if room.poiTimer != nil {
+		room.poiTimer.Stop()
+		room.poiTimer = nil
+	}
+	room.POI = nil
+
+	wasAccepted := poi.Status == models.POIStatusAccepted
+	if wasAccepted {
+		now := time.Now()
+		poi.EndedAt = &now
+	}
+	poi.Status = status
+
+	// A canceled point is followed by a phase change, which sets the mutes itself
+	var notices []muteNotice
+	if wasAccepted && status != models.POIStatusCanceled {
+		notices = applyFloorLocked(room, turnForPhase(room.CurrentPhase), "")
+	}
+	snapshot := *poi
+	room.Mutex.Unlock()
+
+	recordPointOfInformation(roomID, snapshot)
+	sendMuteNotices(notices)
+
+	eventType := "poiEnded"
+	switch status {
+	case models.POIStatusExpired:
+		eventType = "poiExpired"
+	case models.POIStatusCanceled:
+		eventType = "poiCanceled"
+	}
+	broadcastPOI(room, eventType, snapshot, nil)
+}
@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestPointOfInformationFlow(t *testing.T) {
+	room := &Room{CurrentPhase: "openingFor"}
+	speaker := &Client{UserID: "u1", Username: "Ada", Role: "for"}
+	offerer := &Client{UserID: "u2", Username: "Bo", Role: "against"}
+	speakerPeer := joinTestRoom(t, room, speaker)
+	offererPeer := joinTestRoom(t, room, offerer)
+
+	handlePOIRequest(room, Message{Type: "poiRequest"}, speaker, "room1")
+	if msg := readMessageOfType(t, speakerPeer, "poiError"); msg["error"] != "The speaker cannot offer a point of information" {
+		t.Errorf("Expected the speaker to be refused, got %v", msg["error"])
+	}
+
+	handlePOIRequest(room, Message{Type: "poiRequest", Content: " On the figures "}, offerer, "room1")
+	request := readMessageOfType(t, speakerPeer, "poiRequest")
+	poi := request["poi"].(map[string]interface{})
+	if poi["offeredBy"] != "u2" || poi["speakerId"] != "u1" || poi["content"] != "On the figures" {
+		t.Errorf("Unexpected point of information %v", poi)
+	}
+
+	extra, _ := json.Marshal(POIResponsePayload{POIID: poi["id"].(string), Accept: true})
+	handlePOIResponse(room, Message{Type: "poiResponse", Extra: extra}, offerer, "room1")
+	readMessageOfType(t, offererPeer, "poiError")
+
+	handlePOIResponse(room, Message{Type: "poiResponse", Extra: extra}, speaker, "room1")
+	readMessageOfType(t, offererPeer, "poiAccepted")
+	if !speaker.IsMuted || offerer.IsMuted {
+		t.Errorf("Expected the offerer to hold the floor, speaker muted=%v offerer muted=%v", speaker.IsMuted, offerer.IsMuted)
+	}
+
+	handlePOIEnd(room, Message{Type: "poiEnd"}, offerer, "room1")
+	ended := readMessageOfType(t, speakerPeer, "poiEnded")
+	if status := ended["poi"].(map[string]interface{})["status"]; status != models.POIStatusEnded {
+		t.Errorf("Expected the point to end, got %v", status)
+	}
+	if speaker.IsMuted || !offerer.IsMuted || room.POI != nil {
+		t.Errorf("Expected the floor back with the speaker, speaker muted=%v offerer muted=%v", speaker.IsMuted, offerer.IsMuted)
+	}
+}
+
+func TestPointOfInformationNotAllowedInCrossExamination(t *testing.T) {
+	room := &Room{CurrentPhase: "crossForQuestion"}
+	offerer := &Client{UserID: "u2", Role: "against"}
+	peer := joinTestRoom(t, room, offerer)
+
+	handlePOIRequest(room, Message{Type: "poiRequest"}, offerer, "room1")
+	if msg := readMessageOfType(t, peer, "poiError"); msg["error"] != "Points of information are not allowed in this phase" {
+		t.Errorf("Unexpected error %v", msg["error"])
+	}
+}
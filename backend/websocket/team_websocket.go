@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/wsconn"
 	"arguehub/models"
 	"arguehub/services"
 	"arguehub/utils"
@@ -20,6 +21,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Connection metrics and limits for the /ws/team hub
+var (
+	teamHubMetrics = wsconn.HubMetrics("/ws/team")
+	teamConnConfig = wsconn.DefaultConfig()
+)
+
 // TeamRoom represents a team debate room with connected team members
 type TeamRoom struct {
 	Clients     map[*websocket.Conn]*TeamClient
@@ -41,7 +48,7 @@ type TeamRoom struct {
 // TeamClient represents a connected team member
 type TeamClient struct {
 	Conn         *websocket.Conn
-	writeMu      sync.Mutex
+	ws           *wsconn.Conn
 	UserID       primitive.ObjectID
 	Username     string
 	Email        string
@@ -56,18 +63,14 @@ type TeamClient struct {
 	Tokens       int // Remaining speaking tokens
 }
 
-// SafeWriteJSON safely writes JSON data to the team client's WebSocket connection
+// SafeWriteJSON queues JSON data for the team client's WebSocket connection
 func (tc *TeamClient) SafeWriteJSON(v any) error {
-	tc.writeMu.Lock()
-	defer tc.writeMu.Unlock()
-	return tc.Conn.WriteJSON(v)
+	return tc.ws.WriteJSON(v)
 }
 
-// SafeWriteMessage safely writes raw WebSocket messages to the team client's connection
+// SafeWriteMessage queues a raw WebSocket message for the team client's connection
 func (tc *TeamClient) SafeWriteMessage(messageType int, data []byte) error {
-	tc.writeMu.Lock()
-	defer tc.writeMu.Unlock()
-	return tc.Conn.WriteMessage(messageType, data)
+	return tc.ws.WriteMessage(messageType, data)
 }
 
 // TeamMessage represents a message in team debate
@@ -220,6 +223,8 @@ func TeamWebsocketHandler(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	wc := wsconn.New(conn, teamHubMetrics, teamConnConfig)
+	defer wc.Close()
 
 	// CRITICAL: Validate userTeamID matches one of the debate teams before creating client
 	userTeamIDHex := userTeamID.Hex()
@@ -228,13 +233,13 @@ func TeamWebsocketHandler(c *gin.Context) {
 
 	if userTeamIDHex != team1IDHex && userTeamIDHex != team2IDHex {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Team assignment error"})
-		conn.Close()
 		return
 	}
 
 	// Create team client instance
 	client := &TeamClient{
 		Conn:         conn,
+		ws:           wc,
 		UserID:       userObjectID,
 		Username:     username,
 		Email:        email,
@@ -317,9 +322,15 @@ func TeamWebsocketHandler(c *gin.Context) {
 		"team2Members": debate.Team2Members,
 	})
 
-	// Listen for messages
+	serveTeamClient(room, client, roomKey)
+}
+
+// serveTeamClient reads the client's messages until its connection fails, times out on
+// the heartbeat or is evicted as a slow consumer, then removes it from the room
+func serveTeamClient(room *TeamRoom, client *TeamClient, roomKey string) {
+	conn := client.Conn
 	for {
-		messageType, msg, err := conn.ReadMessage()
+		messageType, msg, err := client.ws.ReadMessage()
 		if err != nil {
 			// Remove client from room
 			room.Mutex.Lock()
@@ -419,12 +430,7 @@ func handleTeamJoin(room *TeamRoom, conn *websocket.Conn, message TeamMessage, c
 	}
 
 	// Broadcast to all clients in the room
-<<<<<<< HEAD
-	recipients := snapshotTeamRecipients(room, nil)
-	for _, r := range recipients {
-=======
 	for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 		response := map[string]interface{}{
 			"type":        "teamStatus",
 			"teamStatus":  teamStatus,
@@ -563,12 +569,7 @@ func handleTeamPhaseChange(room *TeamRoom, conn *websocket.Conn, message TeamMes
 		Type:  "phaseChange",
 		Phase: currentPhase,
 	}
-<<<<<<< HEAD
-	recipients := snapshotTeamRecipients(room, nil)
-	for _, r := range recipients {
-=======
 	for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 		if err := r.SafeWriteJSON(phaseMessage); err != nil {
 		} else {
 			log.Printf("[handleTeamPhaseChange] ✓ Phase change broadcasted: %s", currentPhase)
@@ -586,12 +587,7 @@ func handleTeamTopicChange(room *TeamRoom, conn *websocket.Conn, message TeamMes
 	room.Mutex.Unlock()
 
 	// Broadcast topic change to ALL clients (including sender for sync)
-<<<<<<< HEAD
-	recipients := snapshotTeamRecipients(room, nil)
-	for _, r := range recipients {
-=======
 	for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 		if err := r.SafeWriteJSON(message); err != nil {
 		}
 	}
@@ -626,12 +622,7 @@ func handleTeamRoleSelection(room *TeamRoom, conn *websocket.Conn, message TeamM
 		}
 		room.Mutex.Unlock()
 
-<<<<<<< HEAD
-		recipients := snapshotTeamRecipients(room, nil)
-		for _, r := range recipients {
-=======
 		for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 			if err := r.SafeWriteJSON(roleMessage); err != nil {
 			}
 		}
@@ -816,19 +807,13 @@ func handleTeamTurnRequest(room *TeamRoom, conn *websocket.Conn, message TeamMes
 		client.SafeWriteJSON(response)
 
 		// Broadcast turn status to all clients
-<<<<<<< HEAD
 		teamStatus, statusErr := room.TokenBucket.GetTeamSpeakingStatus(client.TeamID, room.TurnManager)
 		if statusErr != nil {
 			log.Printf("failed to load team status for team %s: %v", client.TeamID.Hex(), statusErr)
 			teamStatus = map[string]interface{}{}
 		}
-		recipients := snapshotTeamRecipients(room, nil)
-		for _, r := range recipients {
-=======
-		teamStatus := room.TokenBucket.GetTeamSpeakingStatus(client.TeamID, room.TurnManager)
 		currentTurn := room.TurnManager.GetCurrentTurn(client.TeamID).Hex()
 		for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 			if r.TeamID == client.TeamID {
 				response := map[string]interface{}{
 					"type":        "teamStatus",
@@ -864,12 +849,7 @@ func handleTeamTurnEnd(room *TeamRoom, conn *websocket.Conn, message TeamMessage
 	}
 
 	// Broadcast turn change to all clients in the team
-<<<<<<< HEAD
-	recipients := snapshotTeamRecipients(room, nil)
-	for _, r := range recipients {
-=======
 	for _, r := range snapshotAllTeamClients(room) {
->>>>>>> main
 		if r.TeamID == client.TeamID {
 			response := map[string]interface{}{
 				"type":        "teamStatus",
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"arguehub/internal/wsconn"
+
+	"github.com/gorilla/websocket"
+)
+
+// joinTestTeamRoom connects a team client to room over a real websocket and serves it the
+// way the /ws/team handler does. The returned peer is the client end of the connection.
+func joinTestTeamRoom(t *testing.T, room *TeamRoom, client *TeamClient, roomKey string, cfg wsconn.Config) *websocket.Conn {
+	t.Helper()
+	testUpgrader := websocket.Upgrader{}
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := testUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		serverConns <- ws
+	}))
+	t.Cleanup(server.Close)
+
+	peer, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { peer.Close() })
+
+	conn := <-serverConns
+	client.Conn = conn
+	client.ws = wsconn.New(conn, teamHubMetrics, cfg)
+	t.Cleanup(client.ws.Close)
+
+	room.Mutex.Lock()
+	room.Clients[conn] = client
+	room.Mutex.Unlock()
+	go serveTeamClient(room, client, roomKey)
+	return peer
+}
+
+func TestTeamHubEvictsClientsThatMissTheHeartbeat(t *testing.T) {
+	cfg := wsconn.DefaultConfig()
+	cfg.PongWait = 200 * time.Millisecond
+	cfg.PingPeriod = 50 * time.Millisecond
+
+	roomKey := "heartbeat-test"
+	room := &TeamRoom{Clients: make(map[*websocket.Conn]*TeamClient)}
+	teamRoomsMutex.Lock()
+	teamRooms[roomKey] = room
+	teamRoomsMutex.Unlock()
+	t.Cleanup(func() {
+		teamRoomsMutex.Lock()
+		delete(teamRooms, roomKey)
+		teamRoomsMutex.Unlock()
+	})
+
+	alive := &TeamClient{Username: "alive"}
+	silent := &TeamClient{Username: "silent"}
+	alivePeer := joinTestTeamRoom(t, room, alive, roomKey, cfg)
+	joinTestTeamRoom(t, room, silent, roomKey, cfg)
+
+	// Reading lets the live peer answer pings; the silent peer never does
+	go func() {
+		for {
+			if _, _, err := alivePeer.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(4 * cfg.PongWait)
+	room.Mutex.Lock()
+	_, aliveStays := room.Clients[alive.Conn]
+	_, silentStays := room.Clients[silent.Conn]
+	room.Mutex.Unlock()
+	if !aliveStays {
+		t.Error("Expected a client that answers pings to stay in the room")
+	}
+	if silentStays {
+		t.Error("Expected a client that misses the heartbeat to be evicted")
+	}
+
+	// Once the last client goes, the room goes with it
+	alivePeer.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		teamRoomsMutex.Lock()
+		_, exists := teamRooms[roomKey]
+		teamRoomsMutex.Unlock()
+		if !exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the empty team room to be removed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
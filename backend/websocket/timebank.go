@@ -0,0 +1,201 @@
+package websocket
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+)
+
+// How often remaining time is broadcast while a side's clock is running
+const timeBankBroadcastInterval = 5 * time.Second
+
+// broadcastTimeBank sends the room's time banks to everyone in the room
+func broadcastTimeBank(room *Room, eventType string, state models.TimeBankState, extra map[string]interface{}) {
+	payload := map[string]interface{}{
+		"type":      eventType,
+		"timeBank":  state,
+		"timestamp": time.Now().Unix(),
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+	for _, r := range snapshotRecipients(room, nil) {
+		r.SafeWriteJSON(payload)
+	}
+}
+
+// loadRoomTiming reads the room's timing settings before its first phase change. A failed
+// read is retried on the next phase change, with the default phase lengths until then.
+// Rooms in fixed timing mode keep a nil TimeBank, and their phases run on the phase timer.
+func loadRoomTiming(room *Room, roomID string) {
+	room.Mutex.Lock()
+	loaded := room.timingLoaded
+	room.Mutex.Unlock()
+	if loaded {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	timing, err := services.GetRoomTiming(ctx, roomID)
+	if err != nil {
+		log.Printf("[ws] failed to load room timing: room=%s err=%v", roomID, err)
+		return
+	}
+
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	if room.timingLoaded {
+		return
+	}
+	room.phaseLengths = make(map[string]time.Duration, len(timing.PhaseSeconds))
+	for phase, seconds := range timing.PhaseSeconds {
+		room.phaseLengths[phase] = time.Duration(seconds) * time.Second
+	}
+	if timing.Mode == models.TimingModeTimeBank && timing.TimeBank != nil {
+		room.timeBankConfig = timing.TimeBank
+	}
+	room.timingLoaded = true
+}
+
+// debaterRatingsLocked returns the ratings of the debaters on each side. The caller must
+// hold room.Mutex.
+func debaterRatingsLocked(room *Room) (forRating, againstRating int) {
+	for _, cl := range room.Clients {
+		if !cl.isDebater() {
+			continue
+		}
+		switch cl.Role {
+		case "for":
+			forRating = cl.Elo
+		case "against":
+			againstRating = cl.Elo
+		}
+	}
+	return forRating, againstRating
+}
+
+// startTimeBankPhase moves the clock to the side speaking in phase. The banks are created
+// when the first speech starts, once the sides are fixed, so the rating handicap is worked
+// out from the debaters who actually hold each side.
+func startTimeBankPhase(room *Room, roomID, phase string) {
+	room.Mutex.Lock()
+	if room.TimeBank == nil && room.timeBankConfig != nil && turnForPhase(phase) != "" {
+		forRating, againstRating := debaterRatingsLocked(room)
+		room.TimeBank = services.NewTimeBank(*room.timeBankConfig, forRating, againstRating)
+	}
+	if room.TimeBank == nil {
+		room.Mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	room.TimeBank.StartPhase(phase, turnForPhase(phase), now)
+	scheduleTimeBankLocked(room, roomID)
+	state := room.TimeBank.State(now)
+	room.Mutex.Unlock()
+
+	broadcastTimeBank(room, "timeBank", state, nil)
+}
+
+// scheduleTimeBankLocked arms the timer for the next broadcast or cutoff, whichever
+// comes first. The caller must hold room.Mutex.
+func scheduleTimeBankLocked(room *Room, roomID string) {
+	if room.bankTimer != nil {
+		room.bankTimer.Stop()
+		room.bankTimer = nil
+	}
+	if room.TimeBank == nil || !room.TimeBank.Running() {
+		return
+	}
+	wait := room.TimeBank.UntilCutoff(time.Now())
+	if wait > timeBankBroadcastInterval {
+		wait = timeBankBroadcastInterval
+	}
+	room.bankTimer = time.AfterFunc(wait, func() {
+		timeBankTick(room, roomID)
+	})
+}
+
+// stopTimeBankLocked stops the clock and its timer. The caller must hold room.Mutex.
+func stopTimeBankLocked(room *Room) {
+	if room.bankTimer != nil {
+		room.bankTimer.Stop()
+		room.bankTimer = nil
+	}
+	if room.TimeBank != nil {
+		room.TimeBank.Stop(time.Now())
+	}
+}
+
+// pauseTimeBankLocked pauses or resumes the clock with the moderator's clock controls and
+// returns the new state. The caller must hold room.Mutex.
+func pauseTimeBankLocked(room *Room, roomID string, paused bool) *models.TimeBankState {
+	if room.TimeBank == nil {
+		return nil
+	}
+	now := time.Now()
+	if paused {
+		room.TimeBank.Pause(now)
+	} else {
+		room.TimeBank.Resume(now)
+	}
+	scheduleTimeBankLocked(room, roomID)
+	state := room.TimeBank.State(now)
+	return &state
+}
+
+// timeBankTick broadcasts the remaining time and ends the phase of a side whose time has
+// run out
+func timeBankTick(room *Room, roomID string) {
+	room.Mutex.Lock()
+	bank := room.TimeBank
+	if bank == nil || !bank.Running() {
+		room.Mutex.Unlock()
+		return
+	}
+
+	now := time.Now()
+	if bank.UntilCutoff(now) > 0 {
+		scheduleTimeBankLocked(room, roomID)
+		state := bank.State(now)
+		room.Mutex.Unlock()
+		broadcastTimeBank(room, "timeBank", state, nil)
+		return
+	}
+
+	side := bank.ActiveSide()
+	phase := room.CurrentPhase
+	bank.CutOff(now)
+	room.bankTimer = nil
+	state := bank.State(now)
+	room.Mutex.Unlock()
+
+	broadcastTimeBank(room, "timeBankExpired", state, map[string]interface{}{"side": side})
+	advancePhase(room, roomID, nextPhase(phase))
+}
+
+// handleYieldFloor lets the speaking side of a time bank debate end its speech early once
+// it has spoken for the phase minimum, keeping the rest of its bank for later phases
+func handleYieldFloor(room *Room, client *Client, roomID string) {
+	room.Mutex.Lock()
+	bank := room.TimeBank
+	phase := room.CurrentPhase
+	allowed := bank != nil && client.isDebater() && bank.ActiveSide() == client.Role && bank.CanYield(time.Now())
+	room.Mutex.Unlock()
+	if !allowed {
+		client.SafeWriteJSON(map[string]interface{}{"type": "phaseError", "error": "You cannot yield the floor yet"})
+		return
+	}
+	advancePhase(room, roomID, nextPhase(phase))
+}
+
+// isCutOff reports whether a debater's side has run out of time in the current phase.
+// Speech and audio from a side that is cut off are dropped until the next phase.
+func isCutOff(room *Room, client *Client) bool {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	return client.isDebater() && room.TimeBank != nil && room.TimeBank.IsCutOff(client.Role)
+}
@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+
+	"arguehub/internal/debate"
+	"arguehub/internal/wsconn"
+	"arguehub/services"
+	"arguehub/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+var vsBotUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// vsBotHubMetrics tracks connections to the /ws/vsbot hub
+var vsBotHubMetrics = wsconn.HubMetrics("/ws/vsbot")
+
+// VsBotStreamRequest is a message from the client: "message" takes a turn of a bot debate
+// and asks for the bot's reply, "cancel" stops the reply being generated. The debate's
+// history is kept on the server, so only the new turn is sent.
+type VsBotStreamRequest struct {
+	Type     string `json:"type"`
+	ID       string `json:"id,omitempty"` // Echoed in every event about the request
+	DebateID string `json:"debateId"`
+	Phase    string `json:"phase"`
+	Text     string `json:"text,omitempty"` // The user's turn; empty when the bot speaks first
+	Context  string `json:"context"`
+}
+
+// VsBotStreamEvent tells the client how a reply is progressing: "start", then a "chunk"
+// per piece of text, then "done" with the final text, which replaces the chunks.
+// "cancelled" and "error" end a reply early.
+type VsBotStreamEvent struct {
+	Type       string `json:"type"`
+	ID         string `json:"id,omitempty"`
+	Text       string `json:"text,omitempty"`
+	Error      string `json:"error,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"` // Seconds, when rate limited
+}
+
+// vsBotStream is one user's connection to the /ws/vsbot hub. It generates at most one
+// reply at a time.
+type vsBotStream struct {
+	conn   *wsconn.Conn
+	email  string
+	userID string
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // Stops the reply being generated; nil when idle
+}
+
+// VsBotStreamHandler streams debate bot replies to the client as the model writes them.
+// Leaving the page closes the connection, which stops the reply being generated. The turn
+// and the finished reply are saved to the bot debate's history.
+func VsBotStreamHandler(c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing token"})
+		return
+	}
+	valid, email, err := utils.ValidateTokenAndFetchEmail("./config/config.prod.yml", token, c)
+	if err != nil || !valid || email == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return
+	}
+	userID, err := utils.GetUserIDFromEmail(email)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	ws, err := vsBotUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	// A long reply is many small chunks, so allow a deeper queue than the default
+	cfg := wsconn.DefaultConfig()
+	cfg.SendQueueSize = 1024
+	stream := &vsBotStream{
+		conn:   wsconn.New(ws, vsBotHubMetrics, cfg),
+		email:  email,
+		userID: userID.Hex(),
+	}
+
+	// Cancelled when the client disconnects, stopping any reply in flight
+	ctx, cancel := context.WithCancel(services.WithLLMUser(context.Background(), stream.userID))
+	defer cancel()
+	defer stream.conn.Close()
+
+	for {
+		var req VsBotStreamRequest
+		if err := stream.conn.ReadJSON(&req); err != nil {
+			// Read errors close the connection; anything else was a malformed message
+			if stream.isClosed() {
+				return
+			}
+			stream.send(VsBotStreamEvent{Type: "error", Error: "Invalid message"})
+			continue
+		}
+		switch req.Type {
+		case "message":
+			stream.start(ctx, req)
+		case "cancel":
+			stream.stop()
+		default:
+			stream.send(VsBotStreamEvent{Type: "error", ID: req.ID, Error: "Unknown message type"})
+		}
+	}
+}
+
+// isClosed reports whether the connection has been closed
+func (s *vsBotStream) isClosed() bool {
+	select {
+	case <-s.conn.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// send queues an event; events for a closed connection are dropped
+func (s *vsBotStream) send(event VsBotStreamEvent) {
+	s.conn.WriteJSON(event)
+}
+
+// start validates a reply request and generates the reply in the background
+func (s *vsBotStream) start(parent context.Context, req VsBotStreamRequest) {
+	if req.DebateID == "" || req.Phase == "" {
+		s.send(VsBotStreamEvent{Type: "error", ID: req.ID, Error: "debateId and phase are required"})
+		return
+	}
+
+	// Shares the vsbot_message budget with the HTTP endpoint
+	result, err := debate.NewRateLimiter().Allow(debate.ActionVsBotMessage, "user:"+s.userID)
+	if err != nil {
+		log.Printf("[ws] Rate limit check failed for %s: %v", debate.ActionVsBotMessage, err)
+	} else if !result.Allowed {
+		s.send(VsBotStreamEvent{
+			Type:       "error",
+			ID:         req.ID,
+			Error:      "Too many requests",
+			RetryAfter: int(math.Ceil(result.RetryAfter.Seconds())),
+		})
+		return
+	}
+
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		s.send(VsBotStreamEvent{Type: "error", ID: req.ID, Error: "A reply is already being generated"})
+		return
+	}
+	ctx, cancel := context.WithCancel(parent)
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	go s.generate(ctx, req)
+}
+
+// stop cancels the reply being generated, if any
+func (s *vsBotStream) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// finish marks the connection idle so the client can ask for the next reply
+func (s *vsBotStream) finish() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// generate streams the bot's reply and saves the turn once the reply is complete.
+// Cancelled replies are not saved.
+func (s *vsBotStream) generate(ctx context.Context, req VsBotStreamRequest) {
+	defer s.finish()
+
+	s.send(VsBotStreamEvent{Type: "start", ID: req.ID})
+	turn := services.VsBotTurn{Phase: req.Phase, Text: req.Text, Context: req.Context}
+	_, reply, err := services.TakeVsBotTurn(ctx, req.DebateID, s.email, turn, func(chunk string) {
+		s.send(VsBotStreamEvent{Type: "chunk", ID: req.ID, Text: chunk})
+	})
+	var turnErr *services.VsBotTurnError
+	switch {
+	case err == nil:
+		s.send(VsBotStreamEvent{Type: "done", ID: req.ID, Text: reply})
+	case ctx.Err() != nil:
+		s.send(VsBotStreamEvent{Type: "cancelled", ID: req.ID})
+	case errors.As(err, &turnErr), errors.Is(err, services.ErrVsBotDebateNotFound),
+		errors.Is(err, services.ErrVsBotDebateJudged), errors.Is(err, services.ErrVsBotHistoryChanged):
+		s.send(VsBotStreamEvent{Type: "error", ID: req.ID, Error: err.Error()})
+	default:
+		log.Printf("[ws] Failed to save bot reply: debate=%s err=%v", req.DebateID, err)
+		s.send(VsBotStreamEvent{Type: "error", ID: req.ID, Error: "Failed to save the reply"})
+	}
+}
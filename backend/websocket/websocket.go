@@ -11,6 +11,10 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/internal/wsconn"
+	"arguehub/models"
+	"arguehub/services"
 	"arguehub/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,22 +30,56 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// Connection metrics and limits for the /ws debate room hub. Messages may carry audio
+// chunks, so the read limit is raised to fit services.MaxAudioChunkBytes once base64 encoded.
+var (
+	roomHubMetrics = wsconn.HubMetrics("/ws")
+	roomConnConfig = func() wsconn.Config {
+		cfg := wsconn.DefaultConfig()
+		cfg.MaxMessageSize = 8 << 20
+		return cfg
+	}()
+)
+
 // Room represents a debate room with connected clients.
 type Room struct {
 	Clients map[*websocket.Conn]*Client
 	Mutex   sync.Mutex
+	// Moderator-controlled state
+	CurrentPhase string
+	Paused       bool
+	Ended        bool
+	// Point of information awaiting a response or currently holding the floor
+	POI      *models.PointOfInformation
+	poiTimer *time.Timer
+	// Chess-clock time banks; nil when the room uses fixed phase timings. The bank is
+	// created from timeBankConfig when the first speech starts and the roles are final.
+	TimeBank       *services.TimeBank
+	timeBankConfig *models.TimeBankConfig
+	bankTimer      *time.Timer
+	timingLoaded   bool
+	// Server phase timer; phaseLengths overrides phaseDurations for this room and
+	// phaseRemaining holds what was left while the clock is paused
+	phaseLengths   map[string]time.Duration
+	phaseTimer     *time.Timer
+	phaseDeadline  time.Time
+	phaseRemaining time.Duration
+	// What was said so far and the topic, for the AI commentator
+	Topic  string
+	Speech []services.CommentarySpeech
 }
 
 // Client represents a connected client with user information
 type Client struct {
 	Conn         *websocket.Conn
-	writeMu      sync.Mutex // Mutex for safe WebSocket writes
+	ws           *wsconn.Conn // Queued writer with heartbeats and slow-consumer eviction
 	UserID       string
 	Username     string
 	Email        string
 	AvatarURL    string
 	Elo          int
 	IsSpectator  bool
+	IsHost       bool // Owner of the room, who may move the debate on to the next phase early
 	IsTyping     bool
 	IsSpeaking   bool
 	PartialText  string
@@ -50,20 +88,17 @@ type Client struct {
 	Role         string // New field to track debate role (for/against)
 	SpeechText   string // New field to store speech text
 	ConnectionID string
+	Seat         string // "judge" or "moderator" for officials, empty for debaters and spectators
 }
 
-// SafeWriteJSON safely writes JSON data to the client's WebSocket connection
+// SafeWriteJSON queues JSON data for the client's WebSocket connection
 func (c *Client) SafeWriteJSON(v any) error {
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
-	return c.Conn.WriteJSON(v)
+	return c.ws.WriteJSON(v)
 }
 
-// SafeWriteMessage safely writes raw WebSocket messages to the client's connection
+// SafeWriteMessage queues a raw WebSocket message for the client's connection
 func (c *Client) SafeWriteMessage(messageType int, data []byte) error {
-	c.writeMu.Lock()
-	defer c.writeMu.Unlock()
-	return c.Conn.WriteMessage(messageType, data)
+	return c.ws.WriteMessage(messageType, data)
 }
 
 type Message struct {
@@ -116,12 +151,13 @@ func snapshotRecipients(room *Room, exclude *websocket.Conn) []*Client {
 	return out
 }
 
-func nonSpectatorRecipients(room *Room, exclude *websocket.Conn) []*Client {
+// debaterRecipients returns the debaters in the room, excluding spectators and officials
+func debaterRecipients(room *Room, exclude *websocket.Conn) []*Client {
 	room.Mutex.Lock()
 	defer room.Mutex.Unlock()
 	out := make([]*Client, 0, len(room.Clients))
 	for cc, cl := range room.Clients {
-		if (exclude != nil && cc == exclude) || cl.IsSpectator {
+		if (exclude != nil && cc == exclude) || !cl.isDebater() {
 			continue
 		}
 		out = append(out, cl)
@@ -134,7 +170,7 @@ func countDebaters(room *Room) int {
 	defer room.Mutex.Unlock()
 	count := 0
 	for _, cl := range room.Clients {
-		if !cl.IsSpectator {
+		if cl.isDebater() {
 			count++
 		}
 	}
@@ -158,6 +194,7 @@ func buildParticipantsMessage(room *Room) map[string]interface{} {
 	defer room.Mutex.Unlock()
 
 	participants := make([]map[string]interface{}, 0, len(room.Clients))
+	officials := make([]map[string]interface{}, 0)
 	spectatorCount := 0
 
 	for _, client := range room.Clients {
@@ -165,6 +202,14 @@ func buildParticipantsMessage(room *Room) map[string]interface{} {
 			spectatorCount++
 			continue
 		}
+		if client.Seat != "" {
+			officials = append(officials, map[string]interface{}{
+				"id":          client.UserID,
+				"displayName": client.Username,
+				"seat":        client.Seat,
+			})
+			continue
+		}
 
 		participants = append(participants, map[string]interface{}{
 			"id":          client.UserID,
@@ -178,7 +223,10 @@ func buildParticipantsMessage(room *Room) map[string]interface{} {
 	message := map[string]interface{}{
 		"type":             "roomParticipants",
 		"roomParticipants": participants,
+		"officials":        officials,
 		"spectatorCount":   spectatorCount,
+		"paused":           room.Paused,
+		"ended":            room.Ended,
 	}
 
 	return message
@@ -212,14 +260,14 @@ func notifySpectatorStatus(room *Room, spectator *Client, joined bool) {
 		"spectatorCount": countSpectators(room),
 	}
 
-	for _, client := range nonSpectatorRecipients(room, nil) {
+	for _, client := range debaterRecipients(room, nil) {
 		if err := client.SafeWriteJSON(status); err != nil {
 		}
 	}
 }
 
 func broadcastRawToDebaters(room *Room, exclude *websocket.Conn, payload []byte) {
-	recipients := nonSpectatorRecipients(room, exclude)
+	recipients := debaterRecipients(room, exclude)
 	for _, client := range recipients {
 		if err := client.SafeWriteMessage(websocket.TextMessage, payload); err != nil {
 		}
@@ -273,27 +321,44 @@ func WebsocketHandler(c *gin.Context) {
 	room := rooms[roomID]
 	roomsMutex.Unlock()
 
+	// Judges and moderators take an official seat instead of debating
+	seat := strings.ToLower(c.Query("seat"))
+	if seat != "" {
+		if seat != seatJudge && seat != seatModerator {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid seat"})
+			return
+		}
+		seatCtx, seatCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		allowed := services.CanTakeSeat(seatCtx, roomID, userID, seat)
+		seatCancel()
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not allowed to take this seat"})
+			return
+		}
+	}
+
 	// Upgrade the connection.
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		return
 	}
+	wc := wsconn.New(conn, roomHubMetrics, roomConnConfig)
+	defer wc.Close()
 
 	// Check if this is a spectator connection (they want to receive video streams)
-	// Allow spectators to connect even if room has 2 debaters
-	isSpectator := strings.EqualFold(c.Query("spectator"), "true")
+	// Allow spectators and officials to connect even if room has 2 debaters
+	isSpectator := seat == "" && strings.EqualFold(c.Query("spectator"), "true")
 	room.Mutex.Lock()
 	currentDebaters := 0
 	for _, existing := range room.Clients {
-		if !existing.IsSpectator {
+		if existing.isDebater() {
 			currentDebaters++
 		}
 	}
 	maxDebaters := 2
-	if !isSpectator && currentDebaters >= maxDebaters {
+	if !isSpectator && seat == "" && currentDebaters >= maxDebaters {
 		room.Mutex.Unlock()
 		log.Printf("[ws] rejecting debater %s for room %s: already full", email, roomID)
-		conn.Close()
 		return
 	}
 	room.Mutex.Unlock()
@@ -308,6 +373,7 @@ func WebsocketHandler(c *gin.Context) {
 	// Create client instance
 	client := &Client{
 		Conn:         conn,
+		ws:           wc,
 		UserID:       userID,
 		Username:     username,
 		Email:        email,
@@ -321,13 +387,22 @@ func WebsocketHandler(c *gin.Context) {
 		IsMuted:      false,
 		Role:         "",
 		SpeechText:   "",
-		IsSpectator:  isSpectator,
+		Seat:         seat,
 	}
 
+	if client.isDebater() {
+		hostCtx, hostCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		client.IsHost = services.IsRoomHost(hostCtx, roomID, userID)
+		hostCancel()
+	}
 	if isSpectator {
 		client.Role = "spectator"
 		client.ConnectionID = uuid.New().String()
 	}
+	if seat != "" {
+		client.Role = seat
+		client.ConnectionID = uuid.New().String()
+	}
 
 	// Mark as spectator if needed (we can add a field to Client struct for this)
 	// For now, we'll handle it through the message handlers
@@ -335,8 +410,14 @@ func WebsocketHandler(c *gin.Context) {
 	// Send current participants to the new client
 	room.Mutex.Lock()
 	room.Clients[conn] = client
+	settingUp := room.CurrentPhase == ""
 	room.Mutex.Unlock()
 
+	// The audience's pre-debate vote on the motion is open while the debaters set up
+	if settingUp && client.isDebater() {
+		services.OpenPreDebateMotionPoll(roomID)
+	}
+
 	// Send participants list to newly connected client
 	participantsMsg := buildParticipantsMessage(room)
 	client.SafeWriteJSON(participantsMsg)
@@ -393,7 +474,7 @@ func WebsocketHandler(c *gin.Context) {
 
 	// Listen for messages.
 	for {
-		messageType, msg, err := conn.ReadMessage()
+		messageType, msg, err := wc.ReadMessage()
 		if err != nil {
 			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
 				log.Printf("[ws] connection closed: room=%s spectator=%t user=%s", roomID, client.IsSpectator, client.Email)
@@ -411,15 +492,28 @@ func WebsocketHandler(c *gin.Context) {
 				delete(room.Clients, conn)
 			}
 			clientCount = len(room.Clients)
+			started := room.CurrentPhase != "" || room.Ended
 
 			// If room is empty, delete it.
 			if clientCount == 0 {
+				stopTimeBankLocked(room)
+				stopPhaseTimerLocked(room)
 				roomsMutex.Lock()
 				delete(rooms, roomID)
 				roomsMutex.Unlock()
 			}
 			room.Mutex.Unlock()
 
+			// A debate everyone has left is no longer live. Once it has started its polls
+			// and events are archived as if it had ended.
+			if clientCount == 0 && started {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				services.ArchiveEndedDebate(ctx, roomID)
+				cancel()
+			} else if clientCount == 0 {
+				services.RemoveLiveDebate(roomID)
+			}
+
 			if exists && disconnectedClient.IsSpectator {
 				log.Printf("[ws] spectator disconnected: room=%s connectionId=%s user=%s", roomID, disconnectedClient.ConnectionID, disconnectedClient.Email)
 				notifySpectatorStatus(room, disconnectedClient, false)
@@ -460,7 +554,9 @@ func WebsocketHandler(c *gin.Context) {
 		case "liveTranscript":
 			handleLiveTranscript(room, conn, message, client, roomID)
 		case "phaseChange":
-			handlePhaseChange(room, conn, message, roomID)
+			handlePhaseChange(room, message, client, roomID)
+		case "yieldFloor":
+			handleYieldFloor(room, client, roomID)
 		case "topicChange":
 			handleTopicChange(room, conn, message, roomID)
 		case "roleSelection":
@@ -471,8 +567,24 @@ func WebsocketHandler(c *gin.Context) {
 			handleMuteRequest(room, conn, message, client, roomID)
 		case "unmute":
 			handleUnmuteRequest(room, conn, message, client, roomID)
+		case "audioChunk":
+			handleAudioChunk(room, message, client, roomID)
+		case "poiRequest":
+			handlePOIRequest(room, message, client, roomID)
+		case "poiResponse":
+			handlePOIResponse(room, message, client, roomID)
+		case "poiEnd":
+			handlePOIEnd(room, message, client, roomID)
+		case "moderateQuestion":
+			handleModerateQuestion(room, message, client, roomID)
+		case "questionAnswered":
+			handleQuestionAnswered(room, message, client, roomID)
+		case "judgeBallot":
+			handleJudgeBallot(room, message, client, roomID)
+		case "pauseClock", "resumeClock", "moderatorWarning", "endDebate", "setDecisionMode", "setWinnerSource", "setSpectatorPolicy", "setCommentary":
+			handleModeratorAction(room, message, client, roomID)
 		default:
-			if message.Type == "requestOffer" && client.IsSpectator {
+			if message.Type == "requestOffer" && !client.isDebater() {
 				var req map[string]interface{}
 				if err := json.Unmarshal(msg, &req); err == nil {
 					if client.ConnectionID == "" {
@@ -503,6 +615,10 @@ func WebsocketHandler(c *gin.Context) {
 
 // handleChatMessage handles chat messages with enhanced features
 func handleChatMessage(room *Room, conn *websocket.Conn, message Message, client *Client, roomID string) {
+	if isCutOff(room, client) {
+		return
+	}
+
 	// Add timestamp if not provided
 	if message.Timestamp == 0 {
 		message.Timestamp = time.Now().Unix()
@@ -572,8 +688,19 @@ func handleSpeakingIndicator(room *Room, conn *websocket.Conn, message Message,
 
 // handleSpeechText handles speech-to-text conversion
 func handleSpeechText(room *Room, conn *websocket.Conn, message Message, client *Client, roomID string) {
+	if isCutOff(room, client) {
+		return
+	}
+
 	room.Mutex.Lock()
 	client.SpeechText = message.SpeechText
+	if client.isDebater() && strings.TrimSpace(message.SpeechText) != "" {
+		phase := message.Phase
+		if phase == "" {
+			phase = room.CurrentPhase
+		}
+		room.Speech = append(room.Speech, services.CommentarySpeech{Phase: phase, Side: client.Role, Text: message.SpeechText})
+	}
 	room.Mutex.Unlock()
 
 	// Broadcast speech text to other clients
@@ -593,6 +720,10 @@ func handleSpeechText(room *Room, conn *websocket.Conn, message Message, client
 
 // handleLiveTranscript handles live/interim transcript updates
 func handleLiveTranscript(room *Room, conn *websocket.Conn, message Message, client *Client, roomID string) {
+	if isCutOff(room, client) {
+		return
+	}
+
 	// Broadcast live transcript to other clients
 	for _, r := range snapshotRecipients(room, conn) {
 		response := map[string]interface{}{
@@ -608,24 +739,91 @@ func handleLiveTranscript(room *Room, conn *websocket.Conn, message Message, cli
 	}
 }
 
-// handlePhaseChange handles phase changes
-func handlePhaseChange(room *Room, conn *websocket.Conn, message Message, roomID string) {
+// handlePhaseChange lets the room host or a moderator start the debate or move it on to
+// the next phase early. Phases are never skipped; everyone else follows the server.
+func handlePhaseChange(room *Room, message Message, client *Client, roomID string) {
+	if !client.IsHost && client.Seat != seatModerator {
+		client.SafeWriteJSON(map[string]interface{}{"type": "phaseError", "error": "Only the host or a moderator can change the phase"})
+		return
+	}
+	room.Mutex.Lock()
+	next := nextPhase(room.CurrentPhase)
+	room.Mutex.Unlock()
+	if message.Phase != next {
+		client.SafeWriteJSON(map[string]interface{}{"type": "phaseError", "error": "The debate can only move on to the next phase", "nextPhase": next})
+		return
+	}
+	advancePhase(room, roomID, message.Phase)
+}
+
+// advancePhase moves the debate on to phase and tells everyone in the room. In fixed timing
+// the phase timer moves it on again once the phase's time is up; with time banks a speech
+// ends when its side runs out of time or yields.
+func advancePhase(room *Room, roomID, phase string) {
+	// The room's timing mode decides which clock ends the phase
+	loadRoomTiming(room, roomID)
+
+	// Phase changes are frozen while a moderator has paused or ended the debate, and the
+	// debate never goes back to or restarts a phase already under way
+	room.Mutex.Lock()
+	previousPhase := room.CurrentPhase
+	if room.Paused || room.Ended || phaseIndex(phase) <= phaseIndex(previousPhase) {
+		room.Mutex.Unlock()
+		return
+	}
+	room.CurrentPhase = phase
+	if room.timeBankConfig != nil && turnForPhase(phase) != "" {
+		stopPhaseTimerLocked(room)
+	} else {
+		schedulePhaseLocked(room, roomID, phase, phaseDurationLocked(room, phase))
+	}
+	topic := room.Topic
+	speech := append([]services.CommentarySpeech(nil), room.Speech...)
+	room.Mutex.Unlock()
+	message := Message{Type: "phaseChange", Phase: phase}
+
+	// The AI commentator, if the debate has it on, analyses the phase that just finished
+	// for spectators only
+	if previousPhase != "" {
+		go services.CommentOnPhase(roomID, topic, previousPhase, speech)
+	}
+
+	// A phase change cancels any point of information still open in the previous phase
+	cancelPointOfInformation(room, roomID)
+
+	// Move the chess clock, if the room uses time banks, to the side now speaking
+	startTimeBankPhase(room, roomID, phase)
+
+	// The audience votes on the motion before the openings and again from the closings
+	// until the debate finishes
+	switch phase {
+	case "openingFor":
+		services.ClosePreDebateMotionPoll(roomID)
+	case "closingFor":
+		services.OpenPostDebateMotionPoll(roomID)
+	case phaseFinished:
+		swingCtx, swingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		services.FinishMotionVoting(swingCtx, roomID)
+		swingCancel()
+	}
+
 	// Determine whose turn it is based on the phase
-	var currentTurn string
-	switch message.Phase {
-	case "openingFor", "crossForQuestion", "crossForAnswer", "closingFor":
-		currentTurn = "for"
-	case "openingAgainst", "crossAgainstQuestion", "crossAgainstAnswer", "closingAgainst":
-		currentTurn = "against"
-	default:
-		currentTurn = ""
+	currentTurn := turnForPhase(phase)
+
+	// Mark the phase on the audience sentiment timeline and in the live directory
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := services.RecordDebatePhase(ctx, roomID, phase, currentTurn); err != nil {
+		log.Printf("[ws] Failed to record debate phase: room=%s err=%v", roomID, err)
 	}
+	services.UpdateLiveDebate(ctx, roomID, phase, liveParticipants(room))
+	cancel()
 
-	// Automatically mute/unmute users based on turn
+	// Automatically mute/unmute users based on turn. Both sides may answer audience questions.
+	openFloor := phase == phaseAudienceQuestions
 	room.Mutex.Lock()
-	for clientConn, client := range room.Clients {
+	for _, client := range room.Clients {
 		if client.Role != "" {
-			shouldBeMuted := client.Role != currentTurn
+			shouldBeMuted := client.Role != currentTurn && !openFloor
 			client.IsMuted = shouldBeMuted
 
 			// Send mute status to each client
@@ -635,23 +833,66 @@ func handlePhaseChange(room *Room, conn *websocket.Conn, message Message, roomID
 				"username":    client.Username,
 				"isMuted":     shouldBeMuted,
 				"currentTurn": currentTurn,
-				"phase":       message.Phase,
+				"phase":       phase,
 			}
-			if err := clientConn.WriteJSON(response); err != nil {
+			if err := client.SafeWriteJSON(response); err != nil {
 			}
 		}
 	}
 	room.Mutex.Unlock()
 
-	// Broadcast phase change to other clients
-	for _, r := range snapshotRecipients(room, conn) {
+	// Broadcast the phase change to everyone, including the host who asked for it
+	for _, r := range snapshotRecipients(room, nil) {
 		if err := r.SafeWriteJSON(message); err != nil {
 		}
 	}
+
+	if openFloor {
+		pushAudienceQuestions(room, roomID)
+	}
+}
+
+// turnForPhase returns the side ("for" or "against") whose turn it is in a phase
+func turnForPhase(phase string) string {
+	switch phase {
+	case "openingFor", "crossForQuestion", "crossForAnswer", "closingFor":
+		return "for"
+	case "openingAgainst", "crossAgainstQuestion", "crossAgainstAnswer", "closingAgainst":
+		return "against"
+	default:
+		return ""
+	}
+}
+
+// liveParticipants lists the room's debaters for the live debates directory
+func liveParticipants(room *Room) []debate.LiveParticipant {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	participants := make([]debate.LiveParticipant, 0, 2)
+	for _, client := range room.Clients {
+		if !client.isDebater() {
+			continue
+		}
+		participants = append(participants, debate.LiveParticipant{
+			ID:        client.UserID,
+			Name:      client.Username,
+			Side:      client.Role,
+			Rating:    client.Elo,
+			AvatarURL: client.AvatarURL,
+		})
+	}
+	return participants
 }
 
 // handleTopicChange handles topic changes
 func handleTopicChange(room *Room, conn *websocket.Conn, message Message, roomID string) {
+	if topic := strings.TrimSpace(message.Topic); topic != "" {
+		room.Mutex.Lock()
+		room.Topic = topic
+		room.Mutex.Unlock()
+		services.SetLiveDebateTopic(roomID, topic)
+	}
+
 	// Broadcast topic change to other clients
 	for _, r := range snapshotRecipients(room, conn) {
 		if err := r.SafeWriteJSON(message); err != nil {
@@ -661,15 +902,25 @@ func handleTopicChange(room *Room, conn *websocket.Conn, message Message, roomID
 
 // handleRoleSelection handles role selection
 func handleRoleSelection(room *Room, conn *websocket.Conn, message Message, roomID string) {
+	if message.Role != "for" && message.Role != "against" {
+		return
+	}
+
 	// Store the role in the client
 	room.Mutex.Lock()
-	defer room.Mutex.Unlock()
-	if client, exists := room.Clients[conn]; exists {
-		if client.IsSpectator {
-			return
-		}
-		client.Role = message.Role
+	client, exists := room.Clients[conn]
+	if !exists || !client.isDebater() {
+		room.Mutex.Unlock()
+		return
 	}
+	// Sides are fixed once the debate starts; the time bank handicap depends on them
+	if room.CurrentPhase != "" {
+		room.Mutex.Unlock()
+		client.SafeWriteJSON(map[string]interface{}{"type": "phaseError", "error": "Sides cannot change once the debate has started"})
+		return
+	}
+	client.Role = message.Role
+	room.Mutex.Unlock()
 
 	// Broadcast role selection to other clients
 	for _, r := range snapshotRecipients(room, conn) {
@@ -0,0 +1,64 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"arguehub/internal/wsconn"
+
+	"github.com/gorilla/websocket"
+)
+
+// joinTestRoom connects a client to room over a real websocket and returns the client end,
+// which receives everything the server sends the client
+func joinTestRoom(t *testing.T, room *Room, client *Client) *websocket.Conn {
+	t.Helper()
+	testUpgrader := websocket.Upgrader{}
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := testUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		serverConns <- ws
+	}))
+	t.Cleanup(server.Close)
+
+	peer, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { peer.Close() })
+
+	conn := <-serverConns
+	client.Conn = conn
+	client.ws = wsconn.New(conn, roomHubMetrics, roomConnConfig)
+	t.Cleanup(client.ws.Close)
+
+	room.Mutex.Lock()
+	if room.Clients == nil {
+		room.Clients = make(map[*websocket.Conn]*Client)
+	}
+	room.Clients[conn] = client
+	room.Mutex.Unlock()
+	return peer
+}
+
+// readMessageOfType reads from peer until a message of the given type arrives
+func readMessageOfType(t *testing.T, peer *websocket.Conn, msgType string) map[string]interface{} {
+	t.Helper()
+	peer.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg map[string]interface{}
+		if err := peer.ReadJSON(&msg); err != nil {
+			t.Fatalf("Expected a %s message, got %v", msgType, err)
+		}
+		if msg["type"] == msgType {
+			return msg
+		}
+	}
+}
@@ -1,4 +1,4 @@
-import React, { useState } from "react";
+import React, { useEffect, useState } from "react";
 import { useNavigate } from "react-router-dom";
 import { Button } from "../components/ui/button";
 import { Input } from "../components/ui/input";
@@ -10,7 +10,7 @@ import {
   SelectValue,
 } from "@/components/ui/select";
 import { Separator } from "../components/ui/separator";
-import { createDebate } from "@/services/vsbot";
+import { createDebate, listBots } from "@/services/vsbot";
 import { useAtom } from "jotai";
 import { userAtom } from "@/state/userAtom";
 
@@ -24,8 +24,8 @@ interface Bot {
   rating: number;
 }
 
-// Bot definitions
-const allBots: Bot[] = [
+// Cards for the built-in bots; bots added on the server are shown from their definitions
+const knownBots: Bot[] = [
   // Classic bots
   {
     name: "Rookie Rick",
@@ -213,8 +213,30 @@ const BotSelection: React.FC = () => {
   const [user] = useAtom(userAtom);
   const [error, setError] = useState<string | null>(null);
   const [expandedLevel, setExpandedLevel] = useState<string | null>(null);
+  const [allBots, setAllBots] = useState<Bot[]>(knownBots);
   const navigate = useNavigate();
 
+  // The server's list is authoritative, so admin-added bots appear and retired ones go
+  useEffect(() => {
+    listBots()
+      .then((serverBots) => {
+        setAllBots(
+          serverBots.map(
+            (b) =>
+              knownBots.find((known) => known.name === b.name) || {
+                name: b.name,
+                level: b.level,
+                desc: b.description || "",
+                avatar: b.avatarUrl || "/images/moderate_mike.jpg",
+                quote: b.quote || "",
+                rating: b.rating,
+              }
+          )
+        );
+      })
+      .catch((err) => console.error("Failed to load bots:", err));
+  }, []);
+
   const effectiveTopic = topic === "custom" ? customTopic : topic;
   const selectedBotObj = selectedBot
     ? allBots.find((b) => b.name === selectedBot)
@@ -280,7 +302,6 @@ const BotSelection: React.FC = () => {
       botLevel: bot.level,
       topic: effectiveTopic,
       stance: finalStance,
-      history: [],
       phaseTimings,
     };
 
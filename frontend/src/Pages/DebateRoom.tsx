@@ -2,7 +2,7 @@ import React, { useState, useEffect, useRef } from "react";
 import { useLocation } from "react-router-dom";
 import { Button } from "../components/ui/button";
 import { Input } from "../components/ui/input";
-import { sendDebateMessage, judgeDebate } from "@/services/vsbot";
+import { streamDebateMessage, recordUserTurn, judgeDebate } from "@/services/vsbot";
 import JudgmentPopup from "@/components/JudgementPopup";
 import { Mic, MicOff } from "lucide-react";
 import { useAtom } from "jotai";
@@ -249,10 +249,14 @@ const DebateRoom: React.FC = () => {
   const [showJudgment, setShowJudgment] = useState(false);
   const [isRecognizing, setIsRecognizing] = useState(false);
   const [nextTurnPending, setNextTurnPending] = useState(false);
+  const [streamingText, setStreamingText] = useState<string | null>(null);
   const timerRef = useRef<NodeJS.Timeout | null>(null);
   const botTurnRef = useRef(false);
   const messagesEndRef = useRef<HTMLDivElement>(null);
   const recognitionRef = useRef<SpeechRecognition | null>(null);
+  const botStreamRef = useRef<AbortController | null>(null);
+  // User turns still being saved; the bot's reply and the verdict wait for them
+  const userTurnRef = useRef<Promise<void>>(Promise.resolve());
 
   const bot = allBots.find((b) => b.name === debateData.botName) || allBots[0];
   const userAvatar =
@@ -391,9 +395,14 @@ const DebateRoom: React.FC = () => {
     state.isDebateEnded,
   ]);
 
+  // Stop a reply still being written when leaving the page
+  useEffect(() => {
+    return () => botStreamRef.current?.abort();
+  }, []);
+
   useEffect(() => {
     messagesEndRef.current?.scrollIntoView({ behavior: "smooth" });
-  }, [state.messages]);
+  }, [state.messages, streamingText]);
 
   const getPhaseInstructions = (phaseIndex: number) => {
     switch (phaseIndex) {
@@ -449,7 +458,7 @@ const DebateRoom: React.FC = () => {
         isJudging: true,
       });
       setState({ ...currentState, isDebateEnded: true });
-      judgeDebateResult(currentState.messages);
+      judgeDebateResult();
       setNextTurnPending(false);
     }
   };
@@ -469,6 +478,15 @@ const DebateRoom: React.FC = () => {
       text: finalInput,
       phase: phases[state.currentPhase].name,
     };
+    userTurnRef.current = userTurnRef.current
+      .then(() =>
+        recordUserTurn({
+          debateId: debateData.debateId,
+          phase: newMessage.phase!,
+          text: newMessage.text,
+        })
+      )
+      .catch((error) => console.error("Failed to save turn:", error));
 
     setState((prev) => {
       const updatedState = {
@@ -501,14 +519,20 @@ const DebateRoom: React.FC = () => {
           : "Provide your answer";
       }
 
-      const { response } = await sendDebateMessage({
-        botLevel: debateData.botLevel,
-        topic: debateData.topic,
-        history: state.messages,
-        botName: debateData.botName,
-        stance: state.botStance,
-        context,
-      });
+      const controller = new AbortController();
+      botStreamRef.current = controller;
+      setStreamingText("");
+      await userTurnRef.current;
+      const response = await streamDebateMessage(
+        {
+          debateId: debateData.debateId,
+          phase: phases[state.currentPhase].name,
+          context,
+        },
+        setStreamingText,
+        controller.signal
+      );
+      setStreamingText(null);
 
       const botMessage: Message = {
         sender: "Bot",
@@ -528,6 +552,9 @@ const DebateRoom: React.FC = () => {
         return updatedState;
       });
     } catch (error) {
+      setStreamingText(null);
+      // The page was left while the bot was replying
+      if (error instanceof DOMException && error.name === "AbortError") return;
       console.error("Bot error:", error);
       // Even on error, advance turn to prevent getting stuck
       setState((prev) => {
@@ -545,17 +572,16 @@ const DebateRoom: React.FC = () => {
         return updatedState;
       });
     } finally {
+      botStreamRef.current = null;
       botTurnRef.current = false;
     }
   };
 
-  const judgeDebateResult = async (messages: Message[]) => {
+  const judgeDebateResult = async () => {
     try {
-      console.log("Starting judgment with messages:", messages);
-      const { result } = await judgeDebate({
-        history: messages,
-        userId: debateData.userId,
-      });
+      // The server judges the turns it saved, so wait for the last one
+      await userTurnRef.current;
+      const { result } = await judgeDebate({ debateId: debateData.debateId });
       console.log("Raw judge result:", result);
       
       const jsonString = extractJSON(result);
@@ -655,6 +681,14 @@ const DebateRoom: React.FC = () => {
             {msg.text}
           </div>
         ))}
+        {sender === "Bot" && streamingText !== null && (
+          <div className="p-3 bg-gray-50 rounded-lg shadow-sm text-gray-800 break-words">
+            <span className="text-xs text-gray-500 block mb-1">
+              {phases[state.currentPhase].name}
+            </span>
+            {streamingText || "..."}
+          </div>
+        )}
         <div ref={messagesEndRef} />
       </div>
     );
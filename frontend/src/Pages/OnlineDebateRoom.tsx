@@ -31,6 +31,7 @@ enum DebatePhase {
   CrossAgainstAnswer = "crossAgainstAnswer",
   CrossAgainstQuestion = "crossAgainstQuestion",
   CrossForAnswer = "crossForAnswer",
+  AudienceQuestions = "audienceQuestions",
   ClosingFor = "closingFor",
   ClosingAgainst = "closingAgainst",
   Finished = "finished",
@@ -125,6 +126,7 @@ const phaseDurations: { [key in DebatePhase]?: number } = {
   [DebatePhase.CrossAgainstAnswer]: 30,
   [DebatePhase.CrossAgainstQuestion]: 30,
   [DebatePhase.CrossForAnswer]: 30,
+  [DebatePhase.AudienceQuestions]: 120,
   [DebatePhase.ClosingFor]: 30,
   [DebatePhase.ClosingAgainst]: 30,
 };
@@ -472,6 +474,7 @@ const OnlineDebateRoom = (): JSX.Element => {
       DebatePhase.CrossAgainstAnswer,
       DebatePhase.CrossAgainstQuestion,
       DebatePhase.CrossForAnswer,
+      DebatePhase.AudienceQuestions,
       DebatePhase.ClosingFor,
       DebatePhase.ClosingAgainst,
       DebatePhase.Finished,
@@ -479,14 +482,17 @@ const OnlineDebateRoom = (): JSX.Element => {
     []
   );
 
-  // Determine if it's the local user's turn to speak
+  // Determine if it's the local user's turn to speak. Both sides may answer audience
+  // questions.
   const isMyTurn =
-    localRole ===
-    (debatePhase.includes("For")
-      ? "for"
-      : debatePhase.includes("Against")
-      ? "against"
-      : null);
+    debatePhase === DebatePhase.AudienceQuestions
+      ? Boolean(localRole)
+      : localRole ===
+        (debatePhase.includes("For")
+          ? "for"
+          : debatePhase.includes("Against")
+          ? "against"
+          : null);
 
   const startJudgmentPolling = useCallback(
     (role: DebateRole) => {
@@ -790,27 +796,20 @@ const OnlineDebateRoom = (): JSX.Element => {
     startJudgmentPolling,
   ]);
 
+  // The server moves the debate on when a phase's time is up, so the local timer only
+  // warns when the debate cannot finish
   const handlePhaseDone = useCallback(() => {
     const currentIndex = phaseOrder.indexOf(debatePhase);
     console.debug(
       `handlePhaseDone called for ${localRole}. Current phase: ${debatePhase}, Index: ${currentIndex}`
     );
-    if (currentIndex >= 0 && currentIndex < phaseOrder.length - 1) {
-      const nextPhase = phaseOrder[currentIndex + 1];
-      console.debug(
-        `Transitioning to next phase: ${nextPhase} for role: ${localRole}`
-      );
-      setDebatePhase(nextPhase);
-      wsRef.current?.send(
-        JSON.stringify({ type: "phaseChange", phase: nextPhase })
-      );
-    } else if (!localRole || !peerRole) {
+    if (currentIndex === phaseOrder.length - 2 && (!localRole || !peerRole)) {
       setPopup({
         show: true,
         message: "Both debaters must select roles to finish the debate.",
       });
     }
-  }, [debatePhase, localRole, peerRole, phaseOrder, setDebatePhase, setPopup]);
+  }, [debatePhase, localRole, peerRole, phaseOrder, setPopup]);
 
   // Set timer based on phase duration
   useEffect(() => {
@@ -1986,10 +1985,12 @@ const OnlineDebateRoom = (): JSX.Element => {
       const timer = setTimeout(() => setCountdown(countdown - 1), 1000);
       return () => clearTimeout(timer);
     } else if (countdown === 0) {
-      setDebatePhase(DebatePhase.OpeningFor);
-      wsRef.current?.send(
-        JSON.stringify({ type: "phaseChange", phase: DebatePhase.OpeningFor })
-      );
+      // The host starts the debate; the server tells everyone when each phase begins
+      if (isRoomOwner) {
+        wsRef.current?.send(
+          JSON.stringify({ type: "phaseChange", phase: DebatePhase.OpeningFor })
+        );
+      }
       console.debug(
         `Countdown finished. Starting debate at ${DebatePhase.OpeningFor} for ${localRole}`
       );
@@ -2004,7 +2005,7 @@ const OnlineDebateRoom = (): JSX.Element => {
           );
       }
     }
-  }, [countdown, localRole]);
+  }, [countdown, localRole, isRoomOwner]);
 
   // Clear input fields on phase change
   useEffect(() => {
@@ -4,6 +4,7 @@ import { useAtom } from "jotai";
 import { useDebateWS } from "../hooks/useDebateWS";
 import { ReactionBar } from "../components/ReactionBar";
 import { AnonymousQA } from "../components/AnonymousQA";
+import { SpectatorChat } from "../components/SpectatorChat";
 import {
   debateIdAtom,
   pollStateAtom,
@@ -11,9 +12,11 @@ import {
   reactionsAtom,
   wsStatusAtom,
   presenceAtom,
+  commentaryAtom,
 } from "../atoms/debateAtoms";
 import { Button } from "../components/ui/button";
 import { getAuthToken } from "../utils/auth";
+import { pollService } from "../services/pollService";
 
 type DebateParticipant = {
   id: string;
@@ -31,6 +34,7 @@ export const ViewDebate: React.FC = () => {
   const [reactions] = useAtom(reactionsAtom);
   const [wsStatus] = useAtom(wsStatusAtom);
   const [presence] = useAtom(presenceAtom);
+  const [commentary] = useAtom(commentaryAtom);
   const { sendMessage } = useDebateWS(debateID || null);
 
   // Video streams for spectators
@@ -475,12 +479,13 @@ export const ViewDebate: React.FC = () => {
     setPollError(null);
     setIsCreatingPoll(true);
     try {
-      sendMessage("createPoll", {
-        question,
-        options,
-      });
+      await pollService.createPoll(debateID, { question, options });
       setPollQuestion("");
       setPollOptions(["", ""]);
+    } catch (err) {
+      setPollError(
+        err instanceof Error ? err.message : "Failed to create the poll."
+      );
     } finally {
       setIsCreatingPoll(false);
     }
@@ -640,6 +645,7 @@ export const ViewDebate: React.FC = () => {
                       (count, option) => count + (poll.counts[option] || 0),
                       0
                     );
+                    const votingOpen = !poll.status || poll.status === "open";
                     return (
                       <div
                         key={poll.pollId}
@@ -651,6 +657,8 @@ export const ViewDebate: React.FC = () => {
                           </span>
                           <span className="text-xs text-muted-foreground">
                             {totalVotes} vote{totalVotes === 1 ? "" : "s"}
+                            {poll.status === "closed" && " • Closed"}
+                            {poll.status === "draft" && " • Not open yet"}
                           </span>
                         </div>
                         <div className="mt-3 space-y-2">
@@ -661,7 +669,8 @@ export const ViewDebate: React.FC = () => {
                                 key={option}
                                 type="button"
                                 onClick={() => handleVote(poll.pollId, option)}
-                                className="flex w-full items-center justify-between rounded-lg border border-border px-3 py-2 text-sm hover:bg-primary/5"
+                                disabled={!votingOpen}
+                                className="flex w-full items-center justify-between rounded-lg border border-border px-3 py-2 text-sm hover:bg-primary/5 disabled:cursor-not-allowed disabled:opacity-60"
                               >
                                 <span>{option}</span>
                                 <span className="font-semibold">{count}</span>
@@ -685,6 +694,49 @@ export const ViewDebate: React.FC = () => {
           </div>
 
           <aside className="space-y-4">
+            {commentary.length > 0 && (
+              <div className="rounded-2xl border border-border bg-card/40 p-4 shadow-sm shadow-black/5">
+                <h2 className="text-sm font-semibold uppercase tracking-wide text-muted-foreground pb-3">
+                  Commentary
+                </h2>
+                <div className="space-y-3 max-h-80 overflow-y-auto pr-1">
+                  {commentary
+                    .slice()
+                    .reverse()
+                    .map((entry) => (
+                      <div
+                        key={`${entry.phase}-${entry.timestamp}`}
+                        className="rounded-lg bg-background/60 px-3 py-2 text-sm space-y-1"
+                      >
+                        <div className="flex items-center justify-between text-xs text-muted-foreground">
+                          <span>{entry.phase}</span>
+                          <span>
+                            {entry.leading === "even"
+                              ? "Even"
+                              : `${entry.leading === "for" ? "For" : "Against"} leading`}
+                          </span>
+                        </div>
+                        <p>{entry.summary}</p>
+                        {entry.clashes.length > 0 && (
+                          <ul className="list-disc pl-4 text-xs text-muted-foreground">
+                            {entry.clashes.map((clash, index) => (
+                              <li key={index}>{clash}</li>
+                            ))}
+                          </ul>
+                        )}
+                      </div>
+                    ))}
+                </div>
+              </div>
+            )}
+
+            <div className="rounded-2xl border border-border bg-card/40 p-4 shadow-sm shadow-black/5">
+              <h2 className="text-sm font-semibold uppercase tracking-wide text-muted-foreground pb-3">
+                Spectator chat
+              </h2>
+              <SpectatorChat />
+            </div>
+
             <div className="rounded-2xl border border-border bg-card/40 p-4 shadow-sm shadow-black/5">
               <h2 className="text-sm font-semibold uppercase tracking-wide text-muted-foreground pb-3">
                 Recent reactions
@@ -704,7 +756,14 @@ export const ViewDebate: React.FC = () => {
                         key={index}
                         className="flex items-center justify-between rounded-lg bg-background/60 px-3 py-2 text-sm"
                       >
-                        <span className="text-lg">{reaction.reaction}</span>
+                        <span className="text-lg">
+                          {reaction.reaction}
+                          {reaction.count && reaction.count > 1 && (
+                            <span className="ml-1 text-xs text-muted-foreground">
+                              ×{reaction.count}
+                            </span>
+                          )}
+                        </span>
                         <span className="text-xs text-muted-foreground">
                           {new Date(reaction.timestamp).toLocaleTimeString()}
                         </span>
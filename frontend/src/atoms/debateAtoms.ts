@@ -12,6 +12,8 @@ export interface PollInfo {
   options: string[];
   counts: Record<string, number>;
   voters: number;
+  status?: 'draft' | 'open' | 'closed';
+  closesAt?: number; // Unix milliseconds
 }
 
 // Poll state atom: pollId -> poll info
@@ -60,10 +62,21 @@ export const spectatorHashAtom = atom<string>(() => {
 export const transcriptAtom = atom<string>('');
 
 // Questions atom (array of questions)
-export const questionsAtom = atom<Array<{ qId: string; text: string; spectatorHash: string; timestamp: number }>>([]);
-
-// Reactions atom (array of recent reactions)
-export const reactionsAtom = atom<Array<{ reaction: string; spectatorHash: string; timestamp: number }>>([]);
+export const questionsAtom = atom<
+  Array<{
+    qId: string;
+    text: string;
+    timestamp: number;
+    status?: string;
+    upvotes?: number;
+    asked?: boolean;
+  }>
+>([]);
+
+// Reactions atom (array of recent reactions). Aggregated bursts carry a count.
+export const reactionsAtom = atom<
+  Array<{ reaction: string; spectatorHash?: string; timestamp: number; count?: number }>
+>([]);
 
 // WebSocket connection status atom
 export const wsStatusAtom = atom<'connecting' | 'connected' | 'disconnected' | 'error'>('disconnected');
@@ -74,3 +87,37 @@ export const lastEventIdAtom = atom<string | null>(null);
 // Presence atom (connected spectators count)
 export const presenceAtom = atom<number>(0);
 
+
+export interface ChatMessage {
+  id: string;
+  name: string;
+  text: string;
+  timestamp: number; // Unix milliseconds
+}
+
+export interface ChatSettings {
+  slowModeSeconds: number;
+  blockedWords: string[];
+  blockLinks: boolean;
+}
+
+// Spectator chat: recent messages, the debate's chat rules and the last refusal
+export const chatMessagesAtom = atom<ChatMessage[]>([]);
+export const chatSettingsAtom = atom<ChatSettings>({
+  slowModeSeconds: 0,
+  blockedWords: [],
+  blockLinks: false,
+});
+export const chatErrorAtom = atom<{ error: string; retryAfter?: number } | null>(null);
+
+export interface Commentary {
+  phase: string;
+  summary: string;
+  keyClaims: { side: 'for' | 'against'; claim: string }[];
+  clashes: string[];
+  leading: 'for' | 'against' | 'even';
+  timestamp: number;
+}
+
+// AI commentator analysis, one entry per finished phase
+export const commentaryAtom = atom<Commentary[]>([]);
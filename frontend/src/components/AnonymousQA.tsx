@@ -70,8 +70,8 @@ export const AnonymousQA: React.FC = () => {
       localStorage.setItem('spectatorHash', finalHash);
     }
 
+    // The server assigns the question's ID
     const payload = {
-      qId: crypto.randomUUID(),
       text: questionText.trim(),
       spectatorHash: finalHash,
       timestamp: Date.now(),
@@ -148,9 +148,21 @@ export const AnonymousQA: React.FC = () => {
                 className="text-sm p-2 bg-gray-50 dark:bg-gray-700 rounded border border-gray-200 dark:border-gray-600"
               >
                 <p className="text-gray-900 dark:text-gray-100">{q.text}</p>
-                <p className="text-xs text-gray-500 dark:text-gray-400 mt-1">
-                  {new Date(q.timestamp).toLocaleTimeString()}
-                </p>
+                <div className="flex items-center justify-between mt-1">
+                  <p className="text-xs text-gray-500 dark:text-gray-400">
+                    {new Date(q.timestamp).toLocaleTimeString()}
+                    {q.status === 'approved' && ' · Approved'}
+                    {q.status === 'answered' && ' · Answered'}
+                  </p>
+                  <button
+                    type="button"
+                    onClick={() => sendMessage('upvote_question', { qId: q.qId })}
+                    disabled={q.status === 'answered'}
+                    className="text-xs text-gray-600 dark:text-gray-300 hover:text-blue-600 disabled:opacity-50"
+                  >
+                    ▲ {q.upvotes || 0}
+                  </button>
+                </div>
               </div>
             ))
           )}
@@ -49,7 +49,12 @@ const Reaction: React.FC<ReactionProps> = ({ emoji, onClick }) => {
   );
 };
 
-export const ReactionBar: React.FC = () => {
+interface ReactionBarProps {
+  // Side the reactions support; without one they count for the side speaking
+  side?: 'for' | 'against';
+}
+
+export const ReactionBar: React.FC<ReactionBarProps> = ({ side }) => {
   const [debateId] = useAtom(debateIdAtom);
   const [spectatorHash] = useAtom(spectatorHashAtom);
   const { sendMessage } = useDebateWS(debateId);
@@ -64,6 +69,7 @@ export const ReactionBar: React.FC = () => {
 
     const payload = {
       reaction: emoji,
+      side,
       spectatorHash: storedHash,
       timestamp: Date.now(),
     };
@@ -0,0 +1,84 @@
+import React, { useEffect, useRef, useState } from 'react';
+import { useAtom } from 'jotai';
+import { useDebateWS } from '../hooks/useDebateWS';
+import {
+  debateIdAtom,
+  chatMessagesAtom,
+  chatSettingsAtom,
+  chatErrorAtom,
+} from '../atoms/debateAtoms';
+import { Input } from './ui/input';
+import { Button } from './ui/button';
+
+const MAX_CHAT_LENGTH = 300;
+
+// Chat between spectators. It is carried on the spectator socket only, so debaters never see it.
+export const SpectatorChat: React.FC = () => {
+  const [debateId] = useAtom(debateIdAtom);
+  const [messages] = useAtom(chatMessagesAtom);
+  const [settings] = useAtom(chatSettingsAtom);
+  const [chatError, setChatError] = useAtom(chatErrorAtom);
+  const { sendMessage } = useDebateWS(debateId);
+  const [text, setText] = useState('');
+  const listRef = useRef<HTMLDivElement | null>(null);
+
+  useEffect(() => {
+    if (listRef.current) {
+      listRef.current.scrollTop = listRef.current.scrollHeight;
+    }
+  }, [messages]);
+
+  const handleSubmit = (e: React.FormEvent) => {
+    e.preventDefault();
+    const trimmed = text.trim();
+    if (!debateId || !trimmed) return;
+
+    setChatError(null);
+    sendMessage('chat', { text: trimmed });
+    setText('');
+  };
+
+  if (!debateId) return null;
+
+  return (
+    <div className="flex flex-col gap-3">
+      {settings.slowModeSeconds > 0 && (
+        <p className="text-xs text-muted-foreground">
+          Slow mode: one message every {settings.slowModeSeconds}s
+        </p>
+      )}
+      <div ref={listRef} className="space-y-2 max-h-72 overflow-y-auto pr-1">
+        {messages.length === 0 ? (
+          <p className="text-sm text-muted-foreground">No messages yet.</p>
+        ) : (
+          messages.map((message) => (
+            <div key={message.id} className="rounded-lg bg-background/60 px-3 py-2 text-sm">
+              <span className="font-medium">{message.name}</span>
+              <span className="ml-2 text-xs text-muted-foreground">
+                {new Date(message.timestamp).toLocaleTimeString()}
+              </span>
+              <p className="break-words">{message.text}</p>
+            </div>
+          ))
+        )}
+      </div>
+      {chatError && (
+        <p className="text-xs text-destructive">
+          {chatError.error}
+        </p>
+      )}
+      <form onSubmit={handleSubmit} className="flex gap-2">
+        <Input
+          type="text"
+          value={text}
+          maxLength={MAX_CHAT_LENGTH}
+          onChange={(e) => setText(e.target.value)}
+          placeholder="Chat with other spectators..."
+        />
+        <Button type="submit" disabled={!text.trim()}>
+          Send
+        </Button>
+      </form>
+    </div>
+  );
+};
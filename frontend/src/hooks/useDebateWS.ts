@@ -10,11 +10,18 @@ import {
   presenceAtom,
   spectatorHashAtom,
   lastEventIdAtom,
+  chatMessagesAtom,
+  chatSettingsAtom,
+  chatErrorAtom,
+  commentaryAtom,
+  ChatMessage,
+  Commentary,
   PollInfo,
 } from '../atoms/debateAtoms';
 import ReconnectingWebSocket from 'reconnecting-websocket';
 
 interface Event {
+  id?: string;
   type: string;
   payload: any;
   timestamp: number;
@@ -29,8 +36,14 @@ export const useDebateWS = (debateId: string | null) => {
   const [, setWsStatus] = useAtom(wsStatusAtom);
   const [, setPresence] = useAtom(presenceAtom);
   const [, setLastEventId] = useAtom(lastEventIdAtom);
+  const [, setChatMessages] = useAtom(chatMessagesAtom);
+  const [, setChatSettings] = useAtom(chatSettingsAtom);
+  const [, setChatError] = useAtom(chatErrorAtom);
+  const [, setCommentary] = useAtom(commentaryAtom);
   const [spectatorHash] = useAtom(spectatorHashAtom);
   const wsRef = useRef<ReconnectingWebSocket | null>(null);
+  // Stream ID of the newest event seen, sent on reconnect to replay missed events
+  const lastEventIdRef = useRef<string | null>(null);
 
   useEffect(() => {
     if (!debateId) return;
@@ -81,9 +94,14 @@ export const useDebateWS = (debateId: string | null) => {
       localStorage.setItem('spectatorId', spectatorId);
     }
 
-    const wsUrl = `${protocol}//${host}/ws/debate/${debateId}${
-      spectatorId ? `?spectatorId=${spectatorId}` : ''
-    }`;
+    // Logged-in spectators send their token so their votes count in debates that
+    // require an account
+    const params = new URLSearchParams({ spectatorId });
+    const token = localStorage.getItem('token');
+    if (token) {
+      params.set('token', token);
+    }
+    const wsUrl = `${protocol}//${host}/ws/debate/${debateId}?${params.toString()}`;
 
     const rws = new ReconnectingWebSocket(wsUrl, [], {
       connectionTimeout: 4000,
@@ -106,6 +124,9 @@ export const useDebateWS = (debateId: string | null) => {
         type: 'join',
         payload: {
           spectatorHash: spectatorHashValue,
+          ...(lastEventIdRef.current
+            ? { lastEventId: lastEventIdRef.current }
+            : {}),
         },
       };
       rws.send(JSON.stringify(joinMessage));
@@ -115,8 +136,9 @@ export const useDebateWS = (debateId: string | null) => {
       try {
         const eventData: Event = JSON.parse(event.data);
 
-        if (eventData.type !== 'poll_snapshot' && eventData.timestamp) {
-          setLastEventId(String(eventData.timestamp));
+        if (eventData.id) {
+          lastEventIdRef.current = eventData.id;
+          setLastEventId(eventData.id);
         }
 
         switch (eventData.type) {
@@ -162,6 +184,8 @@ export const useDebateWS = (debateId: string | null) => {
                     typeof poll.voters === 'number'
                       ? poll.voters
                       : Number(poll.voters ?? 0) || 0,
+                  status: poll.status,
+                  closesAt: poll.closesAt,
                 };
                 nextState[pollId] = info;
               });
@@ -254,6 +278,8 @@ export const useDebateWS = (debateId: string | null) => {
                       typeof poll.voters === 'number'
                         ? poll.voters
                         : Number(poll.voters ?? 0) || 0,
+                    status: poll.status,
+                    closesAt: poll.closesAt,
                   },
                 };
               });
@@ -261,17 +287,69 @@ export const useDebateWS = (debateId: string | null) => {
             break;
           }
 
+          case 'poll_opened':
+          case 'poll_closed': {
+            const update = eventData.payload || {};
+            setPollState((prev) => {
+              const existing = prev[update.pollId];
+              if (!existing) return prev;
+              return {
+                ...prev,
+                [update.pollId]: {
+                  ...existing,
+                  status: update.status,
+                  closesAt: update.closesAt,
+                  // Closed polls carry their final results
+                  counts: update.counts ?? existing.counts,
+                  voters: update.voters ?? existing.voters,
+                },
+              };
+            });
+            break;
+          }
+
+          // Approved questions reach everyone; question_received is the asker's own
+          // question while it waits for a moderator
           case 'question':
-            setQuestions((prev) => [
-              ...prev,
-              {
-                qId: eventData.payload.qId,
-                text: eventData.payload.text,
-                spectatorHash: eventData.payload.spectatorHash,
-                timestamp: eventData.payload.timestamp,
-              },
-            ]);
+          case 'question_received': {
+            const question = eventData.payload || {};
+            setQuestions((prev) =>
+              prev.some((q) => q.qId === question.qId)
+                ? prev.map((q) =>
+                    q.qId === question.qId ? { ...q, status: question.status } : q
+                  )
+                : [
+                    ...prev,
+                    {
+                      qId: question.qId,
+                      text: question.text,
+                      timestamp: question.timestamp,
+                      status: question.status,
+                      upvotes: question.upvotes || 0,
+                    },
+                  ]
+            );
             break;
+          }
+
+          case 'question_updated': {
+            const update = eventData.payload || {};
+            setQuestions((prev) =>
+              update.status === 'hidden'
+                ? prev.filter((q) => q.qId !== update.qId)
+                : prev.map((q) =>
+                    q.qId === update.qId
+                      ? {
+                          ...q,
+                          status: update.status,
+                          upvotes: update.upvotes,
+                          asked: q.asked || !!update.asked,
+                        }
+                      : q
+                  )
+            );
+            break;
+          }
 
           case 'reaction':
             setReactions((prev) => [
@@ -284,6 +362,65 @@ export const useDebateWS = (debateId: string | null) => {
             ]);
             break;
 
+          case 'reaction_burst': {
+            // One event per bucket: counts of each reaction received in it
+            const counts: Record<string, number> = eventData.payload.counts || {};
+            const at = eventData.payload.to || Date.now();
+            setReactions((prev) => [
+              ...prev,
+              ...Object.entries(counts).map(([reaction, count]) => ({
+                reaction,
+                count,
+                timestamp: at,
+              })),
+            ].slice(-50));
+            break;
+          }
+
+          case 'chat_history':
+            // Sent on every (re)connect, so it replaces what was shown before
+            setChatMessages(eventData.payload.messages || []);
+            if (eventData.payload.settings) {
+              setChatSettings(eventData.payload.settings);
+            }
+            break;
+
+          case 'chat_message': {
+            const message: ChatMessage = eventData.payload;
+            setChatMessages((prev) =>
+              prev.some((m) => m.id === message.id)
+                ? prev
+                : [...prev, message].slice(-100)
+            );
+            break;
+          }
+
+          case 'chat_deleted': {
+            // One deleted message, or every message from a banned spectator
+            const { messageId, messageIds } = eventData.payload;
+            const removed = new Set<string>(messageIds || (messageId ? [messageId] : []));
+            setChatMessages((prev) => prev.filter((m) => !removed.has(m.id)));
+            break;
+          }
+
+          case 'chat_settings':
+            setChatSettings(eventData.payload);
+            break;
+
+          case 'chat_error':
+            setChatError(eventData.payload);
+            break;
+
+          case 'commentary': {
+            const commentary: Commentary = eventData.payload;
+            setCommentary((prev) =>
+              prev.some((c) => c.phase === commentary.phase && c.timestamp === commentary.timestamp)
+                ? prev
+                : [...prev, commentary].slice(-20)
+            );
+            break;
+          }
+
           case 'presence': {
             const count = eventData.payload.connected || 0;
             setPresence(count);
@@ -331,6 +468,10 @@ export const useDebateWS = (debateId: string | null) => {
     setWsStatus,
     setLastEventId,
     setPresence,
+    setChatMessages,
+    setChatSettings,
+    setChatError,
+    setCommentary,
   ]);
 
   const sendMessage = (type: string, payload: any) => {
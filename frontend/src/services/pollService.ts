@@ -0,0 +1,56 @@
+import { getAuthToken } from '@/utils/auth';
+
+const API_BASE_URL = import.meta.env.VITE_BASE_URL || 'http://localhost:1313';
+
+export interface CreatePollRequest {
+  question: string;
+  options: string[];
+  status?: 'draft' | 'open';
+  durationSeconds?: number;
+}
+
+async function pollRequest(path: string, body?: unknown) {
+  const token = getAuthToken();
+  if (!token) {
+    throw new Error('Authentication token not found');
+  }
+
+  const response = await fetch(`${API_BASE_URL}${path}`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+      Authorization: `Bearer ${token}`,
+    },
+    body: body ? JSON.stringify(body) : undefined,
+  });
+
+  if (!response.ok) {
+    let msg = 'Request failed';
+    try {
+      const errorData = await response.json();
+      msg = errorData.error || errorData.message || response.statusText || msg;
+    } catch {
+      msg = response.statusText || msg;
+    }
+    throw new Error(msg);
+  }
+
+  return response.json();
+}
+
+export const pollService = {
+  // Create a spectator poll (debate host or moderators only)
+  createPoll(debateId: string, data: CreatePollRequest) {
+    return pollRequest(`/debate/${debateId}/polls`, data);
+  },
+
+  // Start voting on a draft poll
+  openPoll(debateId: string, pollId: string) {
+    return pollRequest(`/debate/${debateId}/polls/${pollId}/open`);
+  },
+
+  // Stop voting and freeze the results
+  closePoll(debateId: string, pollId: string) {
+    return pollRequest(`/debate/${debateId}/polls/${pollId}/close`);
+  },
+};
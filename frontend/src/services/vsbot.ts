@@ -16,11 +16,17 @@ export type PhaseTiming = {
 export type DebateRequest = {
   botLevel: string;
   topic: string;
-  history: DebateMessage[];
   botName: string;
   stance: string;
   phaseTimings?: PhaseTiming[]; // For createDebate
-  context?: string; // Added optional context field
+};
+
+// A turn of a bot debate. The server keeps the history, so only the new turn is sent.
+export type DebateTurn = {
+  debateId: string;
+  phase: string;
+  text?: string; // The user's turn; omitted when the bot speaks first
+  context?: string; // What kind of reply the bot owes
 };
 
 export type DebateResponse = {
@@ -33,14 +39,40 @@ export type DebateResponse = {
 };
 
 export type JudgeRequest = {
-  history: DebateMessage[];
-  userId: string;
+  debateId: string;
 };
 
 export type JudgeResponse = {
   result: string;
 };
 
+export type BotSummary = {
+  name: string;
+  level: string;
+  rating: number;
+  description?: string;
+  avatarUrl?: string;
+  quote?: string;
+};
+
+// Function to list the bots available to debate
+export const listBots = async (): Promise<BotSummary[]> => {
+  const token = getAuthToken();
+  const response = await fetch(`${baseURL}/vsbot/bots`, {
+    headers: {
+      ...(token && { Authorization: `Bearer ${token}` }),
+    },
+    credentials: "include",
+  });
+
+  if (!response.ok) {
+    throw new Error("Failed to load bots");
+  }
+
+  const result = await response.json();
+  return result.bots;
+};
+
 // Function to create a new debate
 export const createDebate = async (data: DebateRequest): Promise<DebateResponse> => {
   const token = getAuthToken();
@@ -79,8 +111,8 @@ export const createDebate = async (data: DebateRequest): Promise<DebateResponse>
   };
 };
 
-// Function to send a message in an existing debate
-export const sendDebateMessage = async (data: DebateRequest): Promise<{ response: string }> => {
+// Function to take a turn in an existing debate and get the bot's reply
+export const sendDebateMessage = async (data: DebateTurn): Promise<{ response: string }> => {
   const token = getAuthToken();
   const response = await fetch(`${baseURL}/vsbot/debate`, {
     method: "POST",
@@ -100,6 +132,108 @@ export const sendDebateMessage = async (data: DebateRequest): Promise<{ response
   return { response: result.response }; // Adjusted to return bot's response directly
 };
 
+// Function to save a user's turn the bot does not reply to, such as an answer
+export const recordUserTurn = async (data: DebateTurn): Promise<void> => {
+  const token = getAuthToken();
+  const response = await fetch(`${baseURL}/vsbot/turn`, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+      ...(token && { Authorization: `Bearer ${token}` }),
+    },
+    credentials: "include",
+    body: JSON.stringify(data),
+  });
+
+  if (!response.ok) {
+    const result = await response.json().catch(() => ({}));
+    throw new Error(result.error || "Failed to save your turn");
+  }
+};
+
+// Builds the /ws/vsbot URL on the API host, authenticated with the stored token
+const vsBotStreamURL = (): string => {
+  const protocol = window.location.protocol === "https:" ? "wss:" : "ws:";
+  const apiUrl = import.meta.env.VITE_API_URL;
+  let host = window.location.host;
+  if (apiUrl) {
+    try {
+      host = new URL(apiUrl).host;
+    } catch {
+      host = apiUrl.replace(/^https?:\/\//, "");
+    }
+  }
+  const params = new URLSearchParams();
+  const token = getAuthToken();
+  if (token) {
+    params.set("token", token);
+  }
+  return `${protocol}//${host}/ws/vsbot?${params.toString()}`;
+};
+
+// Function to stream the bot's reply. onChunk receives the text so far as it is written;
+// the promise resolves with the final reply. Aborting the signal stops the reply.
+export const streamDebateMessage = (
+  data: DebateTurn,
+  onChunk: (textSoFar: string) => void,
+  signal?: AbortSignal
+): Promise<string> =>
+  new Promise((resolve, reject) => {
+    if (signal?.aborted) {
+      reject(new DOMException("Aborted", "AbortError"));
+      return;
+    }
+
+    const id = crypto.randomUUID();
+    const ws = new WebSocket(vsBotStreamURL());
+    let text = "";
+    let settled = false;
+
+    const settle = (fn: () => void) => {
+      if (settled) return;
+      settled = true;
+      signal?.removeEventListener("abort", onAbort);
+      fn();
+      ws.close();
+    };
+    const onAbort = () => {
+      if (ws.readyState === WebSocket.OPEN) {
+        ws.send(JSON.stringify({ type: "cancel", id }));
+      }
+      settle(() => reject(new DOMException("Aborted", "AbortError")));
+    };
+    signal?.addEventListener("abort", onAbort);
+
+    ws.onopen = () => {
+      ws.send(JSON.stringify({ type: "message", id, ...data }));
+    };
+    ws.onmessage = (event) => {
+      const msg = JSON.parse(event.data);
+      if (msg.id && msg.id !== id) return;
+      switch (msg.type) {
+        case "chunk":
+          text += msg.text || "";
+          onChunk(text);
+          break;
+        case "done":
+          settle(() => resolve(msg.text || text));
+          break;
+        case "cancelled":
+          settle(() => reject(new DOMException("Aborted", "AbortError")));
+          break;
+        case "error":
+          settle(() => reject(new Error(msg.error || "Failed to stream debate message")));
+          break;
+      }
+    };
+    ws.onerror = () => {
+      settle(() => reject(new Error("Failed to stream debate message")));
+    };
+    ws.onclose = () => {
+      settle(() => reject(new Error("Connection closed before the reply finished")));
+    };
+  });
+
 // Function to judge a debate
 export const judgeDebate = async (data: JudgeRequest): Promise<JudgeResponse> => {
   const token = getAuthToken();